@@ -0,0 +1,173 @@
+package vast
+
+import (
+	"encoding/base64"
+	"errors"
+	"regexp"
+)
+
+var ErrEncryptPrice = errors.New("cannot encrypt price")
+
+// AuctionMacro is an OpenRTB substitution macro, e.g. `${AUCTION_PRICE}`. Unlike the macros of the VAST
+// specification, they are replaced by the exchange or the SSP before the VAST is sent to the player.
+type AuctionMacro string
+
+const (
+	AuctionIDMacro         AuctionMacro = "AUCTION_ID"
+	AuctionBidIDMacro      AuctionMacro = "AUCTION_BID_ID"
+	AuctionImpIDMacro      AuctionMacro = "AUCTION_IMP_ID"
+	AuctionSeatIDMacro     AuctionMacro = "AUCTION_SEAT_ID"
+	AuctionAdIDMacro       AuctionMacro = "AUCTION_AD_ID"
+	AuctionPriceMacro      AuctionMacro = "AUCTION_PRICE"
+	AuctionCurrencyMacro   AuctionMacro = "AUCTION_CURRENCY"
+	AuctionMBRMacro        AuctionMacro = "AUCTION_MBR"
+	AuctionLossMacro       AuctionMacro = "AUCTION_LOSS"
+	AuctionMinToWinMacro   AuctionMacro = "AUCTION_MIN_TO_WIN"
+	AuctionMultiplierMacro AuctionMacro = "AUCTION_MULTIPLIER"
+	AuctionImpTSMacro      AuctionMacro = "AUCTION_IMP_TS"
+)
+
+var auctionMacroPattern = regexp.MustCompile(`\$\{(AUCTION_[A-Z_]+)(:B64)?}`)
+
+// PriceEncrypter encrypts the clearing price, e.g. with the price encryption scheme agreed with a bidder.
+type PriceEncrypter interface {
+	EncryptPrice(price string) (string, error)
+}
+
+// PriceEncrypterFunc is a function implementing PriceEncrypter.
+type PriceEncrypterFunc func(price string) (string, error)
+
+func (f PriceEncrypterFunc) EncryptPrice(price string) (string, error) {
+	return f(price)
+}
+
+// AuctionMacroExpander replaces OpenRTB auction macros with their values. Macros without value are kept, so that they
+// can be replaced later. A macro with the suffix `:B64`, e.g. `${AUCTION_PRICE:B64}`, is replaced with the URL-safe
+// Base64 encoding of the value. If PriceEncrypter is set, the value of AuctionPriceMacro is encrypted before it is
+// inserted.
+//
+// Values are inserted as they are, i.e. they are neither URL nor XML encoded.
+type AuctionMacroExpander struct {
+	Values         map[AuctionMacro]string
+	PriceEncrypter PriceEncrypter
+}
+
+// Expand replaces the macros of a string, e.g. of a win notice URL.
+func (e *AuctionMacroExpander) Expand(s string) (string, error) {
+	var err error
+
+	expanded := auctionMacroPattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := auctionMacroPattern.FindStringSubmatch(match)
+		macro := AuctionMacro(groups[1])
+
+		value, ok := e.Values[macro]
+		if !ok || err != nil {
+			return match
+		}
+
+		if macro == AuctionPriceMacro && e.PriceEncrypter != nil {
+			var encryptErr error
+			if value, encryptErr = e.PriceEncrypter.EncryptPrice(value); encryptErr != nil {
+				err = errors.Join(ErrEncryptPrice, encryptErr)
+				return match
+			}
+		}
+
+		if groups[2] != "" {
+			value = base64.URLEncoding.EncodeToString([]byte(value))
+		}
+
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return expanded, nil
+}
+
+// ExpandBytes replaces the macros of a raw document, e.g. of the `adm` field of a bid. Unlike ExpandVAST, it also
+// replaces macros in the Pricing element, whose value cannot be parsed before.
+func (e *AuctionMacroExpander) ExpandBytes(data []byte) ([]byte, error) {
+	expanded, err := e.Expand(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(expanded), nil
+}
+
+// ExpandVAST replaces the macros of all URLs, AdParameters and HTMLResource payloads of the VAST.
+// The VAST is not modified if an error occurs.
+func (e *AuctionMacroExpander) ExpandVAST(m *VAST) error {
+	type replacement struct {
+		value    *string
+		expanded string
+	}
+
+	var (
+		replacements []replacement
+		err          error
+	)
+
+	expand := func(value *string) {
+		if err != nil {
+			return
+		}
+
+		var expanded string
+		if expanded, err = e.Expand(*value); err == nil && expanded != *value {
+			replacements = append(replacements, replacement{value: value, expanded: expanded})
+		}
+	}
+
+	visitURLs(m, func(_ URL, _ Path, value *string) bool {
+		expand(value)
+		return true
+	})
+
+	adParameters := func(adParameters *AdParameters) {
+		if adParameters != nil {
+			expand(&adParameters.Value)
+		}
+	}
+
+	htmlResources := func(resources []CData) {
+		for i := range resources {
+			expand(&resources[i].Value)
+		}
+	}
+
+	_ = Walk(m, Visitor{
+		LinearInLine: func(linear *LinearInLine, _ Path) error {
+			adParameters(linear.AdParameters)
+			return nil
+		},
+		Icon: func(icon *Icon, _ Path) error {
+			htmlResources(icon.HTMLResource)
+			return nil
+		},
+		NonLinear: func(nonLinear *NonLinearAdInLine, _ Path) error {
+			adParameters(nonLinear.AdParameters)
+			htmlResources(nonLinear.HTMLResource)
+
+			return nil
+		},
+		Companion: func(companion *CompanionAd, _ Path) error {
+			adParameters(companion.AdParameters)
+			htmlResources(companion.HTMLResource)
+
+			return nil
+		},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, r := range replacements {
+		*r.value = r.expanded
+	}
+
+	return nil
+}
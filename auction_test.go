@@ -0,0 +1,112 @@
+package vast_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestAuctionMacroExpander_Expand(t *testing.T) {
+	expander := &vast.AuctionMacroExpander{Values: map[vast.AuctionMacro]string{
+		vast.AuctionIDMacro:       "a1",
+		vast.AuctionPriceMacro:    "1.23",
+		vast.AuctionCurrencyMacro: "USD",
+	}}
+
+	got, err := expander.Expand("https://example.com/win?id=${AUCTION_ID}&price=${AUCTION_PRICE}&b64=${AUCTION_PRICE:B64}&cur=${AUCTION_CURRENCY}&imp=${AUCTION_IMP_ID}&vast=[TIMESTAMP]")
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if want := "https://example.com/win?id=a1&price=1.23&b64=MS4yMw==&cur=USD&imp=${AUCTION_IMP_ID}&vast=[TIMESTAMP]"; got != want {
+		t.Errorf("wrong expansion: %s", got)
+	}
+}
+
+func TestAuctionMacroExpander_Expand_encrypted(t *testing.T) {
+	expander := &vast.AuctionMacroExpander{
+		Values: map[vast.AuctionMacro]string{vast.AuctionPriceMacro: "1.23", vast.AuctionMBRMacro: "0.5"},
+		PriceEncrypter: vast.PriceEncrypterFunc(func(price string) (string, error) {
+			return "enc-" + price, nil
+		}),
+	}
+
+	got, err := expander.Expand("${AUCTION_PRICE}/${AUCTION_MBR}")
+	if err != nil || got != "enc-1.23/0.5" {
+		t.Errorf("wrong expansion: %s", got)
+	}
+
+	expander.PriceEncrypter = vast.PriceEncrypterFunc(func(string) (string, error) {
+		return "", errors.New("missing key")
+	})
+
+	if _, err := expander.Expand("${AUCTION_PRICE}/${AUCTION_PRICE}"); !errors.Is(err, vast.ErrEncryptPrice) {
+		t.Error("expected error")
+	}
+
+	if _, err := expander.ExpandBytes([]byte("${AUCTION_PRICE}")); !errors.Is(err, vast.ErrEncryptPrice) {
+		t.Error("expected error")
+	}
+}
+
+func TestAuctionMacroExpander_ExpandBytes(t *testing.T) {
+	expander := &vast.AuctionMacroExpander{Values: map[vast.AuctionMacro]string{vast.AuctionPriceMacro: "2.5"}}
+
+	got, err := expander.ExpandBytes([]byte(`<VAST version="4.2"><Ad><InLine><Pricing model="CPM" currency="USD">${AUCTION_PRICE}</Pricing></InLine></Ad></VAST>`))
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	testVAST, err := vast.Read(io.NopCloser(bytes.NewReader(got)))
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if testVAST.Ad[0].InLine.Pricing.Value != 2.5 {
+		t.Errorf("wrong price: %v", testVAST.Ad[0].InLine.Pricing.Value)
+	}
+}
+
+func TestAuctionMacroExpander_ExpandVAST(t *testing.T) {
+	testVAST := trackerVAST()
+	testVAST.Ad[0].InLine.Impression[0].Value = "https://example.com/impression?price=${AUCTION_PRICE}"
+	testVAST.Ad[0].InLine.Creatives.Creative[0].Linear.AdParameters = &vast.AdParameters{Value: `{"auction":"${AUCTION_ID}"}`}
+	testVAST.Ad[0].InLine.Creatives.Creative[0].Linear.Icons.Icon[0].HTMLResource = []vast.CData{{Value: "<img src='https://example.com/${AUCTION_ID}'>"}}
+	testVAST.Ad[0].InLine.Creatives.Creative[1].NonLinearAds.NonLinear[0].HTMLResource = []vast.CData{{Value: "${AUCTION_ID}"}}
+	testVAST.Ad[0].InLine.Creatives.Creative[2].CompanionAds.Companion[0].AdParameters = &vast.AdParameters{Value: "${AUCTION_ID:B64}"}
+
+	expected := trackerVAST()
+	expected.Ad[0].InLine.Impression[0].Value = "https://example.com/impression?price=0.75"
+	expected.Ad[0].InLine.Creatives.Creative[0].Linear.AdParameters = &vast.AdParameters{Value: `{"auction":"a1"}`}
+	expected.Ad[0].InLine.Creatives.Creative[0].Linear.Icons.Icon[0].HTMLResource = []vast.CData{{Value: "<img src='https://example.com/a1'>"}}
+	expected.Ad[0].InLine.Creatives.Creative[1].NonLinearAds.NonLinear[0].HTMLResource = []vast.CData{{Value: "a1"}}
+	expected.Ad[0].InLine.Creatives.Creative[2].CompanionAds.Companion[0].AdParameters = &vast.AdParameters{Value: "YTE="}
+
+	expander := &vast.AuctionMacroExpander{Values: map[vast.AuctionMacro]string{vast.AuctionIDMacro: "a1", vast.AuctionPriceMacro: "0.75"}}
+	if err := expander.ExpandVAST(testVAST); err != nil {
+		t.Error("unexpected error")
+	}
+
+	if diff := cmp.Diff(expected, testVAST); diff != "" {
+		t.Errorf("wrong VAST: %s", diff)
+	}
+
+	expander.PriceEncrypter = vast.PriceEncrypterFunc(func(string) (string, error) {
+		return "", errors.New("missing key")
+	})
+
+	testVAST.Ad[0].InLine.Error = []vast.CData{{Value: "https://example.com/error?id=${AUCTION_ID}"}, {Value: "https://example.com/error?price=${AUCTION_PRICE}"}}
+	testVAST.Ad[0].InLine.Creatives.Creative[0].Linear.AdParameters.Value = "${AUCTION_ID}"
+
+	if err := expander.ExpandVAST(testVAST); !errors.Is(err, vast.ErrEncryptPrice) {
+		t.Error("expected error")
+	}
+
+	if !strings.HasSuffix(testVAST.Ad[0].InLine.Error[0].Value, "${AUCTION_ID}") || testVAST.Ad[0].InLine.Creatives.Creative[0].Linear.AdParameters.Value != "${AUCTION_ID}" {
+		t.Error("VAST modified")
+	}
+}
@@ -0,0 +1,110 @@
+package vast
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+var ErrInvalidLanguage = errors.New("invalid language")
+
+// LanguageTag parses the language of the closed caption file as BCP 47 language tag, e.g. `en` or `zh-TW`.
+func (c *ClosedCaptionFile) LanguageTag() (language.Tag, error) {
+	tag, err := language.Parse(strings.TrimSpace(c.Language))
+	if err != nil {
+		return language.Und, fmt.Errorf("%w %q: %w", ErrInvalidLanguage, c.Language, err)
+	}
+
+	return tag, nil
+}
+
+// ValidLanguage reports whether a language code is a well-formed and known BCP 47 language tag.
+func ValidLanguage(code string) bool {
+	_, err := language.Parse(strings.TrimSpace(code))
+	return err == nil
+}
+
+// Select returns the closed caption file whose language matches the preferred languages best, which are given as
+// BCP 47 language tags in order of preference. The language matching of golang.org/x/text/language is used, i.e.
+// `en-US` matches `en`, and `zh-Hant` matches `zh-TW`. Only files with one of the MIME types are considered, unless
+// no MIME types are given; files with a MIME type listed earlier are preferred among files with the same language.
+// Files with an invalid language are ignored. It returns nil if no file matches.
+func (c *ClosedCaptionFiles) Select(languages []string, mimeTypes ...string) *ClosedCaptionFile {
+	if c == nil {
+		return nil
+	}
+
+	var (
+		tags       []language.Tag
+		candidates = map[language.Tag][]int{}
+	)
+
+	for i := range c.ClosedCaptionFile {
+		if captionTypeRank(c.ClosedCaptionFile[i].Type, mimeTypes) < 0 {
+			continue
+		}
+
+		tag, err := c.ClosedCaptionFile[i].LanguageTag()
+		if err != nil {
+			continue
+		}
+
+		if _, ok := candidates[tag]; !ok {
+			tags = append(tags, tag)
+		}
+
+		candidates[tag] = append(candidates[tag], i)
+	}
+
+	var preferred []language.Tag
+
+	for _, code := range languages {
+		if tag, err := language.Parse(strings.TrimSpace(code)); err == nil {
+			preferred = append(preferred, tag)
+		}
+	}
+
+	if len(tags) == 0 || len(preferred) == 0 {
+		return nil
+	}
+
+	_, index, confidence := language.NewMatcher(tags).Match(preferred...)
+	if confidence == language.No {
+		return nil
+	}
+
+	var selected *ClosedCaptionFile
+
+	for _, i := range candidates[tags[index]] {
+		file := &c.ClosedCaptionFile[i]
+		if selected == nil || captionTypeRank(file.Type, mimeTypes) < captionTypeRank(selected.Type, mimeTypes) {
+			selected = file
+		}
+	}
+
+	return selected
+}
+
+// captionTypeRank returns the position of the MIME type in the accepted types, 0 if all types are accepted, or -1 if
+// the type is not accepted.
+func captionTypeRank(mimeType string, accepted []string) int {
+	if len(accepted) == 0 {
+		return 0
+	}
+
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return -1
+	}
+
+	for i, acceptedType := range accepted {
+		if strings.EqualFold(mediaType, strings.TrimSpace(acceptedType)) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,86 @@
+package vast_test
+
+import (
+	"errors"
+	"go.eigsys.de/go-vast"
+	"testing"
+)
+
+func TestClosedCaptionFiles_Select(t *testing.T) {
+	files := &vast.ClosedCaptionFiles{ClosedCaptionFile: []vast.ClosedCaptionFile{
+		{Value: "https://example.com/en.srt", Type: "text/srt", Language: "en"},
+		{Value: "https://example.com/en.vtt", Type: "text/vtt", Language: "en"},
+		{Value: "https://example.com/fr.srt", Type: "text/srt", Language: "fr"},
+		{Value: "https://example.com/zh.vtt", Type: "text/vtt", Language: "zh-TW"},
+		{Value: "https://example.com/de.ttml", Type: "application/ttml+xml; charset=utf-8", Language: "de"},
+		{Value: "https://example.com/invalid.vtt", Type: "text/vtt", Language: "english"},
+		{Value: "https://example.com/untyped.vtt", Type: "", Language: "es"},
+	}}
+
+	tests := []struct {
+		languages []string
+		mimeTypes []string
+		expected  string
+	}{
+		{[]string{"en-US"}, nil, "https://example.com/en.srt"},
+		{[]string{"en-GB"}, []string{"text/vtt", "text/srt"}, "https://example.com/en.vtt"},
+		{[]string{"fr-CA", "en"}, []string{"text/vtt"}, "https://example.com/en.vtt"},
+		{[]string{"zh-Hant"}, nil, "https://example.com/zh.vtt"},
+		{[]string{"invalid!", "de-AT"}, []string{"application/ttml+xml"}, "https://example.com/de.ttml"},
+		{[]string{"es"}, nil, "https://example.com/untyped.vtt"},
+		{[]string{"es"}, []string{"text/vtt"}, ""},
+		{[]string{"ja"}, nil, ""},
+		{nil, nil, ""},
+		{[]string{"en"}, []string{"text/plain"}, ""},
+	}
+
+	for _, testCase := range tests {
+		selected := files.Select(testCase.languages, testCase.mimeTypes...)
+
+		var got string
+		if selected != nil {
+			got = selected.Value
+		}
+
+		if got != testCase.expected {
+			t.Errorf("wrong file for %v %v: %q", testCase.languages, testCase.mimeTypes, got)
+		}
+	}
+
+	var nilFiles *vast.ClosedCaptionFiles
+	if nilFiles.Select([]string{"en"}) != nil {
+		t.Error("unexpected file")
+	}
+}
+
+func TestClosedCaptionFile_LanguageTag(t *testing.T) {
+	file := vast.ClosedCaptionFile{Language: " zh-TW "}
+
+	tag, err := file.LanguageTag()
+	if err != nil || tag.String() != "zh-TW" {
+		t.Errorf("wrong tag: %s", tag)
+	}
+
+	file.Language = "en_US!"
+	if _, err := file.LanguageTag(); !errors.Is(err, vast.ErrInvalidLanguage) {
+		t.Error("expected error")
+	}
+}
+
+func TestValidLanguage(t *testing.T) {
+	tests := map[string]bool{
+		"en":      true,
+		"en-US":   true,
+		"zh-Hant": true,
+		"zh-CH":   true,
+		"english": false,
+		"":        false,
+		"e":       false,
+	}
+
+	for code, expected := range tests {
+		if got := vast.ValidLanguage(code); got != expected {
+			t.Errorf("wrong result for %q: %v", code, got)
+		}
+	}
+}
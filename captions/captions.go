@@ -0,0 +1,225 @@
+// Package captions parses and converts the closed caption files referenced by ClosedCaptionFile elements in the
+// WebVTT, SRT and TTML formats.
+//
+// Only the timing and the text of cues are converted. Styling, positioning, regions and metadata are dropped, and
+// markup within the text is removed.
+package captions
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported caption format")
+	ErrParseCaptions     = errors.New("cannot parse captions")
+	ErrWriteCaptions     = errors.New("cannot write captions")
+)
+
+// Format is a caption format, identified by its MIME type.
+type Format string
+
+const (
+	WebVTTFormat Format = "text/vtt"
+	SRTFormat    Format = "text/srt"
+	TTMLFormat   Format = "application/ttml+xml"
+)
+
+var formatAliases = map[string]Format{
+	"text/vtt":             WebVTTFormat,
+	"text/webvtt":          WebVTTFormat,
+	"text/srt":             SRTFormat,
+	"application/srt":      SRTFormat,
+	"application/x-subrip": SRTFormat,
+	"application/ttml+xml": TTMLFormat,
+	"application/ttaf+xml": TTMLFormat,
+	"text/ttml":            TTMLFormat,
+}
+
+// FormatOf returns the format of a MIME type, e.g. the type of a ClosedCaptionFile. Common aliases like
+// `application/x-subrip` are recognized.
+func FormatOf(mimeType string) (Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return "", false
+	}
+
+	format, ok := formatAliases[mediaType]
+
+	return format, ok
+}
+
+// Cue is a caption displayed during an interval.
+type Cue struct {
+	// ID is the identifier of a WebVTT cue or the number of an SRT subtitle.
+	ID    string
+	Start time.Duration
+	End   time.Duration
+	// Text is the plain text of the cue, lines are separated by `\n`.
+	Text string
+}
+
+// Track is a sequence of cues.
+type Track struct {
+	Cues []Cue
+}
+
+// Parse reads captions in the format.
+func Parse(reader io.Reader, format Format) (*Track, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Join(ErrParseCaptions, err)
+	}
+
+	var track *Track
+
+	switch format {
+	case WebVTTFormat:
+		track, err = parseWebVTT(normalizeLines(data))
+	case SRTFormat:
+		track, err = parseSRT(normalizeLines(data))
+	case TTMLFormat:
+		track, err = parseTTML(data)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+	}
+
+	if err != nil {
+		return nil, errors.Join(ErrParseCaptions, err)
+	}
+
+	return track, nil
+}
+
+// Write writes the captions in the format.
+func (t *Track) Write(writer io.Writer, format Format) error {
+	var data []byte
+
+	switch format {
+	case WebVTTFormat:
+		data = t.webVTT()
+	case SRTFormat:
+		data = t.srt()
+	case TTMLFormat:
+		data = t.ttml()
+	default:
+		return fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return errors.Join(ErrWriteCaptions, err)
+	}
+
+	return nil
+}
+
+// Convert reads captions in one format and writes them in another format.
+func Convert(reader io.Reader, from Format, writer io.Writer, to Format) error {
+	track, err := Parse(reader, from)
+	if err != nil {
+		return err
+	}
+
+	return track.Write(writer, to)
+}
+
+// normalizeLines removes a byte order mark and converts line endings to `\n`.
+func normalizeLines(data []byte) string {
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	return strings.ReplaceAll(text, "\r", "\n")
+}
+
+// blocks splits text into blocks separated by blank lines.
+func blocks(text string) [][]string {
+	var (
+		result  [][]string
+		current []string
+	)
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				result = append(result, current)
+				current = nil
+			}
+
+			continue
+		}
+
+		current = append(current, line)
+	}
+
+	if len(current) > 0 {
+		result = append(result, current)
+	}
+
+	return result
+}
+
+var timestampPattern = regexp.MustCompile(`^(?:(\d+):)?([0-5]\d):([0-5]\d)[.,](\d{3})$`)
+
+// parseTimestamp parses a timestamp like `01:02:03.456`, `02:03.456` or `01:02:03,456`.
+func parseTimestamp(value string) (time.Duration, error) {
+	match := timestampPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+
+	var parts [4]time.Duration
+
+	for i, part := range match[1:] {
+		for _, digit := range part {
+			parts[i] = parts[i]*10 + time.Duration(digit-'0')
+		}
+	}
+
+	return parts[0]*time.Hour + parts[1]*time.Minute + parts[2]*time.Second + parts[3]*time.Millisecond, nil
+}
+
+// formatTimestamp formats a timestamp as `hh:mm:ss` followed by the separator and the milliseconds.
+func formatTimestamp(value time.Duration, separator string) string {
+	value = max(value, 0).Round(time.Millisecond)
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", value/time.Hour, value%time.Hour/time.Minute,
+		value%time.Minute/time.Second, separator, value%time.Second/time.Millisecond)
+}
+
+// parseTiming parses a timing line like `00:00:01.000 --> 00:00:02.000`. Cue settings after the end are ignored.
+func parseTiming(line string) (time.Duration, time.Duration, error) {
+	start, rest, ok := strings.Cut(line, "-->")
+	fields := strings.Fields(rest)
+
+	if !ok || len(fields) == 0 {
+		return 0, 0, fmt.Errorf("invalid timing %q", line)
+	}
+
+	startTime, err := parseTimestamp(start)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	endTime, err := parseTimestamp(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return startTime, endTime, nil
+}
+
+var (
+	markupPattern  = regexp.MustCompile(`<[^>]*>`)
+	entityReplacer = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&nbsp;", "\u00A0", "&lrm;", "\u200E", "&rlm;", "\u200F", "&amp;", "&")
+	textEscaper    = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// plainText removes markup like `<i>` and `<v Speaker>` from cue text and decodes entities.
+func plainText(lines []string) string {
+	return entityReplacer.Replace(markupPattern.ReplaceAllString(strings.Join(lines, "\n"), ""))
+}
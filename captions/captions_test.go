@@ -0,0 +1,202 @@
+package captions_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast/captions"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+var expectedCues = []captions.Cue{
+	{ID: "intro", Start: time.Second, End: 2500 * time.Millisecond, Text: "Hello & welcome"},
+	{Start: time.Hour + 2*time.Minute + 3*time.Second + 400*time.Millisecond, End: time.Hour + 2*time.Minute + 5*time.Second, Text: "Two\nlines"},
+}
+
+const (
+	testWebVTT = "\uFEFFWEBVTT - Example\r\n\r\nNOTE a comment\r\n\r\nSTYLE\r\n::cue { color: red }\r\n\r\n" +
+		"intro\r\n00:01.000 --> 00:02.500 align:start\r\n<v Speaker>Hello &amp; <b>welcome</b></v>\r\n\r\n" +
+		"01:02:03.400 --> 01:02:05.000\r\nTwo\r\n<i>lines</i>\r\n"
+	testSRT = "intro\n00:00:01,000 --> 00:00:02,500\nHello & <b>welcome</b>\n\n" +
+		"\n2\n01:02:03,400 --> 01:02:05,000\nTwo\nlines\n"
+	testTTML = `<?xml version="1.0" encoding="UTF-8"?>
+<tt xmlns="http://www.w3.org/ns/ttml" xmlns:ttp="http://www.w3.org/ns/ttml#parameter" ttp:frameRate="25" ttp:tickRate="10">
+  <body>
+    <div begin="1s">
+      <p xml:id="intro" begin="0s" end="15t">Hello &amp;
+        <span>welcome</span></p>
+    </div>
+    <div>
+      <p begin="01:02:03:10" dur="1600ms">Two<br/> lines </p>
+    </div>
+  </body>
+</tt>`
+)
+
+func TestParse(t *testing.T) {
+	tests := map[captions.Format]string{
+		captions.WebVTTFormat: testWebVTT,
+		captions.SRTFormat:    testSRT,
+		captions.TTMLFormat:   testTTML,
+	}
+
+	for format, input := range tests {
+		track, err := captions.Parse(strings.NewReader(input), format)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", format, err)
+			continue
+		}
+
+		expected := expectedCues
+		if format == captions.SRTFormat {
+			expected = append([]captions.Cue{}, expectedCues...)
+			expected[1].ID = "2"
+		}
+
+		if diff := cmp.Diff(expected, track.Cues); diff != "" {
+			t.Errorf("wrong cues for %s: %s", format, diff)
+		}
+	}
+}
+
+func TestParse_error(t *testing.T) {
+	tests := map[string]struct {
+		format captions.Format
+		input  string
+	}{
+		"unsupported":           {"text/plain", ""},
+		"missing signature":     {captions.WebVTTFormat, "00:01.000 --> 00:02.000\nText"},
+		"empty WebVTT":          {captions.WebVTTFormat, ""},
+		"missing cue timing":    {captions.WebVTTFormat, "WEBVTT\n\nid"},
+		"invalid cue timing":    {captions.WebVTTFormat, "WEBVTT\n\n00:01.000 -> 00:02.000\nText"},
+		"missing SRT timing":    {captions.SRTFormat, "1"},
+		"invalid start":         {captions.SRTFormat, "1\n00:00:1,000 --> 00:00:02,000\nText"},
+		"invalid SRT end":       {captions.SRTFormat, "1\n00:00:01,000 --> 00:00:2\nText"},
+		"missing end":           {captions.SRTFormat, "1\n00:00:01,000 -->\nText"},
+		"invalid XML":           {captions.TTMLFormat, "<tt><body>"},
+		"wrong root":            {captions.TTMLFormat, "<html/>"},
+		"no root":               {captions.TTMLFormat, ""},
+		"invalid frame rate":    {captions.TTMLFormat, `<tt frameRate="x"/>`},
+		"invalid begin":         {captions.TTMLFormat, `<tt><body begin="soon"/></tt>`},
+		"invalid TTML end":      {captions.TTMLFormat, `<tt><body><p begin="1s" end="later">x</p></body></tt>`},
+		"missing paragraph end": {captions.TTMLFormat, `<tt><body><p begin="1s">x</p></body></tt>`},
+	}
+
+	for name, testCase := range tests {
+		if _, err := captions.Parse(strings.NewReader(testCase.input), testCase.format); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+
+	if _, err := captions.Parse(strings.NewReader(""), "text/plain"); !errors.Is(err, captions.ErrUnsupportedFormat) {
+		t.Error("expected error")
+	}
+
+	if _, err := captions.Parse(iotest.ErrReader(errors.New("read error")), captions.SRTFormat); !errors.Is(err, captions.ErrParseCaptions) {
+		t.Error("expected error")
+	}
+}
+
+func TestTrack_Write(t *testing.T) {
+	track := &captions.Track{Cues: append(expectedCues, captions.Cue{ID: "3 --> 4", Start: -time.Second, End: time.Second, Text: "a < b\n\nc"})}
+
+	tests := map[captions.Format]string{
+		captions.WebVTTFormat: "WEBVTT\n\nintro\n00:00:01.000 --> 00:00:02.500\nHello &amp; welcome\n\n" +
+			"01:02:03.400 --> 01:02:05.000\nTwo\nlines\n\n00:00:00.000 --> 00:00:01.000\na &lt; b\nc\n",
+		captions.SRTFormat: "1\n00:00:01,000 --> 00:00:02,500\nHello & welcome\n\n" +
+			"2\n01:02:03,400 --> 01:02:05,000\nTwo\nlines\n\n3\n00:00:00,000 --> 00:00:01,000\na < b\nc\n",
+		captions.TTMLFormat: `<?xml version="1.0" encoding="UTF-8"?>
+<tt xmlns="http://www.w3.org/ns/ttml">
+  <body>
+    <div>
+      <p begin="00:00:01.000" end="00:00:02.500" xml:id="intro">Hello &amp; welcome</p>
+      <p begin="01:02:03.400" end="01:02:05.000">Two<br/>lines</p>
+      <p begin="00:00:00.000" end="00:00:01.000">a &lt; b<br/><br/>c</p>
+    </div>
+  </body>
+</tt>
+`,
+	}
+
+	for format, expected := range tests {
+		var buffer bytes.Buffer
+		if err := track.Write(&buffer, format); err != nil {
+			t.Errorf("unexpected error for %s", format)
+		}
+
+		if diff := cmp.Diff(expected, buffer.String()); diff != "" {
+			t.Errorf("wrong output for %s: %s", format, diff)
+		}
+	}
+
+	if err := track.Write(&bytes.Buffer{}, "text/plain"); !errors.Is(err, captions.ErrUnsupportedFormat) {
+		t.Error("expected error")
+	}
+
+	if err := track.Write(failingWriter{}, captions.SRTFormat); !errors.Is(err, captions.ErrWriteCaptions) {
+		t.Error("expected error")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+func TestConvert(t *testing.T) {
+	formats := []captions.Format{captions.WebVTTFormat, captions.SRTFormat, captions.TTMLFormat}
+	inputs := map[captions.Format]string{
+		captions.WebVTTFormat: testWebVTT,
+		captions.SRTFormat:    testSRT,
+		captions.TTMLFormat:   testTTML,
+	}
+
+	for _, from := range formats {
+		for _, to := range formats {
+			var converted bytes.Buffer
+			if err := captions.Convert(strings.NewReader(inputs[from]), from, &converted, to); err != nil {
+				t.Errorf("unexpected error for %s to %s: %v", from, to, err)
+				continue
+			}
+
+			track, err := captions.Parse(&converted, to)
+			if err != nil {
+				t.Errorf("unexpected error for %s to %s: %v", from, to, err)
+				continue
+			}
+
+			for i, cue := range track.Cues {
+				if cue.Start != expectedCues[i].Start || cue.End != expectedCues[i].End || cue.Text != expectedCues[i].Text {
+					t.Errorf("wrong cue for %s to %s: %v", from, to, cue)
+				}
+			}
+		}
+	}
+
+	if err := captions.Convert(strings.NewReader(""), "text/plain", &bytes.Buffer{}, captions.SRTFormat); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]captions.Format{
+		"text/vtt":                    captions.WebVTTFormat,
+		"text/VTT; charset=utf-8":     captions.WebVTTFormat,
+		"application/x-subrip":        captions.SRTFormat,
+		"text/srt":                    captions.SRTFormat,
+		"application/ttml+xml":        captions.TTMLFormat,
+		"text/plain":                  "",
+		"":                            "",
+		"application/ttml+xml; a=\"b": "",
+	}
+
+	for mimeType, expected := range tests {
+		if format, ok := captions.FormatOf(mimeType); format != expected || ok != (expected != "") {
+			t.Errorf("wrong format for %q: %q", mimeType, format)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package captions
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// parseSRT parses a SubRip file.
+func parseSRT(text string) (*Track, error) {
+	track := &Track{}
+
+	for _, block := range blocks(text) {
+		var id string
+		if !strings.Contains(block[0], "-->") {
+			id, block = strings.TrimSpace(block[0]), block[1:]
+		}
+
+		if len(block) == 0 {
+			return nil, errors.New("missing subtitle timing")
+		}
+
+		start, end, err := parseTiming(block[0])
+		if err != nil {
+			return nil, err
+		}
+
+		track.Cues = append(track.Cues, Cue{ID: id, Start: start, End: end, Text: plainText(block[1:])})
+	}
+
+	return track, nil
+}
+
+// srt writes the cues numbered from 1, since SubRip requires sequential numbers.
+func (t *Track) srt() []byte {
+	var builder strings.Builder
+
+	for i, cue := range t.Cues {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		builder.WriteString(strconv.Itoa(i+1) + "\n")
+		builder.WriteString(formatTimestamp(cue.Start, ",") + " --> " + formatTimestamp(cue.End, ",") + "\n")
+		builder.WriteString(cueLines(cue.Text))
+	}
+
+	return []byte(builder.String())
+}
@@ -0,0 +1,226 @@
+package captions
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ttmlNamespace = "http://www.w3.org/ns/ttml"
+
+var (
+	clockTimePattern  = regexp.MustCompile(`^(\d+):(\d{2}):(\d{2}(?:\.\d+)?)(?::(\d+(?:\.\d+)?))?$`)
+	offsetTimePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)(h|m|s|ms|f|t)$`)
+	idPattern         = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+)
+
+// ttmlTiming holds the frame and tick rates of a TTML document.
+type ttmlTiming struct {
+	frameRate float64
+	tickRate  float64
+}
+
+// parseTime parses a TTML time expression, i.e. a clock time like `00:00:01.5` or `00:00:01:12` with frames, or an
+// offset time like `1.5s`, `1500ms` or `36f`.
+func (t ttmlTiming) parseTime(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	if match := clockTimePattern.FindStringSubmatch(value); match != nil {
+		hours, _ := strconv.ParseFloat(match[1], 64)
+		minutes, _ := strconv.ParseFloat(match[2], 64)
+		seconds, _ := strconv.ParseFloat(match[3], 64)
+		frames, _ := strconv.ParseFloat("0"+match[4], 64)
+
+		return secondsDuration(hours*3600 + minutes*60 + seconds + frames/t.frameRate), nil
+	}
+
+	if match := offsetTimePattern.FindStringSubmatch(value); match != nil {
+		count, _ := strconv.ParseFloat(match[1], 64)
+
+		switch match[2] {
+		case "h":
+			count *= 3600
+		case "m":
+			count *= 60
+		case "ms":
+			count /= 1000
+		case "f":
+			count /= t.frameRate
+		case "t":
+			count /= t.tickRate
+		}
+
+		return secondsDuration(count), nil
+	}
+
+	return 0, fmt.Errorf("invalid time expression %q", value)
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+}
+
+// ttmlAttribute returns the value of an attribute by its local name.
+func ttmlAttribute(element xml.StartElement, name string) (string, bool) {
+	for _, attribute := range element.Attr {
+		if attribute.Name.Local == name {
+			return attribute.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// parseTTML parses the paragraphs of a TTML document as cues. Times are relative to the begin of the enclosing
+// elements, line breaks are kept and other white space is collapsed.
+func parseTTML(data []byte) (*Track, error) {
+	var (
+		track   = &Track{}
+		timing  = ttmlTiming{frameRate: 30, tickRate: 1}
+		begins  = []time.Duration{0}
+		cue     *Cue
+		text    strings.Builder
+		decoder = xml.NewDecoder(bytes.NewReader(data))
+		root    = true
+	)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			if root {
+				if token.Name.Local != "tt" {
+					return nil, fmt.Errorf("unexpected root element %q", token.Name.Local)
+				}
+
+				if err := timing.read(token); err != nil {
+					return nil, err
+				}
+
+				root = false
+			}
+
+			begin := begins[len(begins)-1]
+
+			if value, ok := ttmlAttribute(token, "begin"); ok {
+				offset, err := timing.parseTime(value)
+				if err != nil {
+					return nil, err
+				}
+
+				begin += offset
+			}
+
+			begins = append(begins, begin)
+
+			switch {
+			case token.Name.Local == "p" && cue == nil:
+				end, err := timing.end(token, begins[len(begins)-2], begin)
+				if err != nil {
+					return nil, err
+				}
+
+				id, _ := ttmlAttribute(token, "id")
+				cue = &Cue{ID: id, Start: begin, End: end}
+				text.Reset()
+			case token.Name.Local == "br" && cue != nil:
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if cue != nil {
+				// Line breaks in the source are white space, only br elements break lines.
+				text.WriteString(strings.NewReplacer("\n", " ", "\r", " ").Replace(string(token)))
+			}
+		case xml.EndElement:
+			begins = begins[:len(begins)-1]
+
+			if token.Name.Local == "p" && cue != nil {
+				cue.Text = collapseSpace(text.String())
+				track.Cues = append(track.Cues, *cue)
+				cue = nil
+			}
+		}
+	}
+
+	if root {
+		return nil, errors.New("missing tt element")
+	}
+
+	return track, nil
+}
+
+// read reads the frame and tick rates of the root element.
+func (t *ttmlTiming) read(root xml.StartElement) error {
+	for name, rate := range map[string]*float64{"frameRate": &t.frameRate, "tickRate": &t.tickRate} {
+		if value, ok := ttmlAttribute(root, name); ok {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || parsed <= 0 {
+				return fmt.Errorf("invalid %s %q", name, value)
+			}
+
+			*rate = parsed
+		}
+	}
+
+	return nil
+}
+
+// end returns the end of a paragraph from its end or dur attribute.
+func (t ttmlTiming) end(element xml.StartElement, parentBegin, begin time.Duration) (time.Duration, error) {
+	if value, ok := ttmlAttribute(element, "end"); ok {
+		end, err := t.parseTime(value)
+		return parentBegin + end, err
+	}
+
+	if value, ok := ttmlAttribute(element, "dur"); ok {
+		duration, err := t.parseTime(value)
+		return begin + duration, err
+	}
+
+	return 0, errors.New("missing end of paragraph")
+}
+
+// collapseSpace collapses white space within lines and removes it around line breaks.
+func collapseSpace(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (t *Track) ttml() []byte {
+	var builder strings.Builder
+
+	builder.WriteString(xml.Header)
+	builder.WriteString(`<tt xmlns="` + ttmlNamespace + `">` + "\n  <body>\n    <div>\n")
+
+	for _, cue := range t.Cues {
+		builder.WriteString(`      <p begin="` + formatTimestamp(cue.Start, ".") + `" end="` + formatTimestamp(cue.End, ".") + `"`)
+
+		if idPattern.MatchString(cue.ID) {
+			builder.WriteString(` xml:id="` + cue.ID + `"`)
+		}
+
+		builder.WriteString(">" + strings.ReplaceAll(textEscaper.Replace(cue.Text), "\n", "<br/>") + "</p>\n")
+	}
+
+	builder.WriteString("    </div>\n  </body>\n</tt>\n")
+
+	return []byte(builder.String())
+}
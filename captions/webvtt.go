@@ -0,0 +1,76 @@
+package captions
+
+import (
+	"errors"
+	"strings"
+)
+
+// parseWebVTT parses a WebVTT file. NOTE, STYLE and REGION blocks are skipped.
+func parseWebVTT(text string) (*Track, error) {
+	parsed := blocks(text)
+	if len(parsed) == 0 || !isWebVTTSignature(parsed[0][0]) {
+		return nil, errors.New("missing WEBVTT signature")
+	}
+
+	track := &Track{}
+
+	for _, block := range parsed[1:] {
+		if keyword := strings.Fields(block[0]); len(keyword) > 0 && (keyword[0] == "NOTE" || keyword[0] == "STYLE" || keyword[0] == "REGION") {
+			continue
+		}
+
+		var id string
+		if !strings.Contains(block[0], "-->") {
+			id, block = block[0], block[1:]
+		}
+
+		if len(block) == 0 {
+			return nil, errors.New("missing cue timing")
+		}
+
+		start, end, err := parseTiming(block[0])
+		if err != nil {
+			return nil, err
+		}
+
+		track.Cues = append(track.Cues, Cue{ID: id, Start: start, End: end, Text: plainText(block[1:])})
+	}
+
+	return track, nil
+}
+
+func isWebVTTSignature(line string) bool {
+	return line == "WEBVTT" || strings.HasPrefix(line, "WEBVTT ") || strings.HasPrefix(line, "WEBVTT\t")
+}
+
+func (t *Track) webVTT() []byte {
+	var builder strings.Builder
+
+	builder.WriteString("WEBVTT\n")
+
+	for _, cue := range t.Cues {
+		builder.WriteString("\n")
+
+		if id := strings.TrimSpace(cue.ID); id != "" && !strings.Contains(id, "-->") {
+			builder.WriteString(id + "\n")
+		}
+
+		builder.WriteString(formatTimestamp(cue.Start, ".") + " --> " + formatTimestamp(cue.End, ".") + "\n")
+		builder.WriteString(cueLines(textEscaper.Replace(cue.Text)))
+	}
+
+	return []byte(builder.String())
+}
+
+// cueLines returns the lines of a cue text without blank lines, which would end the cue, each followed by `\n`.
+func cueLines(text string) string {
+	var builder strings.Builder
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			builder.WriteString(line + "\n")
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go.eigsys.de/go-vast"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+type summary struct {
+	File    string       `json:"file"`
+	Version vast.Version `json:"version"`
+	Ads     []adSummary  `json:"ads"`
+}
+
+type adSummary struct {
+	ID            string            `json:"id,omitempty"`
+	Type          string            `json:"type"`
+	AdSystem      string            `json:"adSystem,omitempty"`
+	AdTitle       string            `json:"adTitle,omitempty"`
+	Advertiser    string            `json:"advertiser,omitempty"`
+	Pricing       *vast.Pricing     `json:"pricing,omitempty"`
+	VASTAdTagURI  string            `json:"vastAdTagURI,omitempty"`
+	Creatives     []creativeSummary `json:"creatives"`
+	Verifications []string          `json:"verifications"`
+	Trackers      map[string]int    `json:"trackers"`
+}
+
+type creativeSummary struct {
+	ID         string             `json:"id,omitempty"`
+	AdID       string             `json:"adId,omitempty"`
+	Duration   vast.Duration      `json:"duration,omitempty"`
+	MediaFiles []mediaFileSummary `json:"mediaFiles"`
+	NonLinears []string           `json:"nonLinears"`
+	Companions []string           `json:"companions"`
+}
+
+type mediaFileSummary struct {
+	Width    int           `json:"width"`
+	Height   int           `json:"height"`
+	Bitrate  int           `json:"bitrate,omitempty"`
+	Type     string        `json:"type"`
+	Delivery vast.Delivery `json:"delivery"`
+}
+
+func runInspect(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "table", "output format: table or json")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *format != "table" && *format != "json" {
+		_, _ = fmt.Fprintf(stderr, "vast inspect: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	inputs, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "vast inspect: %v\n", err)
+		return exitFailure
+	}
+
+	code := exitOK
+	summaries := make([]summary, 0, len(inputs))
+
+	for _, input := range inputs {
+		document, err := readDocument(input.data)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "vast inspect: %s: %s\n", input.name, strings.ReplaceAll(err.Error(), "\n", ": "))
+			code = exitFailure
+
+			continue
+		}
+
+		summaries = append(summaries, summarize(input.name, document))
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(summaries)
+
+		return code
+	}
+
+	for i, summary := range summaries {
+		if i > 0 {
+			_, _ = fmt.Fprintln(stdout)
+		}
+
+		writeSummaryTable(stdout, summary)
+	}
+
+	return code
+}
+
+func summarize(name string, document *vast.VAST) summary {
+	result := summary{File: name, Version: document.Version, Ads: make([]adSummary, 0, len(document.Ad))}
+
+	for _, ad := range document.Ad {
+		adSummary := adSummary{ID: ad.ID, Creatives: []creativeSummary{}, Verifications: []string{}, Trackers: map[string]int{}}
+
+		var (
+			base            *vast.AdDefinitionBase
+			adVerifications *vast.AdVerifications
+		)
+
+		switch {
+		case ad.InLine != nil:
+			adSummary.Type = "InLine"
+			adSummary.AdTitle = ad.InLine.AdTitle
+			adSummary.Advertiser = ad.InLine.Advertiser
+			base, adVerifications = &ad.InLine.AdDefinitionBase, ad.InLine.AdVerifications
+
+			for _, creative := range ad.InLine.Creatives.Creative {
+				adSummary.Creatives = append(adSummary.Creatives, summarizeInLineCreative(creative, adSummary.Trackers))
+			}
+		case ad.Wrapper != nil:
+			adSummary.Type = "Wrapper"
+			adSummary.VASTAdTagURI = ad.Wrapper.VASTAdTagURI.Value
+			base, adVerifications = &ad.Wrapper.AdDefinitionBase, ad.Wrapper.AdVerifications
+
+			if ad.Wrapper.Creatives != nil {
+				for _, creative := range ad.Wrapper.Creatives.Creative {
+					adSummary.Creatives = append(adSummary.Creatives, summarizeWrapperCreative(creative, adSummary.Trackers))
+				}
+			}
+		default:
+			adSummary.Type = "empty"
+		}
+
+		if base != nil {
+			adSummary.AdSystem = strings.TrimSpace(base.AdSystem.Value + " " + base.AdSystem.Version)
+			adSummary.Pricing = base.Pricing
+			adSummary.Trackers["impression"] += len(base.Impression)
+			adSummary.Trackers["error"] += len(base.Error)
+
+			if base.ViewableImpression != nil {
+				adSummary.Trackers["viewable"] += len(base.ViewableImpression.Viewable)
+				adSummary.Trackers["notViewable"] += len(base.ViewableImpression.NotViewable)
+				adSummary.Trackers["viewUndetermined"] += len(base.ViewableImpression.ViewUndetermined)
+			}
+		}
+
+		if adVerifications != nil {
+			for _, verification := range adVerifications.Verification {
+				adSummary.Verifications = append(adSummary.Verifications, verification.Vendor)
+			}
+		}
+
+		for event, count := range adSummary.Trackers {
+			if count == 0 {
+				delete(adSummary.Trackers, event)
+			}
+		}
+
+		result.Ads = append(result.Ads, adSummary)
+	}
+
+	return result
+}
+
+func countTracking(trackers map[string]int, trackingEvents *vast.TrackingEvents) {
+	if trackingEvents == nil {
+		return
+	}
+
+	for _, tracking := range trackingEvents.Tracking {
+		trackers[tracking.Event]++
+	}
+}
+
+func countVideoClicks(trackers map[string]int, videoClicks *vast.VideoClicks) {
+	if videoClicks != nil {
+		trackers["clickTracking"] += len(videoClicks.ClickTracking)
+	}
+}
+
+func summarizeInLineCreative(creative vast.InLineCreative, trackers map[string]int) creativeSummary {
+	result := creativeSummary{
+		ID:         creative.ID,
+		AdID:       creative.AdID,
+		MediaFiles: []mediaFileSummary{},
+		NonLinears: []string{},
+		Companions: []string{},
+	}
+
+	if linear := creative.Linear; linear != nil {
+		result.Duration = linear.Duration
+
+		for _, mediaFile := range linear.MediaFiles.MediaFile {
+			result.MediaFiles = append(result.MediaFiles, mediaFileSummary{
+				Width:    mediaFile.Width,
+				Height:   mediaFile.Height,
+				Bitrate:  mediaFile.Bitrate,
+				Type:     mediaFile.Type,
+				Delivery: mediaFile.Delivery,
+			})
+		}
+
+		countTracking(trackers, linear.TrackingEvents)
+		countVideoClicks(trackers, linear.VideoClicks)
+	}
+
+	summarizeNonLinearAds(&result, creative.NonLinearAds, trackers)
+	summarizeCompanionAds(&result, creative.CompanionAds, trackers)
+
+	return result
+}
+
+func summarizeWrapperCreative(creative vast.WrapperCreative, trackers map[string]int) creativeSummary {
+	result := creativeSummary{
+		ID:         creative.ID,
+		AdID:       creative.AdID,
+		MediaFiles: []mediaFileSummary{},
+		NonLinears: []string{},
+		Companions: []string{},
+	}
+
+	if linear := creative.Linear; linear != nil {
+		countTracking(trackers, linear.TrackingEvents)
+		countVideoClicks(trackers, linear.VideoClicks)
+	}
+
+	summarizeNonLinearAds(&result, creative.NonLinearAds, trackers)
+	summarizeCompanionAds(&result, creative.CompanionAds, trackers)
+
+	return result
+}
+
+func summarizeNonLinearAds(result *creativeSummary, nonLinearAds *vast.NonLinearAds, trackers map[string]int) {
+	if nonLinearAds == nil {
+		return
+	}
+
+	countTracking(trackers, nonLinearAds.TrackingEvents)
+
+	for _, nonLinear := range nonLinearAds.NonLinear {
+		result.NonLinears = append(result.NonLinears, fmt.Sprintf("%dx%d", nonLinear.Width, nonLinear.Height))
+		trackers["nonLinearClickTracking"] += len(nonLinear.NonLinearClickTracking)
+	}
+}
+
+func summarizeCompanionAds(result *creativeSummary, companionAds *vast.CompanionAdsCollection, trackers map[string]int) {
+	if companionAds == nil {
+		return
+	}
+
+	for _, companion := range companionAds.Companion {
+		result.Companions = append(result.Companions, fmt.Sprintf("%dx%d", companion.Width, companion.Height))
+		countTracking(trackers, companion.TrackingEvents)
+		trackers["companionClickTracking"] += len(companion.CompanionClickTracking)
+	}
+}
+
+func writeSummaryTable(w io.Writer, summary summary) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer func() { _ = table.Flush() }()
+
+	_, _ = fmt.Fprintf(table, "File:\t%s\n", summary.File)
+	_, _ = fmt.Fprintf(table, "Version:\t%s\n", summary.Version)
+	_, _ = fmt.Fprintf(table, "Ads:\t%d\n", len(summary.Ads))
+
+	for i, ad := range summary.Ads {
+		_, _ = fmt.Fprintf(table, "\nAd %d:\t%s\n", i+1, ad.Type)
+		writeRow(table, "  ID", ad.ID)
+		writeRow(table, "  AdSystem", ad.AdSystem)
+		writeRow(table, "  AdTitle", ad.AdTitle)
+		writeRow(table, "  Advertiser", ad.Advertiser)
+		writeRow(table, "  VASTAdTagURI", ad.VASTAdTagURI)
+
+		if ad.Pricing != nil {
+			writeRow(table, "  Pricing", fmt.Sprintf("%s %s %s", strconv.FormatFloat(ad.Pricing.Value, 'f', -1, 64), ad.Pricing.Currency, ad.Pricing.Model))
+		}
+
+		writeRow(table, "  Verifications", strings.Join(ad.Verifications, ", "))
+
+		for j, creative := range ad.Creatives {
+			_, _ = fmt.Fprintf(table, "  Creative %d:\tid %q, adId %q\n", j+1, creative.ID, creative.AdID)
+			writeRow(table, "    Duration", string(creative.Duration))
+
+			for _, mediaFile := range creative.MediaFiles {
+				_, _ = fmt.Fprintf(table, "    MediaFile:\t%dx%d\t%d kbit/s\t%s\t%s\n", mediaFile.Width, mediaFile.Height, mediaFile.Bitrate, mediaFile.Type, mediaFile.Delivery)
+			}
+
+			writeRow(table, "    NonLinears", strings.Join(creative.NonLinears, ", "))
+			writeRow(table, "    Companions", strings.Join(creative.Companions, ", "))
+		}
+
+		events := make([]string, 0, len(ad.Trackers))
+		for event := range ad.Trackers {
+			events = append(events, event)
+		}
+
+		sort.Strings(events)
+
+		for _, event := range events {
+			_, _ = fmt.Fprintf(table, "  Tracker %s:\t%d\n", event, ad.Trackers[event])
+		}
+	}
+}
+
+func writeRow(w io.Writer, name, value string) {
+	if value != "" {
+		_, _ = fmt.Fprintf(w, "%s:\t%s\n", name, value)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"strings"
+	"testing"
+)
+
+func TestRunInspect_table(t *testing.T) {
+	code, stdout, _ := runCommand(t, "", "inspect", fixture("iab/Inline_Linear_Tag-test.xml"), fixture("iab/Wrapper_Tag-test.xml"))
+	if code != exitOK {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+
+	for _, want := range []string{
+		"Ads:      1\n",
+		"Ad 1:                     InLine\n",
+		"  Pricing:                25.12 USD cpm\n",
+		"    MediaFile:            854x480   1000 kbit/s  video/mp4  progressive\n",
+		"  Tracker impression:     1\n",
+		"Ad 1:                  Wrapper\n",
+		"    Companions:        100x150\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("missing %q in output: %s", want, stdout)
+		}
+	}
+}
+
+func TestRunInspect_json(t *testing.T) {
+	code, stdout, _ := runCommand(t, mustReadFile(t, fixture("iab/Inline_Non-Linear_Tag-test.xml")), "inspect", "-format", "json")
+	if code != exitOK {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+
+	var summaries []summary
+	if err := json.Unmarshal([]byte(stdout), &summaries); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []summary{{
+		File:    "<stdin>",
+		Version: "4.2",
+		Ads: []adSummary{{
+			ID:            "20005",
+			Type:          "InLine",
+			AdSystem:      "iabtechlab 1",
+			AdTitle:       "VAST 4.0 Pilot - Scenario 5",
+			Pricing:       &vast.Pricing{Value: 25.12, Model: "cpm", Currency: "USD"},
+			Creatives:     []creativeSummary{{ID: "5480", AdID: "2447226", MediaFiles: []mediaFileSummary{}, NonLinears: []string{"350x350"}, Companions: []string{}}},
+			Verifications: []string{},
+			Trackers:      map[string]int{"error": 1, "impression": 1, "nonLinearClickTracking": 1},
+		}},
+	}}
+
+	if diff := cmp.Diff(want, summaries); diff != "" {
+		t.Errorf("wrong summary: %s", diff)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	document := &vast.VAST{Ad: []vast.Ad{
+		{},
+		{Wrapper: &vast.Wrapper{
+			AdDefinitionBase: vast.AdDefinitionBase{
+				ViewableImpression: &vast.ViewableImpression{Viewable: []vast.CData{{Value: "https://example.com/viewable"}}},
+			},
+			AdVerifications: &vast.AdVerifications{Verification: []vast.Verification{{Vendor: "example.com-omid"}}},
+			Creatives: &vast.Creatives{Creative: []vast.WrapperCreative{{
+				Linear: &vast.LinearWrapper{
+					LinearBase:  vast.LinearBase{TrackingEvents: &vast.TrackingEvents{Tracking: []vast.Tracking{{Event: "start"}}}},
+					VideoClicks: &vast.VideoClicks{ClickTracking: []vast.CData{{Value: "https://example.com/click"}}},
+				},
+				CompanionAds: &vast.CompanionAdsCollection{Companion: []vast.CompanionAd{{Width: 300, Height: 250, CompanionClickTracking: []string{"https://example.com/click"}}}},
+			}}},
+		}},
+	}}
+
+	got := summarize("test", document)
+
+	if got.Ads[0].Type != "empty" || got.Ads[1].Type != "Wrapper" {
+		t.Errorf("unexpected types: %v", got.Ads)
+	}
+
+	if diff := cmp.Diff([]string{"example.com-omid"}, got.Ads[1].Verifications); diff != "" {
+		t.Errorf("wrong verifications: %s", diff)
+	}
+
+	if diff := cmp.Diff(map[string]int{"viewable": 1, "start": 1, "clickTracking": 1, "companionClickTracking": 1}, got.Ads[1].Trackers); diff != "" {
+		t.Errorf("wrong trackers: %s", diff)
+	}
+
+	var output strings.Builder
+	writeSummaryTable(&output, got)
+
+	if !strings.Contains(output.String(), "  Verifications:                   example.com-omid\n") {
+		t.Errorf("unexpected output: %s", output.String())
+	}
+}
+
+func TestRunInspect_errors(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		stdin string
+		code  int
+	}{
+		{[]string{"inspect", "-unknown"}, "", exitUsage},
+		{[]string{"inspect", "-format", "yaml"}, "", exitUsage},
+		{[]string{"inspect", "missing.xml"}, "", exitFailure},
+		{[]string{"inspect"}, "<VAST>", exitFailure},
+	}
+
+	for _, testCase := range testCases {
+		if code, _, _ := runCommand(t, testCase.stdin, testCase.args...); code != testCase.code {
+			t.Errorf("unexpected exit code for %v: %d", testCase.args, code)
+		}
+	}
+}
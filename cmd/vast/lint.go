@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"go.eigsys.de/go-vast"
+	"io"
+	"strings"
+)
+
+// parseRule is reported for documents which cannot be parsed.
+var parseRule = vast.Rule{ID: "parse", Description: "The document must be well-formed XML matching the VAST structure."}
+
+// finding is a violation located in a document.
+type finding struct {
+	File string `json:"file"`
+	position
+	Severity vast.Severity `json:"severity"`
+	Rule     string        `json:"rule"`
+	Path     string        `json:"path,omitempty"`
+	Message  string        `json:"message"`
+}
+
+func runLint(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format: text, json or sarif")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	writers := map[string]func(io.Writer, []finding) error{
+		"text":  writeTextFindings,
+		"json":  writeJSONFindings,
+		"sarif": writeSARIFFindings,
+	}
+
+	write, ok := writers[*format]
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "vast lint: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	inputs, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "vast lint: %v\n", err)
+		return exitFailure
+	}
+
+	var findings []finding
+	for _, input := range inputs {
+		findings = append(findings, lint(input)...)
+	}
+
+	if err := write(stdout, findings); err != nil {
+		_, _ = fmt.Fprintf(stderr, "vast lint: %v\n", err)
+		return exitFailure
+	}
+
+	for _, finding := range findings {
+		if finding.Severity == vast.ErrorSeverity {
+			return exitFailure
+		}
+	}
+
+	return exitOK
+}
+
+func lint(input input) []finding {
+	document, err := vast.Read(io.NopCloser(bytes.NewReader(input.data)))
+	if err != nil {
+		position := position{Line: 1, Column: 1}
+
+		var syntaxError *xml.SyntaxError
+		if errors.As(err, &syntaxError) {
+			position.Line = syntaxError.Line
+		}
+
+		return []finding{{
+			File:     input.name,
+			position: position,
+			Severity: vast.ErrorSeverity,
+			Rule:     parseRule.ID,
+			Message:  strings.ReplaceAll(err.Error(), "\n", ": "),
+		}}
+	}
+
+	index := newPositionIndex(input.data)
+	violations := document.Validate()
+	findings := make([]finding, 0, len(violations))
+
+	for _, violation := range violations {
+		findings = append(findings, finding{
+			File:     input.name,
+			position: index.lookup(violation.Path),
+			Severity: violation.Severity,
+			Rule:     violation.Rule,
+			Path:     violation.Path,
+			Message:  violation.Message,
+		})
+	}
+
+	return findings
+}
+
+func writeTextFindings(w io.Writer, findings []finding) error {
+	for _, finding := range findings {
+		if _, err := fmt.Fprintf(w, "%s:%d:%d: %s: %s [%s]\n", finding.File, finding.Line, finding.Column, finding.Severity, finding.Message, finding.Rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeJSONFindings(w io.Writer, findings []finding) error {
+	if findings == nil {
+		findings = []finding{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(findings)
+}
+
+// SARIF 2.1.0, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+func writeSARIFFindings(w io.Writer, findings []finding) error {
+	rules := make([]sarifRule, 0, len(vast.Rules)+1)
+	for _, rule := range append([]vast.Rule{parseRule}, vast.Rules...) {
+		rules = append(rules, sarifRule{ID: rule.ID, ShortDescription: sarifMessage{Text: rule.Description}})
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, finding := range findings {
+		results = append(results, sarifResult{
+			RuleID:  finding.Rule,
+			Level:   string(finding.Severity),
+			Message: sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: finding.File},
+					Region:           sarifRegion{StartLine: finding.Line, StartColumn: finding.Column},
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "vast lint", Rules: rules}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(log)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunLint_valid(t *testing.T) {
+	code, stdout, _ := runCommand(t, "", "lint", fixture("iab/Inline_Simple.xml"))
+	if code != exitOK || stdout != "" {
+		t.Errorf("unexpected result: %d %s", code, stdout)
+	}
+}
+
+func TestRunLint_text(t *testing.T) {
+	code, stdout, _ := runCommand(t, "", "lint", fixture("invalid.xml"))
+	if code != exitFailure {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 15 {
+		t.Fatalf("unexpected number of findings: %d", len(lines))
+	}
+
+	if want := fixture("invalid.xml") + `:2:3: error: invalid adType "movie", expected one of video, audio, hybrid [enumeration]`; lines[0] != want {
+		t.Errorf("unexpected finding: %s", lines[0])
+	}
+
+	if want := fixture("invalid.xml") + `:14:15: warning: unknown event "begin" [enumeration]`; lines[7] != want {
+		t.Errorf("unexpected finding: %s", lines[7])
+	}
+}
+
+func TestRunLint_json(t *testing.T) {
+	code, stdout, _ := runCommand(t, mustReadFile(t, fixture("invalid.xml")), "lint", "-format", "json")
+	if code != exitFailure {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+
+	var findings []finding
+	if err := json.Unmarshal([]byte(stdout), &findings); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := finding{
+		File:     "<stdin>",
+		position: position{Line: 19, Column: 15},
+		Severity: "error",
+		Rule:     "required-attribute",
+		Path:     "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/MediaFiles/MediaFile[1]/@height",
+		Message:  "missing required attribute height",
+	}
+
+	if len(findings) != 15 || findings[12] != want {
+		t.Errorf("unexpected findings: %v", findings)
+	}
+}
+
+func TestRunLint_jsonEmpty(t *testing.T) {
+	code, stdout, _ := runCommand(t, "", "lint", "-format", "json", fixture("iab/Inline_Simple.xml"))
+	if code != exitOK || stdout != "[]\n" {
+		t.Errorf("unexpected result: %d %s", code, stdout)
+	}
+}
+
+func TestRunLint_sarif(t *testing.T) {
+	code, stdout, _ := runCommand(t, "<VAST>", "lint", "-format", "sarif", "-")
+	if code != exitFailure {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatalf("unexpected log: %v", log)
+	}
+
+	result := log.Runs[0].Results[0]
+	if result.RuleID != "parse" || result.Level != "error" || result.Locations[0].PhysicalLocation.Region.StartLine != 1 {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	if !strings.Contains(result.Message.Text, "XML syntax error on line 1: unexpected EOF") {
+		t.Errorf("unexpected message: %s", result.Message.Text)
+	}
+}
+
+func TestRunLint_unknownFormat(t *testing.T) {
+	if code, _, _ := runCommand(t, "", "lint", "-format", "yaml"); code != exitUsage {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+}
+
+func TestRunLint_invalidFlag(t *testing.T) {
+	if code, _, _ := runCommand(t, "", "lint", "-unknown"); code != exitUsage {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+}
+
+func TestRunLint_missingFile(t *testing.T) {
+	code, _, stderr := runCommand(t, "", "lint", "missing.xml")
+	if code != exitFailure || !strings.HasPrefix(stderr, "vast lint: open missing.xml") {
+		t.Errorf("unexpected result: %d %s", code, stderr)
+	}
+}
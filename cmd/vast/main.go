@@ -0,0 +1,108 @@
+// Command vast lints and processes Digital Video Ad Serving Templates (VAST).
+//
+// Usage:
+//
+//	vast <command> [flags] [files]
+//
+// Files are read from stdin if no file is given or if the file is "-".
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{"lint", "validate documents against the VAST specification", runLint},
+	{"fmt", "normalize the formatting of documents", runFmt},
+	{"convert", "change the declared version or translate documents to and from JSON", runConvert},
+	{"resolve", "follow wrapper chains and print the merged InLine ads", runResolve},
+	{"inspect", "print a summary of documents", runInspect},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	for _, command := range commands {
+		if command.name == args[0] {
+			return command.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stdout)
+		return exitOK
+	}
+
+	_, _ = fmt.Fprintf(stderr, "vast: unknown command %q\n", args[0])
+	usage(stderr)
+
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "Usage: vast <command> [flags] [files]")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Commands:")
+
+	for _, command := range commands {
+		_, _ = fmt.Fprintf(w, "  %-10s %s\n", command.name, command.description)
+	}
+}
+
+// input is a document read from a file or stdin.
+type input struct {
+	name string
+	data []byte
+}
+
+// readInputs reads all files, or stdin if no file is given.
+func readInputs(files []string, stdin io.Reader) ([]input, error) {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	inputs := make([]input, 0, len(files))
+
+	for _, file := range files {
+		var (
+			data []byte
+			err  error
+		)
+
+		if file == "-" {
+			file = "<stdin>"
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		inputs = append(inputs, input{name: file, data: data})
+	}
+
+	return inputs, nil
+}
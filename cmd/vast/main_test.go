@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runCommand(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func fixture(name string) string {
+	return filepath.Join("..", "..", "testdata", name)
+}
+
+func mustReadFile(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return string(data)
+}
+
+func TestRun_usage(t *testing.T) {
+	code, _, stderr := runCommand(t, "")
+	if code != exitUsage || !strings.HasPrefix(stderr, "Usage: vast") {
+		t.Errorf("unexpected result: %d %s", code, stderr)
+	}
+}
+
+func TestRun_help(t *testing.T) {
+	code, stdout, _ := runCommand(t, "", "help")
+	if code != exitOK || !strings.Contains(stdout, "lint") {
+		t.Errorf("unexpected result: %d %s", code, stdout)
+	}
+}
+
+func TestRun_unknownCommand(t *testing.T) {
+	code, _, stderr := runCommand(t, "", "unknown")
+	if code != exitUsage || !strings.HasPrefix(stderr, `vast: unknown command "unknown"`) {
+		t.Errorf("unexpected result: %d %s", code, stderr)
+	}
+}
+
+func TestPositionIndex_lookup(t *testing.T) {
+	index := newPositionIndex([]byte("<VAST>\n  <Ad/>\n  <Ad>\n    <InLine/></Ad>\n</VAST>"))
+
+	testCases := map[string]position{
+		"/VAST":                 {Line: 1, Column: 1},
+		"/VAST/Ad[2]":           {Line: 3, Column: 3},
+		"/VAST/Ad[2]/InLine":    {Line: 4, Column: 5},
+		"/VAST/Ad[2]/@id":       {Line: 3, Column: 3},
+		"/VAST/Ad[1]/InLine":    {Line: 2, Column: 3},
+		"/VAST/Ad[3]/InLine":    {Line: 1, Column: 1},
+		"/Unknown/Ad[1]/InLine": {Line: 1, Column: 1},
+	}
+
+	for path, want := range testCases {
+		if got := index.lookup(path); got != want {
+			t.Errorf("unexpected position of %s: %v", path, got)
+		}
+	}
+}
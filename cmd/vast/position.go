@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// position is a location in a document. Line and column start at 1.
+type position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// positionIndex maps fully indexed element paths like `/VAST[1]/Ad[2]` to the position of their start tags.
+type positionIndex map[string]position
+
+// newPositionIndex indexes the positions of all elements of a document.
+// It returns a partial index if the document is malformed.
+func newPositionIndex(data []byte) positionIndex {
+	index := positionIndex{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	type frame struct {
+		path     string
+		children map[string]int
+	}
+
+	stack := []frame{{children: map[string]int{}}}
+
+	for {
+		offset := decoder.InputOffset()
+
+		token, err := decoder.Token()
+		if err != nil {
+			return index
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			parent := stack[len(stack)-1]
+			parent.children[token.Name.Local]++
+
+			path := fmt.Sprintf("%s/%s[%d]", parent.path, token.Name.Local, parent.children[token.Name.Local])
+			index[path] = offsetPosition(data, offset+int64(bytes.IndexByte(data[offset:], '<')))
+			stack = append(stack, frame{path: path, children: map[string]int{}})
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+func offsetPosition(data []byte, offset int64) position {
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(data[:offset], '\n')
+
+	return position{Line: line, Column: column}
+}
+
+var pathSegmentPattern = regexp.MustCompile(`^[^\[]+\[\d+]$`)
+
+// lookup returns the position of the element addressed by an XPath-like path as used by vast.Violation.
+// Segments without index refer to the first element, attributes refer to their element.
+// If the element does not exist, the position of the closest existing ancestor is returned.
+func (p positionIndex) lookup(path string) position {
+	var segments []string
+
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		if strings.HasPrefix(segment, "@") || segment == "" {
+			break
+		}
+
+		if !pathSegmentPattern.MatchString(segment) {
+			segment += "[1]"
+		}
+
+		segments = append(segments, segment)
+	}
+
+	for i := len(segments); i > 0; i-- {
+		if position, ok := p["/"+strings.Join(segments[:i], "/")]; ok {
+			return position
+		}
+	}
+
+	return position{Line: 1, Column: 1}
+}
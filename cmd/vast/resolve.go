@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go.eigsys.de/go-vast"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fixtureIndex is the name of the file which maps URIs to recorded responses in a fixture directory.
+const fixtureIndex = "fixtures.json"
+
+var errMissingFixture = errors.New("no recorded response")
+
+// fixtureFetcher serves recorded responses from a directory.
+// The directory contains fixtures.json, which maps URIs to file names relative to the directory.
+type fixtureFetcher struct {
+	dir      string
+	fixtures map[string]string
+}
+
+func newFixtureFetcher(dir string) (*fixtureFetcher, error) {
+	data, err := os.ReadFile(filepath.Join(dir, fixtureIndex))
+	if err != nil {
+		return nil, err
+	}
+
+	fetcher := &fixtureFetcher{dir: dir}
+	if err := json.Unmarshal(data, &fetcher.fixtures); err != nil {
+		return nil, fmt.Errorf("%s: %w", fixtureIndex, err)
+	}
+
+	return fetcher, nil
+}
+
+func (f *fixtureFetcher) Fetch(_ context.Context, uri string) (*vast.VAST, error) {
+	name, ok := f.fixtures[uri]
+	if !ok {
+		return nil, errors.Join(vast.ErrFetchVAST, fmt.Errorf("%w for %s", errMissingFixture, uri))
+	}
+
+	handle, err := os.Open(filepath.Join(f.dir, name))
+	if err != nil {
+		return nil, errors.Join(vast.ErrFetchVAST, err)
+	}
+
+	return vast.Read(handle)
+}
+
+func runResolve(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("resolve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	fixtures := flags.String("fixtures", "", "directory of recorded responses instead of live HTTP requests")
+	timeout := flags.Duration("timeout", 10*time.Second, "timeout for resolving all wrapper chains")
+	maxWrappers := flags.Int("max-wrappers", vast.DefaultMaxWrappers, "maximum number of wrappers per chain")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if flags.NArg() > 1 {
+		_, _ = fmt.Fprintln(stderr, "vast resolve: too many files")
+		return exitUsage
+	}
+
+	if *maxWrappers <= 0 {
+		_, _ = fmt.Fprintln(stderr, "vast resolve: -max-wrappers must be positive")
+		return exitUsage
+	}
+
+	resolver := &vast.Resolver{Fetcher: &vast.HTTPFetcher{}, MaxWrappers: *maxWrappers}
+
+	if *fixtures != "" {
+		fetcher, err := newFixtureFetcher(*fixtures)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "vast resolve: %v\n", err)
+			return exitFailure
+		}
+
+		resolver.Fetcher = fetcher
+	}
+
+	inputs, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "vast resolve: %v\n", err)
+		return exitFailure
+	}
+
+	document, err := readDocument(inputs[0].data)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "vast resolve: %s: %v\n", inputs[0].name, err)
+		return exitFailure
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	code := exitOK
+	result := &vast.VAST{Version: document.Version, XMLNS: document.XMLNS}
+
+	for i := range document.Ad {
+		ad, hops, err := resolver.Resolve(ctx, &document.Ad[i])
+
+		_, _ = fmt.Fprintf(stderr, "Ad %d (id %q):\n", i+1, document.Ad[i].ID)
+		for j, hop := range hops {
+			_, _ = fmt.Fprintf(stderr, "  %d. %s (%s)\n", j+1, hop.URI, hop.Duration.Round(time.Microsecond))
+		}
+
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "  error: %s\n", strings.ReplaceAll(err.Error(), "\n", ": "))
+			code = exitFailure
+
+			continue
+		}
+
+		_, _ = fmt.Fprintf(stderr, "  resolved after %d wrappers\n", len(hops))
+		result.Ad = append(result.Ad, *ad)
+	}
+
+	result.TrimSpace()
+
+	output, err := result.Bytes()
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "vast resolve: %v\n", err)
+		return exitFailure
+	}
+
+	_, _ = fmt.Fprintf(stdout, "%s\n", output)
+
+	return code
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var fixtureDir = filepath.Join("testdata", "fixtures")
+
+func TestRunResolve_fixtures(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "", "resolve", "-fixtures", fixtureDir, fixture("iab/Wrapper_Tag-test.xml"))
+	if code != exitOK {
+		t.Errorf("unexpected exit code: %d %s", code, stderr)
+	}
+
+	if !strings.HasPrefix(stderr, "Ad 1 (id \"20011\"):\n  1. https://raw.githubusercontent.com/InteractiveAdvertisingBureau/VAST_Samples/master/VAST%204.2%20Samples/Inline_Companion_Tag-test.xml (") ||
+		!strings.HasSuffix(stderr, "  resolved after 1 wrappers\n") {
+		t.Errorf("unexpected chain: %s", stderr)
+	}
+
+	if !strings.Contains(stdout, "<Ad id=\"20004\">") || strings.Count(stdout, "<Impression ") != 2 {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+}
+
+func TestRunResolve_missingFixture(t *testing.T) {
+	code, stdout, stderr := runCommand(t, mustReadFile(t, fixture("iab/Viewable_Impression-test.xml")), "resolve", "-fixtures", fixtureDir)
+	if code != exitFailure || !strings.Contains(stderr, "  error: cannot fetch VAST: open ") {
+		t.Errorf("unexpected result: %d %s", code, stderr)
+	}
+
+	if !strings.Contains(stdout, "<VAST version=\"4.2\" xmlns=\"http://www.iab.com/VAST\"></VAST>") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+}
+
+func TestRunResolve_http(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("..", "..", "testdata", "iab"))))
+	defer server.Close()
+
+	wrapper := fmt.Sprintf(`<VAST version="4.2"><Ad id="1"><Wrapper><AdSystem>example</AdSystem>
+<Impression><![CDATA[https://example.com/impression]]></Impression>
+<VASTAdTagURI><![CDATA[%s/Inline_Simple.xml]]></VASTAdTagURI></Wrapper></Ad></VAST>`, server.URL)
+
+	code, stdout, stderr := runCommand(t, wrapper, "resolve")
+	if code != exitOK || !strings.Contains(stderr, "  1. "+server.URL+"/Inline_Simple.xml (") {
+		t.Errorf("unexpected result: %d %s", code, stderr)
+	}
+
+	if !strings.Contains(stdout, "<Impression><![CDATA[https://example.com/impression]]></Impression>") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+}
+
+func TestRunResolve_errors(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		stdin string
+		code  int
+	}{
+		{[]string{"resolve", "-unknown"}, "", exitUsage},
+		{[]string{"resolve", "a.xml", "b.xml"}, "", exitUsage},
+		{[]string{"resolve", "-max-wrappers=-1"}, "", exitUsage},
+		{[]string{"resolve", "-max-wrappers=0"}, "", exitUsage},
+		{[]string{"resolve", "-fixtures", "missing"}, "", exitFailure},
+		{[]string{"resolve", "-fixtures", filepath.Join("testdata", "malformed")}, "", exitFailure},
+		{[]string{"resolve", "missing.xml"}, "", exitFailure},
+		{[]string{"resolve"}, "<VAST>", exitFailure},
+	}
+
+	for _, testCase := range testCases {
+		if code, _, _ := runCommand(t, testCase.stdin, testCase.args...); code != testCase.code {
+			t.Errorf("unexpected exit code for %v: %d", testCase.args, code)
+		}
+	}
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"go.eigsys.de/go-vast"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// rewriteFlags are the flags shared by commands which rewrite documents.
+type rewriteFlags struct {
+	write bool
+	check bool
+}
+
+func (r *rewriteFlags) register(flags *flag.FlagSet) {
+	flags.BoolVar(&r.write, "w", false, "write the result to the source files instead of stdout")
+	flags.BoolVar(&r.check, "check", false, "list files whose content would change and exit non-zero if there are any")
+}
+
+// rewrite transforms all inputs and writes them to stdout or back to their files.
+func rewrite(name string, options rewriteFlags, files []string, stdin io.Reader, stdout, stderr io.Writer, transform func([]byte) ([]byte, error)) int {
+	if options.write && len(files) == 0 {
+		_, _ = fmt.Fprintf(stderr, "vast %s: cannot use -w with stdin\n", name)
+		return exitUsage
+	}
+
+	inputs, err := readInputs(files, stdin)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "vast %s: %v\n", name, err)
+		return exitFailure
+	}
+
+	code := exitOK
+
+	for _, input := range inputs {
+		output, err := transform(input.data)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "vast %s: %s: %s\n", name, input.name, strings.ReplaceAll(err.Error(), "\n", ": "))
+			code = exitFailure
+
+			continue
+		}
+
+		switch {
+		case options.check:
+			if !bytes.Equal(input.data, output) {
+				_, _ = fmt.Fprintln(stdout, input.name)
+				code = exitFailure
+			}
+		case options.write:
+			if bytes.Equal(input.data, output) {
+				continue
+			}
+
+			if err := os.WriteFile(input.name, output, 0o644); err != nil {
+				_, _ = fmt.Fprintf(stderr, "vast %s: %v\n", name, err)
+				code = exitFailure
+			}
+		default:
+			_, _ = stdout.Write(output)
+		}
+	}
+
+	return code
+}
+
+func runFmt(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var options rewriteFlags
+	options.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	return rewrite("fmt", options, flags.Args(), stdin, stdout, stderr, func(data []byte) ([]byte, error) {
+		document, err := readDocument(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if isJSON(data) {
+			return encodeJSON(data, document)
+		}
+
+		return encodeXML(data, document)
+	})
+}
+
+var supportedVersions = []vast.Version{
+	vast.VAST20Version,
+	vast.VAST30Version,
+	vast.VAST40Version,
+	vast.VAST41Version,
+	vast.VAST42Version,
+	vast.VAST43Version,
+}
+
+func runConvert(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var options rewriteFlags
+	options.register(flags)
+	version := flags.String("version", "", "declared VAST version of the result")
+	to := flags.String("to", "xml", "output format: xml or json")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *to != "xml" && *to != "json" {
+		_, _ = fmt.Fprintf(stderr, "vast convert: unknown format %q\n", *to)
+		return exitUsage
+	}
+
+	if *version != "" && !isSupportedVersion(vast.Version(*version)) {
+		_, _ = fmt.Fprintf(stderr, "vast convert: unsupported version %q\n", *version)
+		return exitUsage
+	}
+
+	return rewrite("convert", options, flags.Args(), stdin, stdout, stderr, func(data []byte) ([]byte, error) {
+		document, err := readDocument(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if *version != "" {
+			setVersion(document, vast.Version(*version))
+		}
+
+		if *to == "json" {
+			return encodeJSON(data, document)
+		}
+
+		return encodeXML(data, document)
+	})
+}
+
+func isSupportedVersion(version vast.Version) bool {
+	for _, supportedVersion := range supportedVersions {
+		if version == supportedVersion {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setVersion changes the declared version. The VAST namespace was introduced in VAST 4.0.
+func setVersion(document *vast.VAST, version vast.Version) {
+	document.Version = version
+
+	if version.Compare(vast.VAST40Version) < 0 {
+		document.XMLNS = ""
+	} else {
+		document.XMLNS = vast.VASTNamespace
+	}
+}
+
+// isJSON reports whether a document uses the JSON representation.
+func isJSON(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// readDocument parses a document in XML or JSON representation and trims white space around values.
+func readDocument(data []byte) (*vast.VAST, error) {
+	read := vast.Read
+	if isJSON(data) {
+		read = vast.ReadJSON
+	}
+
+	document, err := read(io.NopCloser(bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	document.TrimSpace()
+
+	return document, nil
+}
+
+var errLostNodes = errors.New("cannot represent unsupported nodes")
+
+// encodeXML marshals the document and restores the nodes of the XML source which are not supported by the package.
+func encodeXML(source []byte, document *vast.VAST) ([]byte, error) {
+	output, err := document.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isJSON(source) {
+		output = restoreNodes(source, output)
+	}
+
+	return append(output, '\n'), nil
+}
+
+// encodeJSON marshals the document to JSON and fails if nodes of the XML source cannot be represented.
+func encodeJSON(source []byte, document *vast.VAST) ([]byte, error) {
+	if !isJSON(source) {
+		output, err := document.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		if lost := lostNodes(scanNodes(source), scanNodes(output)); len(lost) > 0 {
+			paths := make([]string, 0, len(lost))
+			for _, node := range lost {
+				paths = append(paths, node.path)
+			}
+
+			return nil, fmt.Errorf("%w: %s", errLostNodes, strings.Join(paths, ", "))
+		}
+	}
+
+	output, err := document.JSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(output, '\n'), nil
+}
+
+// node is an element or attribute of a document.
+// Paths are fully indexed, e.g. `/VAST[1]/Ad[1]/@id`.
+type node struct {
+	path   string
+	parent string
+	depth  int
+	// elementPath is the path of the element, or of the element of an attribute, without indexes.
+	elementPath string
+
+	// raw contains the source of elements and the name and value of attributes.
+	raw   []byte
+	attr  bool
+	value string
+
+	// start is the offset of the element, startTagEnd the offset of the closing bracket of the start tag,
+	// endTagStart the offset of the end tag and end the offset after the element.
+	start       int
+	startTagEnd int
+	endTagStart int
+	end         int
+}
+
+// scanNodes returns all elements and attributes of a document in document order. The default namespace declaration
+// depends on the version and is ignored.
+func scanNodes(data []byte) []*node {
+	var nodes []*node
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	stack := []*node{{}}
+	children := []map[string]int{{}}
+
+	for {
+		offset := int(decoder.InputOffset())
+
+		token, err := decoder.RawToken()
+		if err != nil {
+			return nodes
+		}
+
+		current := stack[len(stack)-1]
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			name := attributeName(token.Name)
+			children[len(children)-1][name]++
+
+			element := &node{
+				path:        fmt.Sprintf("%s/%s[%d]", current.path, name, children[len(children)-1][name]),
+				parent:      current.path,
+				depth:       len(stack) - 1,
+				elementPath: current.elementPath + "/" + name,
+				start:       offset + bytes.IndexByte(data[offset:], '<'),
+				startTagEnd: int(decoder.InputOffset()) - 1,
+			}
+			element.raw = data[element.start:]
+			nodes = append(nodes, element)
+
+			for _, attr := range token.Attr {
+				if attr.Name == (xml.Name{Local: "xmlns"}) {
+					continue
+				}
+
+				nodes = append(nodes, &node{
+					path:        element.path + "/@" + attributeName(attr.Name),
+					parent:      element.path,
+					elementPath: element.elementPath,
+					attr:        true,
+					raw:         []byte(attributeName(attr.Name)),
+					value:       attr.Value,
+				})
+			}
+
+			stack = append(stack, element)
+			children = append(children, map[string]int{})
+		case xml.EndElement:
+			current.endTagStart = offset
+			current.end = int(decoder.InputOffset())
+			current.raw = data[current.start:current.end]
+
+			stack = stack[:len(stack)-1]
+			children = children[:len(children)-1]
+		}
+	}
+}
+
+func attributeName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
+
+// declaredAttributes are the attributes of the elements supported by the package by their paths without indexes.
+// The encoder omits them if they have zero values, which is equivalent to the attribute value in the source.
+var declaredAttributes = map[string]map[string]bool{}
+
+func init() {
+	declareAttributes("/VAST", reflect.TypeFor[vast.VAST]())
+}
+
+func declareAttributes(path string, elementType reflect.Type) {
+	if declaredAttributes[path] == nil {
+		declaredAttributes[path] = map[string]bool{}
+	}
+
+	for i := range elementType.NumField() {
+		field := elementType.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("xml"), ",")
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer || fieldType.Kind() == reflect.Slice {
+			fieldType = fieldType.Elem()
+		}
+
+		switch {
+		case field.Anonymous:
+			declareAttributes(path, fieldType)
+		case name == "" || name == "-":
+		case strings.Contains(options, "attr"):
+			declaredAttributes[path][name] = true
+		case fieldType.Kind() == reflect.Struct && declaredAttributes[path+"/"+name] == nil:
+			declareAttributes(path+"/"+name, fieldType)
+		}
+	}
+}
+
+// lostNodes returns the nodes of the source which are missing in the output, except for descendants of lost elements
+// and declared attributes omitted by the encoder.
+func lostNodes(source, output []*node) []*node {
+	outputPaths := map[string]bool{}
+	for _, node := range output {
+		outputPaths[node.path] = true
+	}
+
+	var lost []*node
+
+	lostElements := map[string]bool{}
+
+	for _, node := range source {
+		if outputPaths[node.path] || lostElements[node.parent] {
+			if lostElements[node.parent] {
+				lostElements[node.path] = true
+			}
+
+			continue
+		}
+
+		if node.attr && declaredAttributes[node.elementPath][string(node.raw)] {
+			continue
+		}
+
+		lost = append(lost, node)
+		lostElements[node.path] = !node.attr
+	}
+
+	return lost
+}
+
+// restoreNodes inserts the nodes of the source which are missing in the output.
+// Lost elements are inserted before the next sibling in the source which is present in the output, or after the
+// previous one, or appended to the children of their parent if there is none. Lost attributes are appended to the
+// attributes of their element.
+func restoreNodes(source, output []byte) []byte {
+	sourceNodes, outputNodes := scanNodes(source), scanNodes(output)
+	elements := map[string]*node{}
+
+	for _, node := range outputNodes {
+		elements[node.path] = node
+	}
+
+	insertions := map[int][]byte{}
+
+	for _, lost := range lostNodes(sourceNodes, outputNodes) {
+		parent, ok := elements[lost.parent]
+		if !ok {
+			continue
+		}
+
+		if lost.attr {
+			var value bytes.Buffer
+			_ = xml.EscapeText(&value, []byte(lost.value))
+			insertions[parent.startTagEnd] = fmt.Appendf(insertions[parent.startTagEnd], " %s=\"%s\"", lost.raw, value.Bytes())
+
+			continue
+		}
+
+		indent := strings.Repeat("  ", parent.depth)
+
+		if previous, next := siblings(sourceNodes, lost, elements); next != nil {
+			insertions[next.start] = fmt.Appendf(insertions[next.start], "%s\n%s  ", lost.raw, indent)
+
+			continue
+		} else if previous != nil {
+			insertions[previous.end] = fmt.Appendf(insertions[previous.end], "\n%s  %s", indent, lost.raw)
+
+			continue
+		}
+
+		prefix := "  "
+
+		if offset := parent.endTagStart; len(insertions[offset]) == 0 && !strings.HasSuffix(string(output[:offset]), "\n"+indent) {
+			prefix = "\n" + indent + "  "
+		}
+
+		insertions[parent.endTagStart] = fmt.Appendf(insertions[parent.endTagStart], "%s%s\n%s", prefix, lost.raw, indent)
+	}
+
+	offsets := make([]int, 0, len(insertions))
+	for offset := range insertions {
+		offsets = append(offsets, offset)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+
+	for _, offset := range offsets {
+		output = slices.Insert(output, offset, insertions[offset]...)
+	}
+
+	return output
+}
+
+// siblings returns the output elements of the closest source siblings before and after an element which are present
+// in the output.
+func siblings(source []*node, element *node, elements map[string]*node) (previous, next *node) {
+	before := true
+
+	for _, sibling := range source {
+		switch {
+		case sibling == element:
+			before = false
+		case sibling.attr || sibling.parent != element.parent || elements[sibling.path] == nil:
+		case before:
+			previous = elements[sibling.path]
+		case next == nil:
+			next = elements[sibling.path]
+		}
+	}
+
+	return previous, next
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func copyFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	target := filepath.Join(t.TempDir(), filepath.Base(name))
+	if err := os.WriteFile(target, []byte(mustReadFile(t, name)), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return target
+}
+
+func TestRunFmt(t *testing.T) {
+	code, stdout, _ := runCommand(t, "", "fmt", fixture("iab/Inline_Simple.xml"))
+	if code != exitOK {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+
+	if !strings.HasPrefix(stdout, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<VAST version=\"4.2\" xmlns=\"http://www.iab.com/VAST\">\n  <Ad id=\"20001\">\n") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+
+	if code, again, _ := runCommand(t, stdout, "fmt"); code != exitOK || again != stdout {
+		t.Errorf("formatting is not idempotent: %s", again)
+	}
+}
+
+func TestRunFmt_unsupportedNodes(t *testing.T) {
+	code, stdout, _ := runCommand(t, "", "fmt", filepath.Join("testdata", "unsupported.xml"))
+	if code != exitOK {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+
+	if want := mustReadFile(t, filepath.Join("testdata", "unsupported.golden.xml")); stdout != want {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+}
+
+func TestRunFmt_trimSpace(t *testing.T) {
+	code, stdout, _ := runCommand(t, "<VAST version=\"4.2\">\n  <Error>\n    <![CDATA[https://example.com/error]]>\n  </Error>\n</VAST>", "fmt")
+	if code != exitOK {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+
+	if !strings.Contains(stdout, "<Error><![CDATA[https://example.com/error]]></Error>") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+}
+
+func TestRunFmt_check(t *testing.T) {
+	file := copyFixture(t, fixture("iab/Inline_Simple.xml"))
+
+	code, stdout, _ := runCommand(t, "", "fmt", "-check", file)
+	if code != exitFailure || stdout != file+"\n" {
+		t.Errorf("unexpected result: %d %s", code, stdout)
+	}
+
+	if mustReadFile(t, file) != mustReadFile(t, fixture("iab/Inline_Simple.xml")) {
+		t.Error("unexpected modification")
+	}
+}
+
+func TestRunFmt_write(t *testing.T) {
+	first := copyFixture(t, fixture("iab/Inline_Simple.xml"))
+	second := copyFixture(t, fixture("iab/Wrapper_Tag-test.xml"))
+
+	if code, stdout, _ := runCommand(t, "", "fmt", "-w", first, second); code != exitOK || stdout != "" {
+		t.Errorf("unexpected result: %d %s", code, stdout)
+	}
+
+	if code, stdout, _ := runCommand(t, "", "fmt", "-check", first, second); code != exitOK || stdout != "" {
+		t.Errorf("unexpected result: %d %s", code, stdout)
+	}
+
+	if code, _, _ := runCommand(t, "", "fmt", "-w", first); code != exitOK {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+}
+
+func TestRunFmt_json(t *testing.T) {
+	_, jsonOutput, _ := runCommand(t, "", "convert", "-to", "json", fixture("iab/Inline_Simple.xml"))
+
+	file := filepath.Join(t.TempDir(), "Inline_Simple.json")
+	if err := os.WriteFile(file, []byte(strings.Replace(jsonOutput, "\n  ", "\n    ", 1)), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if code, stdout, _ := runCommand(t, "", "fmt", "-w", file); code != exitOK || stdout != "" {
+		t.Errorf("unexpected result: %d %s", code, stdout)
+	}
+
+	if got := mustReadFile(t, file); got != jsonOutput {
+		t.Errorf("unexpected output: %s", got)
+	}
+}
+
+func TestRunFmt_writeStdin(t *testing.T) {
+	if code, _, _ := runCommand(t, "", "fmt", "-w"); code != exitUsage {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+}
+
+func TestRunFmt_invalid(t *testing.T) {
+	code, _, stderr := runCommand(t, "<VAST>", "fmt")
+	if code != exitFailure || !strings.HasPrefix(stderr, "vast fmt: <stdin>: cannot unmarshal VAST: ") {
+		t.Errorf("unexpected result: %d %s", code, stderr)
+	}
+}
+
+func TestRunFmt_invalidFlag(t *testing.T) {
+	if code, _, _ := runCommand(t, "", "fmt", "-unknown"); code != exitUsage {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+}
+
+func TestRunFmt_missingFile(t *testing.T) {
+	if code, _, _ := runCommand(t, "", "fmt", "missing.xml"); code != exitFailure {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+}
+
+func TestRunConvert_json(t *testing.T) {
+	code, jsonOutput, _ := runCommand(t, "", "convert", "-to", "json", fixture("iab/Inline_Simple.xml"))
+	if code != exitOK || !strings.HasPrefix(jsonOutput, "{\n  \"ad\": [") {
+		t.Errorf("unexpected result: %d %s", code, jsonOutput)
+	}
+
+	_, want, _ := runCommand(t, "", "fmt", fixture("iab/Inline_Simple.xml"))
+
+	if code, xmlOutput, _ := runCommand(t, jsonOutput, "convert"); code != exitOK || xmlOutput != want {
+		t.Errorf("unexpected result: %d %s", code, xmlOutput)
+	}
+}
+
+func TestRunConvert_jsonUnsupportedNodes(t *testing.T) {
+	code, _, stderr := runCommand(t, "", "convert", "-to", "json", filepath.Join("testdata", "unsupported.xml"))
+	if code != exitFailure || !strings.Contains(stderr, "cannot represent unsupported nodes: /VAST[1]/Ad[1]/@bar, /VAST[1]/Ad[1]/InLine[1]/@foo, "+
+		"/VAST[1]/Ad[1]/InLine[1]/Foo[1], /VAST[1]/Ad[1]/InLine[1]/Creatives[1]/Creative[1]/@empty, "+
+		"/VAST[1]/Ad[1]/InLine[1]/Creatives[1]/Creative[1]/X[1], /VAST[1]/Ad[1]/InLine[1]/Creatives[1]/Creative[1]/Y[1], "+
+		"/VAST[1]/Ad[1]/InLine[1]/Bar[1], /VAST[1]/Ad[1]/InLine[1]/Baz[1]\n") {
+		t.Errorf("unexpected result: %d %s", code, stderr)
+	}
+}
+
+func TestRunConvert_version(t *testing.T) {
+	code, stdout, _ := runCommand(t, "", "convert", "-version", "3.0", fixture("iab/Inline_Simple.xml"))
+	if code != exitOK || !strings.Contains(stdout, "\n<VAST version=\"3.0\">\n") {
+		t.Errorf("unexpected result: %d %s", code, stdout)
+	}
+
+	code, stdout, _ = runCommand(t, stdout, "convert", "-version", "4.1")
+	if code != exitOK || !strings.Contains(stdout, "\n<VAST version=\"4.1\" xmlns=\"http://www.iab.com/VAST\">\n") {
+		t.Errorf("unexpected result: %d %s", code, stdout)
+	}
+}
+
+func TestRunConvert_invalidFlags(t *testing.T) {
+	testCases := [][]string{
+		{"convert", "-to", "yaml"},
+		{"convert", "-version", "1.0"},
+		{"convert", "-unknown"},
+	}
+
+	for _, args := range testCases {
+		if code, _, _ := runCommand(t, "", args...); code != exitUsage {
+			t.Errorf("unexpected exit code for %v: %d", args, code)
+		}
+	}
+}
+
+func TestRunConvert_invalid(t *testing.T) {
+	if code, _, _ := runCommand(t, "{", "convert"); code != exitFailure {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+}
@@ -0,0 +1,358 @@
+package vast
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrCompanionsRequired = errors.New("required companions cannot be displayed")
+
+// ResourceType is the type of a resource of companions, non-linear ads and icons.
+type ResourceType string
+
+const (
+	StaticResourceType ResourceType = "static"
+	IFrameResourceType ResourceType = "iframe"
+	HTMLResourceType   ResourceType = "html"
+)
+
+var defaultResourceTypes = []ResourceType{StaticResourceType, IFrameResourceType, HTMLResourceType}
+
+// CompanionSlot is an area of a page that displays a companion. ID matches the adSlotId of companions, companions
+// without adSlotId fit any slot. Width and Height are in CSS pixels, companions larger than the slot do not fit.
+type CompanionSlot struct {
+	ID     string
+	Width  int
+	Height int
+}
+
+// CompanionSelector selects the companions displayed with an ad.
+//
+// PixelRatio is the device pixel ratio, 1 if zero. Companions whose pxratio matches are preferred, followed by
+// companions with the next higher and then the next lower pxratio.
+// ResourceTypes lists the supported resource types in order of preference, static, iframe and HTML resources are
+// preferred in this order if it is empty. CreativeTypes restricts the MIME types of static resources, all types are
+// supported if it is empty.
+// EndCard and Concurrent are set if the player displays companions with the end-card and concurrent rendering
+// modes itself, i.e. after the linear creative or alongside it within the player.
+type CompanionSelector struct {
+	Slots         []CompanionSlot
+	PixelRatio    float64
+	ResourceTypes []ResourceType
+	CreativeTypes []string
+	EndCard       bool
+	Concurrent    bool
+}
+
+// CompanionResource is the resource of a companion to display. CreativeType is only set for static resources.
+type CompanionResource struct {
+	Type         ResourceType
+	Value        string
+	CreativeType string
+}
+
+// CompanionPlacement is a companion displayed in a slot, or by the player if Slot is nil.
+type CompanionPlacement struct {
+	Slot      *CompanionSlot
+	Companion *CompanionAd
+	Resource  CompanionResource
+}
+
+// SkippedCompanion is a companion which is not displayed. Index is the index within the CompanionAds.
+type SkippedCompanion struct {
+	Index     int
+	Companion *CompanionAd
+	Reason    string
+}
+
+// CompanionSelection are the companions to display. Placements are in the order of the slots.
+type CompanionSelection struct {
+	Placements []CompanionPlacement
+	EndCard    *CompanionPlacement
+	Concurrent *CompanionPlacement
+	Skipped    []SkippedCompanion
+}
+
+// companionCandidate is a companion with its chosen resource.
+type companionCandidate struct {
+	index     int
+	companion *CompanionAd
+	resource  CompanionResource
+	group     string
+}
+
+// Select selects the companions to display. Companions with the same adSlotId are alternatives, e.g. for different
+// pixel ratios, and at most one of them is displayed. The same applies to end-card and concurrent companions.
+//
+// The Required policy of the CompanionAds is honoured: if `all` companions are required and one of them, or of each
+// group of alternatives, cannot be displayed, or if `any` companion is required and none can be displayed, the ad
+// must be rejected. Select then returns the selection along with an error wrapping ErrCompanionsRequired.
+func (s *CompanionSelector) Select(companionAds *CompanionAdsCollection) (*CompanionSelection, error) {
+	selection := &CompanionSelection{}
+
+	if companionAds == nil || len(companionAds.Companion) == 0 {
+		return selection, nil
+	}
+
+	var (
+		candidates []*companionCandidate
+		groups     []string
+		skip       = func(index int, reason string) {
+			selection.Skipped = append(selection.Skipped, SkippedCompanion{Index: index, Companion: &companionAds.Companion[index], Reason: reason})
+		}
+	)
+
+	for i := range companionAds.Companion {
+		companion := &companionAds.Companion[i]
+
+		group := companionGroup(i, companion)
+		if !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+
+		switch companion.RenderingMode {
+		case EndCardRenderingMode:
+			if !s.EndCard {
+				skip(i, "end-card rendering mode not supported")
+				continue
+			}
+		case ConcurrentRenderingMode:
+			if !s.Concurrent {
+				skip(i, "concurrent rendering mode not supported")
+				continue
+			}
+		}
+
+		resource, ok := s.resource(companion)
+		if !ok {
+			skip(i, "no supported resource")
+			continue
+		}
+
+		candidates = append(candidates, &companionCandidate{index: i, companion: companion, resource: resource, group: group})
+	}
+
+	placed := map[*companionCandidate]bool{}
+	place := func(slot *CompanionSlot, candidate *companionCandidate) *CompanionPlacement {
+		placed[candidate] = true
+		return &CompanionPlacement{Slot: slot, Companion: candidate.companion, Resource: candidate.resource}
+	}
+
+	selection.EndCard = s.placeInPlayer(candidates, EndCardRenderingMode, place)
+	selection.Concurrent = s.placeInPlayer(candidates, ConcurrentRenderingMode, place)
+
+	usedGroups := map[string]bool{}
+
+	for i := range s.Slots {
+		slot := &s.Slots[i]
+
+		var best *companionCandidate
+
+		for _, candidate := range candidates {
+			if placed[candidate] || usedGroups[candidate.group] || !isDefaultRenderingMode(candidate.companion.RenderingMode) || !fitsSlot(candidate.companion, slot) {
+				continue
+			}
+
+			if best == nil || s.betterForSlot(candidate, best, slot) {
+				best = candidate
+			}
+		}
+
+		if best != nil {
+			selection.Placements = append(selection.Placements, *place(slot, best))
+
+			// Other companions of a slot are alternatives, independent companions can fill further slots.
+			if best.companion.AdSlotID != "" {
+				usedGroups[best.group] = true
+			}
+		}
+	}
+
+	displayedGroups := map[string]bool{}
+
+	for candidate := range placed {
+		displayedGroups[candidate.group] = true
+	}
+
+	for _, candidate := range candidates {
+		switch {
+		case placed[candidate]:
+		case displayedGroups[candidate.group]:
+			skip(candidate.index, "alternative displayed")
+		default:
+			skip(candidate.index, "no matching slot")
+		}
+	}
+
+	slices.SortStableFunc(selection.Skipped, func(a, b SkippedCompanion) int {
+		return a.Index - b.Index
+	})
+
+	switch companionAds.Required {
+	case AllRequired:
+		for _, group := range groups {
+			if !displayedGroups[group] {
+				return selection, fmt.Errorf("%w: %s", ErrCompanionsRequired, describeGroup(group))
+			}
+		}
+	case AnyRequired:
+		if len(displayedGroups) == 0 {
+			return selection, fmt.Errorf("%w: no companion can be displayed", ErrCompanionsRequired)
+		}
+	}
+
+	return selection, nil
+}
+
+// resource returns the most preferred supported resource of a companion.
+func (s *CompanionSelector) resource(companion *CompanionAd) (CompanionResource, bool) {
+	resourceTypes := s.ResourceTypes
+	if len(resourceTypes) == 0 {
+		resourceTypes = defaultResourceTypes
+	}
+
+	for _, resourceType := range resourceTypes {
+		switch resourceType {
+		case StaticResourceType:
+			for _, resource := range companion.StaticResource {
+				if value := strings.TrimSpace(resource.Value); value != "" && s.supportsCreativeType(resource.CreativeType) {
+					return CompanionResource{Type: StaticResourceType, Value: value, CreativeType: resource.CreativeType}, true
+				}
+			}
+		case IFrameResourceType:
+			if value, ok := firstResource(companion.IFrameResource); ok {
+				return CompanionResource{Type: IFrameResourceType, Value: value}, true
+			}
+		case HTMLResourceType:
+			if value, ok := firstResource(companion.HTMLResource); ok {
+				return CompanionResource{Type: HTMLResourceType, Value: value}, true
+			}
+		}
+	}
+
+	return CompanionResource{}, false
+}
+
+func (s *CompanionSelector) supportsCreativeType(creativeType string) bool {
+	if len(s.CreativeTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(creativeType)
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(s.CreativeTypes, func(supported string) bool {
+		return strings.EqualFold(supported, mediaType)
+	})
+}
+
+func firstResource(resources []CData) (string, bool) {
+	for _, resource := range resources {
+		if value := strings.TrimSpace(resource.Value); value != "" {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// placeInPlayer places the best companion with the rendering mode in the player.
+func (s *CompanionSelector) placeInPlayer(candidates []*companionCandidate, mode RenderingMode,
+	place func(*CompanionSlot, *companionCandidate) *CompanionPlacement) *CompanionPlacement {
+	var best *companionCandidate
+
+	for _, candidate := range candidates {
+		if candidate.companion.RenderingMode == mode && (best == nil || s.comparePixelRatios(candidate.companion, best.companion) < 0) {
+			best = candidate
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	return place(nil, best)
+}
+
+// betterForSlot reports whether a candidate is better for the slot than the current best. Companions for the slot
+// are preferred over companions without adSlotId, then the pixel ratio and the size decide.
+func (s *CompanionSelector) betterForSlot(candidate, best *companionCandidate, slot *CompanionSlot) bool {
+	if matches, bestMatches := candidate.companion.AdSlotID == slot.ID, best.companion.AdSlotID == slot.ID; matches != bestMatches {
+		return matches
+	}
+
+	if order := s.comparePixelRatios(candidate.companion, best.companion); order != 0 {
+		return order < 0
+	}
+
+	return candidate.companion.Width*candidate.companion.Height > best.companion.Width*best.companion.Height
+}
+
+// comparePixelRatios compares the pxratio of two companions, lower is better. Exact matches come first, higher
+// ratios, which are scaled down, come before lower ratios, which are scaled up.
+func (s *CompanionSelector) comparePixelRatios(a, b *CompanionAd) int {
+	pixelRatio := s.PixelRatio
+	if pixelRatio <= 0 {
+		pixelRatio = 1
+	}
+
+	ratioA, ratioB := companionPixelRatio(a), companionPixelRatio(b)
+	if upscaledA, upscaledB := ratioA < pixelRatio, ratioB < pixelRatio; upscaledA != upscaledB {
+		if upscaledA {
+			return 1
+		}
+
+		return -1
+	}
+
+	return cmp.Compare(math.Abs(ratioA-pixelRatio), math.Abs(ratioB-pixelRatio))
+}
+
+func companionPixelRatio(companion *CompanionAd) float64 {
+	if companion.PXRatio <= 0 {
+		return 1
+	}
+
+	return companion.PXRatio
+}
+
+func isDefaultRenderingMode(mode RenderingMode) bool {
+	return mode == "" || mode == DefaultRenderingMode
+}
+
+// fitsSlot reports whether a companion fits the slot. Companions without size fit any slot.
+func fitsSlot(companion *CompanionAd, slot *CompanionSlot) bool {
+	if companion.AdSlotID != "" && companion.AdSlotID != slot.ID {
+		return false
+	}
+
+	return companion.Width <= slot.Width && companion.Height <= slot.Height
+}
+
+// companionGroup returns the group of alternatives of a companion.
+func companionGroup(index int, companion *CompanionAd) string {
+	switch {
+	case !isDefaultRenderingMode(companion.RenderingMode):
+		return string(companion.RenderingMode)
+	case companion.AdSlotID != "":
+		return "slot " + companion.AdSlotID
+	default:
+		return "companion " + strconv.Itoa(index+1)
+	}
+}
+
+func describeGroup(group string) string {
+	if mode := RenderingMode(group); mode == EndCardRenderingMode || mode == ConcurrentRenderingMode {
+		return "no " + group + " companion can be displayed"
+	}
+
+	return group + " cannot be displayed"
+}
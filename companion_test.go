@@ -0,0 +1,151 @@
+package vast_test
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"testing"
+)
+
+func companionAds(required vast.Required) *vast.CompanionAdsCollection {
+	return &vast.CompanionAdsCollection{
+		Required: required,
+		Companion: []vast.CompanionAd{
+			{AdSlotID: "banner", Width: 300, Height: 250, PXRatio: 1, StaticResource: []vast.StaticResource{{Value: "https://example.com/banner.png", CreativeType: "image/png"}}},
+			{AdSlotID: "banner", Width: 300, Height: 250, PXRatio: 2, StaticResource: []vast.StaticResource{{Value: "https://example.com/banner@2x.png", CreativeType: "image/png"}}},
+			{Width: 728, Height: 90, IFrameResource: []vast.CData{{Value: " https://example.com/leaderboard.html "}}, HTMLResource: []vast.CData{{Value: "<div></div>"}}},
+			{RenderingMode: vast.EndCardRenderingMode, Width: 640, Height: 360, HTMLResource: []vast.CData{{Value: "<div>end card</div>"}}},
+			{RenderingMode: vast.ConcurrentRenderingMode, Width: 320, Height: 50, StaticResource: []vast.StaticResource{{Value: "https://example.com/overlay.gif", CreativeType: "image/gif"}}},
+		},
+	}
+}
+
+func TestCompanionSelector_Select(t *testing.T) {
+	selector := &vast.CompanionSelector{
+		Slots:      []vast.CompanionSlot{{ID: "banner", Width: 300, Height: 250}, {ID: "top", Width: 970, Height: 250}},
+		PixelRatio: 2,
+		EndCard:    true,
+	}
+
+	collection := companionAds(vast.NoneRequired)
+
+	got, err := selector.Select(collection)
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	want := &vast.CompanionSelection{
+		Placements: []vast.CompanionPlacement{
+			{Slot: &selector.Slots[0], Companion: &collection.Companion[1], Resource: vast.CompanionResource{Type: vast.StaticResourceType, Value: "https://example.com/banner@2x.png", CreativeType: "image/png"}},
+			{Slot: &selector.Slots[1], Companion: &collection.Companion[2], Resource: vast.CompanionResource{Type: vast.IFrameResourceType, Value: "https://example.com/leaderboard.html"}},
+		},
+		EndCard: &vast.CompanionPlacement{Companion: &collection.Companion[3], Resource: vast.CompanionResource{Type: vast.HTMLResourceType, Value: "<div>end card</div>"}},
+		Skipped: []vast.SkippedCompanion{
+			{Index: 0, Companion: &collection.Companion[0], Reason: "alternative displayed"},
+			{Index: 4, Companion: &collection.Companion[4], Reason: "concurrent rendering mode not supported"},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong selection: %s", diff)
+	}
+}
+
+func TestCompanionSelector_Select_preferences(t *testing.T) {
+	selector := &vast.CompanionSelector{
+		Slots:         []vast.CompanionSlot{{ID: "banner", Width: 300, Height: 250}, {ID: "side", Width: 728, Height: 90}},
+		PixelRatio:    1.5,
+		ResourceTypes: []vast.ResourceType{vast.HTMLResourceType, vast.StaticResourceType},
+		CreativeTypes: []string{"image/gif"},
+		Concurrent:    true,
+	}
+
+	collection := companionAds(vast.AnyRequired)
+
+	got, err := selector.Select(collection)
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	var placed []string
+	for _, placement := range got.Placements {
+		placed = append(placed, placement.Slot.ID+" "+placement.Resource.Value)
+	}
+
+	if diff := cmp.Diff([]string{"side <div></div>"}, placed); diff != "" {
+		t.Errorf("wrong placements: %s", diff)
+	}
+
+	if got.Concurrent == nil || got.Concurrent.Resource.Value != "https://example.com/overlay.gif" {
+		t.Errorf("wrong concurrent companion: %v", got.Concurrent)
+	}
+
+	var reasons []string
+	for _, skipped := range got.Skipped {
+		reasons = append(reasons, skipped.Reason)
+	}
+
+	want := []string{"no supported resource", "no supported resource", "end-card rendering mode not supported"}
+	if diff := cmp.Diff(want, reasons); diff != "" {
+		t.Errorf("wrong reasons: %s", diff)
+	}
+}
+
+func TestCompanionSelector_Select_pixelRatio(t *testing.T) {
+	collection := &vast.CompanionAdsCollection{Companion: []vast.CompanionAd{
+		{Width: 300, Height: 250, PXRatio: 1, HTMLResource: []vast.CData{{Value: "1x"}}},
+		{Width: 300, Height: 250, PXRatio: 3, HTMLResource: []vast.CData{{Value: "3x"}}},
+		{Width: 300, Height: 250, PXRatio: 2, HTMLResource: []vast.CData{{Value: "2x"}}},
+		{Width: 200, Height: 200, HTMLResource: []vast.CData{{Value: "small"}}},
+	}}
+
+	tests := map[float64]string{0: "1x", 1: "1x", 1.5: "2x", 2.5: "3x", 4: "3x"}
+
+	for pixelRatio, expected := range tests {
+		selector := &vast.CompanionSelector{Slots: []vast.CompanionSlot{{Width: 300, Height: 250}}, PixelRatio: pixelRatio}
+
+		got, err := selector.Select(collection)
+		if err != nil || len(got.Placements) != 1 || got.Placements[0].Resource.Value != expected {
+			t.Errorf("wrong placement for %v: %v", pixelRatio, got.Placements)
+		}
+	}
+}
+
+func TestCompanionSelector_Select_required(t *testing.T) {
+	tests := []struct {
+		selector vast.CompanionSelector
+		required vast.Required
+		expected string
+	}{
+		{vast.CompanionSelector{}, vast.AllRequired, "required companions cannot be displayed: slot banner cannot be displayed"},
+		{vast.CompanionSelector{Slots: []vast.CompanionSlot{{ID: "banner", Width: 300, Height: 250}}}, vast.AllRequired, "required companions cannot be displayed: companion 3 cannot be displayed"},
+		{vast.CompanionSelector{Slots: []vast.CompanionSlot{{ID: "banner", Width: 300, Height: 250}, {Width: 728, Height: 90}}}, vast.AllRequired, "required companions cannot be displayed: no end-card companion can be displayed"},
+		{vast.CompanionSelector{Slots: []vast.CompanionSlot{{ID: "banner", Width: 300, Height: 250}, {Width: 728, Height: 90}}, EndCard: true, Concurrent: true}, vast.AllRequired, ""},
+		{vast.CompanionSelector{Slots: []vast.CompanionSlot{{Width: 100, Height: 100}}}, vast.AnyRequired, "required companions cannot be displayed: no companion can be displayed"},
+		{vast.CompanionSelector{EndCard: true}, vast.AnyRequired, ""},
+		{vast.CompanionSelector{}, vast.NoneRequired, ""},
+		{vast.CompanionSelector{}, "", ""},
+	}
+
+	for i, testCase := range tests {
+		_, err := testCase.selector.Select(companionAds(testCase.required))
+
+		switch {
+		case testCase.expected == "" && err != nil:
+			t.Errorf("unexpected error for %d: %v", i, err)
+		case testCase.expected != "" && (!errors.Is(err, vast.ErrCompanionsRequired) || err.Error() != testCase.expected):
+			t.Errorf("wrong error for %d: %v", i, err)
+		}
+	}
+}
+
+func TestCompanionSelector_Select_empty(t *testing.T) {
+	selector := &vast.CompanionSelector{}
+
+	for _, collection := range []*vast.CompanionAdsCollection{nil, {Required: vast.AllRequired}} {
+		got, err := selector.Select(collection)
+		if err != nil || got == nil || got.Placements != nil || got.Skipped != nil {
+			t.Errorf("unexpected selection: %v %v", got, err)
+		}
+	}
+}
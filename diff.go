@@ -0,0 +1,271 @@
+package vast
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type ChangeType string
+
+const (
+	AddedChange    ChangeType = "added"
+	RemovedChange  ChangeType = "removed"
+	ModifiedChange ChangeType = "modified"
+)
+
+// Change describes a semantic difference between two VAST documents.
+// Path is an XPath-like expression of the affected node, e.g. `/VAST/Ad[@id="1"]/InLine/AdTitle`.
+// Old is nil for added nodes, New is nil for removed nodes.
+type Change struct {
+	Type ChangeType
+	Path string
+	Old  any
+	New  any
+}
+
+// String renders the change in a human-readable form.
+func (c Change) String() string {
+	switch c.Type {
+	case AddedChange:
+		return "+ " + c.Path + formatChangeValue(c.New)
+	case RemovedChange:
+		return "- " + c.Path + formatChangeValue(c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %#v -> %#v", c.Path, c.Old, c.New)
+	}
+}
+
+func formatChangeValue(value any) string {
+	if reflect.ValueOf(value).Kind() == reflect.Struct {
+		return ""
+	}
+
+	return fmt.Sprintf(": %#v", value)
+}
+
+// FormatChanges renders a list of changes in a human-readable form, one change per line.
+func FormatChanges(changes []Change) string {
+	var builder strings.Builder
+
+	for _, change := range changes {
+		builder.WriteString(change.String())
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// Diff compares two VAST documents structurally.
+// Repeated elements are matched by their `id` or `adId` attribute, their URL or value, or their `vendor` or `type` attribute,
+// whichever identifies the elements unambiguously. Otherwise, they are matched by position.
+func Diff(a, b *VAST) []Change {
+	differ := &differ{}
+	differ.diff("/VAST", reflect.ValueOf(a), reflect.ValueOf(b))
+
+	return differ.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+var cdataType = reflect.TypeOf(CData{})
+
+func (d *differ) add(changeType ChangeType, path string, old, new reflect.Value) {
+	change := Change{Type: changeType, Path: path}
+
+	if old.IsValid() {
+		change.Old = changeValue(old)
+	}
+
+	if new.IsValid() {
+		change.New = changeValue(new)
+	}
+
+	d.changes = append(d.changes, change)
+}
+
+func changeValue(value reflect.Value) any {
+	if value.Type() == cdataType {
+		return value.Field(0).Interface()
+	}
+
+	return value.Interface()
+}
+
+func (d *differ) diff(path string, a, b reflect.Value) {
+	switch a.Kind() {
+	case reflect.Pointer:
+		switch {
+		case a.IsNil() && b.IsNil():
+		case a.IsNil():
+			d.add(AddedChange, path, reflect.Value{}, b.Elem())
+		case b.IsNil():
+			d.add(RemovedChange, path, a.Elem(), reflect.Value{})
+		default:
+			d.diff(path, a.Elem(), b.Elem())
+		}
+	case reflect.Struct:
+		if a.Type() == cdataType {
+			d.diff(path, a.Field(0), b.Field(0))
+			return
+		}
+
+		d.diffStruct(path, a, b)
+	case reflect.Slice:
+		d.diffSlice(path, a, b)
+	default:
+		if a.Interface() != b.Interface() {
+			d.add(ModifiedChange, path, a, b)
+		}
+	}
+}
+
+func (d *differ) diffStruct(path string, a, b reflect.Value) {
+	for i := range a.NumField() {
+		field := a.Type().Field(i)
+		if field.Anonymous {
+			d.diffStruct(path, a.Field(i), b.Field(i))
+			continue
+		}
+
+		d.diff(path+fieldPath(field), a.Field(i), b.Field(i))
+	}
+}
+
+// fieldPath returns the path segment of a field. Fields which are not encoded as XML, like the name of a Node, are
+// identified by their Go name.
+func fieldPath(field reflect.StructField) string {
+	name, options, _ := strings.Cut(field.Tag.Get("xml"), ",")
+
+	switch {
+	case name == "-":
+		return "/" + field.Name
+	case strings.Contains(options, "attr") && name == "":
+		return "/@*"
+	case strings.Contains(options, "attr"):
+		return "/@" + name
+	case strings.Contains(options, "any"):
+		return "/*"
+	case name == "":
+		return "/text()"
+	default:
+		return "/" + name
+	}
+}
+
+// diffKeys lists the fields which may identify repeated elements, in order of preference.
+var diffKeys = []struct {
+	field     string
+	predicate string
+}{
+	{"ID", "@id"},
+	{"AdID", "@adId"},
+	{"Value", "."},
+	{"Vendor", "@vendor"},
+	{"Type", "@type"},
+}
+
+func (d *differ) diffSlice(path string, a, b reflect.Value) {
+	keyOf := sliceKey(a, b)
+	if keyOf == nil {
+		d.diffSliceByPosition(path, a, b)
+		return
+	}
+
+	indexB := make(map[string]int, b.Len())
+	for i := range b.Len() {
+		indexB[keyOf(b.Index(i))] = i
+	}
+
+	matched := make(map[string]bool, a.Len())
+
+	for i := range a.Len() {
+		key := keyOf(a.Index(i))
+		elementPath := path + key
+
+		j, ok := indexB[key]
+		if !ok {
+			d.add(RemovedChange, elementPath, a.Index(i), reflect.Value{})
+			continue
+		}
+
+		matched[key] = true
+		d.diff(elementPath, a.Index(i), b.Index(j))
+	}
+
+	for i := range b.Len() {
+		if key := keyOf(b.Index(i)); !matched[key] {
+			d.add(AddedChange, path+key, reflect.Value{}, b.Index(i))
+		}
+	}
+}
+
+func (d *differ) diffSliceByPosition(path string, a, b reflect.Value) {
+	for i := range max(a.Len(), b.Len()) {
+		elementPath := fmt.Sprintf("%s[%d]", path, i+1)
+
+		switch {
+		case i >= b.Len():
+			d.add(RemovedChange, elementPath, a.Index(i), reflect.Value{})
+		case i >= a.Len():
+			d.add(AddedChange, elementPath, reflect.Value{}, b.Index(i))
+		default:
+			d.diff(elementPath, a.Index(i), b.Index(i))
+		}
+	}
+}
+
+// sliceKey returns a function rendering the predicate which identifies an element of both slices unambiguously.
+// It returns nil if there is no such key.
+func sliceKey(a, b reflect.Value) func(reflect.Value) string {
+	if a.Type().Elem().Kind() != reflect.Struct {
+		keyOf := func(element reflect.Value) string {
+			return fmt.Sprintf("[.=%q]", fmt.Sprint(element.Interface()))
+		}
+
+		if uniqueKeys(a, keyOf) && uniqueKeys(b, keyOf) {
+			return keyOf
+		}
+
+		return nil
+	}
+
+	for _, key := range diffKeys {
+		field, ok := a.Type().Elem().FieldByName(key.field)
+		if !ok || field.Type.Kind() != reflect.String {
+			continue
+		}
+
+		keyOf := func(element reflect.Value) string {
+			value := element.FieldByIndex(field.Index).String()
+			if value == "" {
+				return ""
+			}
+
+			return fmt.Sprintf("[%s=%q]", key.predicate, value)
+		}
+
+		if uniqueKeys(a, keyOf) && uniqueKeys(b, keyOf) {
+			return keyOf
+		}
+	}
+
+	return nil
+}
+
+func uniqueKeys(slice reflect.Value, keyOf func(reflect.Value) string) bool {
+	seen := make(map[string]bool, slice.Len())
+
+	for i := range slice.Len() {
+		key := keyOf(slice.Index(i))
+		if key == "" || seen[key] {
+			return false
+		}
+
+		seen[key] = true
+	}
+
+	return true
+}
@@ -0,0 +1,129 @@
+package vast_test
+
+import (
+	"encoding/xml"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"testing"
+)
+
+func mustReadFixture(t *testing.T, fixture string) *vast.VAST {
+	t.Helper()
+
+	testVAST, err := vast.Read(mustOpenFixture(fixture))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return testVAST
+}
+
+func TestDiff_equal(t *testing.T) {
+	for _, testCase := range iabFixtures {
+		t.Run(testCase, func(t *testing.T) {
+			if changes := vast.Diff(mustReadFixture(t, testCase), mustReadFixture(t, testCase)); len(changes) != 0 {
+				t.Errorf("unexpected changes: %v", changes)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := mustReadFixture(t, "iab/Inline_Linear_Tag-test.xml")
+	b := mustReadFixture(t, "iab/Inline_Linear_Tag-test.xml")
+
+	b.Ad[0].InLine.AdTitle = "Changed title"
+	mediaFiles := b.Ad[0].InLine.Creatives.Creative[0].Linear.MediaFiles.MediaFile
+	mediaFiles[0], mediaFiles[1] = mediaFiles[1], mediaFiles[0]
+	mediaFiles[0].Width = 1920
+	b.Ad[0].InLine.Creatives.Creative[0].Linear.MediaFiles.MediaFile = mediaFiles[:2]
+	b.Ad[0].InLine.Impression = append(b.Ad[0].InLine.Impression, vast.Impression{Value: "https://example.com/impression"})
+	b.Ad[0].InLine.Pricing = nil
+
+	got := vast.Diff(a, b)
+	want := []vast.Change{
+		{
+			Type: vast.AddedChange,
+			Path: `/VAST/Ad[@id="20001"]/InLine/Impression[.="https://example.com/impression"]`,
+			New:  vast.Impression{Value: "https://example.com/impression"},
+		},
+		{
+			Type: vast.RemovedChange,
+			Path: `/VAST/Ad[@id="20001"]/InLine/Pricing`,
+			Old:  *a.Ad[0].InLine.Pricing,
+		},
+		{
+			Type: vast.ModifiedChange,
+			Path: `/VAST/Ad[@id="20001"]/InLine/AdTitle`,
+			Old:  "iabtechlab video ad",
+			New:  "Changed title",
+		},
+		{
+			Type: vast.ModifiedChange,
+			Path: `/VAST/Ad[@id="20001"]/InLine/Creatives/Creative[@id="5480"]/Linear/MediaFiles/MediaFile[@id="5244"]/@width`,
+			Old:  854,
+			New:  1920,
+		},
+		{
+			Type: vast.RemovedChange,
+			Path: `/VAST/Ad[@id="20001"]/InLine/Creatives/Creative[@id="5480"]/Linear/MediaFiles/MediaFile[@id="5246"]`,
+			Old:  a.Ad[0].InLine.Creatives.Creative[0].Linear.MediaFiles.MediaFile[2],
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong changes: %s", diff)
+	}
+}
+
+func TestDiff_position(t *testing.T) {
+	a := &vast.VAST{Ad: []vast.Ad{{Sequence: 1}, {Sequence: 2}}}
+	b := &vast.VAST{Ad: []vast.Ad{{Sequence: 1}}, Error: []vast.CData{{Value: "a"}, {Value: "a"}}}
+
+	got := vast.FormatChanges(vast.Diff(a, b))
+	want := "- /VAST/Ad[2]\n" +
+		"+ /VAST/Error[1]: \"a\"\n" +
+		"+ /VAST/Error[2]: \"a\"\n"
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong changes: %s", diff)
+	}
+}
+
+func TestDiff_scalars(t *testing.T) {
+	a := &vast.VAST{Ad: []vast.Ad{{InLine: &vast.InLine{Creatives: vast.InLineCreatives{Creative: []vast.InLineCreative{{
+		CompanionAds: &vast.CompanionAdsCollection{Companion: []vast.CompanionAd{{CompanionClickTracking: []string{"a", "b"}}}},
+	}}}}}}}
+	b := &vast.VAST{Version: vast.VAST42Version, Ad: []vast.Ad{{InLine: &vast.InLine{Creatives: vast.InLineCreatives{Creative: []vast.InLineCreative{{
+		CompanionAds: &vast.CompanionAdsCollection{Companion: []vast.CompanionAd{{CompanionClickTracking: []string{"b", "c"}}}},
+	}}}}}}}
+
+	got := vast.FormatChanges(vast.Diff(a, b))
+	want := "- /VAST/Ad[1]/InLine/Creatives/Creative[1]/CompanionAds/Companion[1]/CompanionClickTracking[.=\"a\"]: \"a\"\n" +
+		"+ /VAST/Ad[1]/InLine/Creatives/Creative[1]/CompanionAds/Companion[1]/CompanionClickTracking[.=\"c\"]: \"c\"\n" +
+		"~ /VAST/@version: \"\" -> \"4.2\"\n"
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong changes: %s", diff)
+	}
+}
+
+func TestDiff_nodes(t *testing.T) {
+	a := &vast.VAST{Ad: []vast.Ad{{InLine: &vast.InLine{AdDefinitionBase: vast.AdDefinitionBase{Extensions: &vast.Extensions{Extension: []vast.Extension{{
+		Nodes: []vast.Node{{Name: "Foo", Children: []vast.Node{{Text: "a"}}}},
+	}}}}}}}}
+	b := &vast.VAST{Ad: []vast.Ad{{InLine: &vast.InLine{AdDefinitionBase: vast.AdDefinitionBase{Extensions: &vast.Extensions{Extension: []vast.Extension{{
+		Nodes:      []vast.Node{{Name: "Bar", Namespace: "urn:bar", Children: []vast.Node{{Text: "a", CData: true}}}},
+		Attributes: vast.Attributes{{Name: xml.Name{Local: "foo"}, Value: "1"}},
+	}}}}}}}}
+
+	got := vast.FormatChanges(vast.Diff(a, b))
+	want := "~ /VAST/Ad[1]/InLine/Extensions/Extension[1]/*[1]/Name: \"Foo\" -> \"Bar\"\n" +
+		"~ /VAST/Ad[1]/InLine/Extensions/Extension[1]/*[1]/Namespace: \"\" -> \"urn:bar\"\n" +
+		"~ /VAST/Ad[1]/InLine/Extensions/Extension[1]/*[1]/*[1]/CData: false -> true\n" +
+		"+ /VAST/Ad[1]/InLine/Extensions/Extension[1]/@*[.=\"1\"]\n"
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong changes: %s", diff)
+	}
+}
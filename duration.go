@@ -0,0 +1,47 @@
+package vast
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDuration = errors.New("invalid duration")
+
+var durationValuePattern = regexp.MustCompile(`^(\d+):([0-5]\d):([0-5]\d)(?:\.(\d{1,3}))?$`)
+
+// NewDuration formats a duration as `hh:mm:ss.mmm`, or `hh:mm:ss` if it has no milliseconds.
+// Negative durations are formatted as zero.
+func NewDuration(duration time.Duration) Duration {
+	duration = max(duration, 0).Round(time.Millisecond)
+
+	hours := duration / time.Hour
+	minutes := duration % time.Hour / time.Minute
+	seconds := duration % time.Minute / time.Second
+
+	formatted := fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+	if milliseconds := duration % time.Second / time.Millisecond; milliseconds > 0 {
+		formatted += fmt.Sprintf(".%03d", milliseconds)
+	}
+
+	return Duration(formatted)
+}
+
+// Parse returns the value of a duration in the format `hh:mm:ss` or `hh:mm:ss.mmm`.
+func (d Duration) Parse() (time.Duration, error) {
+	match := durationValuePattern.FindStringSubmatch(strings.TrimSpace(string(d)))
+	if match == nil {
+		return 0, fmt.Errorf("%w %q", ErrInvalidDuration, string(d))
+	}
+
+	hours, _ := strconv.Atoi(match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	seconds, _ := strconv.Atoi(match[3])
+	milliseconds, _ := strconv.Atoi((match[4] + "000")[:3])
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second + time.Duration(milliseconds)*time.Millisecond, nil
+}
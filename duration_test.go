@@ -0,0 +1,42 @@
+package vast_test
+
+import (
+	"errors"
+	"go.eigsys.de/go-vast"
+	"testing"
+	"time"
+)
+
+func TestNewDuration(t *testing.T) {
+	tests := map[time.Duration]vast.Duration{
+		16 * time.Second: "00:00:16",
+		time.Hour + 2*time.Minute + 1500*time.Millisecond: "01:02:01.500",
+		-time.Second: "00:00:00",
+	}
+
+	for duration, expected := range tests {
+		if got := vast.NewDuration(duration); got != expected {
+			t.Errorf("wrong duration for %s: %s", duration, got)
+		}
+	}
+}
+
+func TestDuration_Parse(t *testing.T) {
+	tests := map[vast.Duration]time.Duration{
+		"00:00:16":     16 * time.Second,
+		" 01:02:01.5 ": time.Hour + 2*time.Minute + 1500*time.Millisecond,
+		"00:00:15.200": 15200 * time.Millisecond,
+	}
+
+	for duration, expected := range tests {
+		if got, err := duration.Parse(); err != nil || got != expected {
+			t.Errorf("wrong duration for %s: %s", duration, got)
+		}
+	}
+
+	for _, duration := range []vast.Duration{"", "16", "00:60:00", "00:00:01.1234"} {
+		if _, err := duration.Parse(); !errors.Is(err, vast.ErrInvalidDuration) {
+			t.Errorf("expected error for %q", duration)
+		}
+	}
+}
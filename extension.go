@@ -0,0 +1,380 @@
+package vast
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrExtensionNotFound = errors.New("extension not found")
+	ErrUnknownExtension  = errors.New("unknown extension type")
+	ErrDecodeExtension   = errors.New("cannot decode extension")
+	ErrEncodeExtension   = errors.New("cannot encode extension")
+)
+
+// Attributes are XML attributes which are not part of the specification. Their JSON representation is an object
+// mapping the names, prefixed with the namespace if there is one, to the values.
+type Attributes []xml.Attr
+
+// MarshalJSON encodes the attributes as JSON object.
+func (a Attributes) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteByte('{')
+
+	for i, attribute := range a {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		name, _ := json.Marshal(attributeName(attribute.Name))
+		value, _ := json.Marshal(attribute.Value)
+
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the attributes from a JSON object, keeping their order.
+func (a *Attributes) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return errors.New("attributes must be a JSON object")
+	}
+
+	*a = nil
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		var value string
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+
+		name := xml.Name{Local: token.(string)}
+		if index := strings.LastIndex(name.Local, ":"); index >= 0 {
+			name = xml.Name{Space: name.Local[:index], Local: name.Local[index+1:]}
+		}
+
+		*a = append(*a, xml.Attr{Name: name, Value: value})
+	}
+
+	return nil
+}
+
+func attributeName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
+
+var extensionRegistry = struct {
+	sync.RWMutex
+	types map[string]reflect.Type
+}{types: map[string]reflect.Type{}}
+
+// RegisterExtension registers the Go type of the value as type of extensions with the type attribute, which is
+// case-insensitive. The value is a struct or a pointer to a struct, which is decoded from the Extension or
+// CreativeExtension element with encoding/xml, i.e. its fields describe the children and attributes of the element.
+//
+// WaterfallExtension, GeoExtension and AdVerificationsExtension are registered by default.
+func RegisterExtension(extensionType string, value any) {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	extensionRegistry.Lock()
+	defer extensionRegistry.Unlock()
+
+	extensionRegistry.types[strings.ToLower(extensionType)] = t
+}
+
+func registeredExtension(extensionType string) (reflect.Type, bool) {
+	extensionRegistry.RLock()
+	defer extensionRegistry.RUnlock()
+
+	t, ok := extensionRegistry.types[strings.ToLower(strings.TrimSpace(extensionType))]
+
+	return t, ok
+}
+
+// WaterfallExtension is the `waterfall` extension of Google Ad Manager, i.e. the position of the ad in a waterfall of
+// fallback ads.
+type WaterfallExtension struct {
+	FallbackIndex int `xml:"fallback_index,attr"`
+}
+
+// GeoExtension is the `geo` extension of Google Ad Manager, i.e. the country and bandwidth of the viewer.
+type GeoExtension struct {
+	Country       string `xml:"Country,omitempty"`
+	Bandwidth     int    `xml:"Bandwidth,omitempty"`
+	BandwidthKbps int    `xml:"BandwidthKbps,omitempty"`
+}
+
+// AdVerificationsExtension is the `AdVerifications` extension, which carries the AdVerifications element of VAST 4.1
+// in VAST 3.0 and earlier.
+type AdVerificationsExtension struct {
+	AdVerifications AdVerifications `xml:"AdVerifications"`
+}
+
+func init() {
+	RegisterExtension("waterfall", WaterfallExtension{})
+	RegisterExtension("geo", GeoExtension{})
+	RegisterExtension("AdVerifications", AdVerificationsExtension{})
+}
+
+// readExtension reads the content and attributes of an extension element, the type attribute is returned separately.
+func readExtension(decoder *xml.Decoder, start xml.StartElement) ([]Node, string, Attributes, error) {
+	nodes, err := readNodes(decoder, start.Name.Space)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var (
+		extensionType string
+		attributes    Attributes
+	)
+
+	for _, attribute := range start.Attr {
+		if attribute.Name == (xml.Name{Local: "type"}) {
+			extensionType = attribute.Value
+			continue
+		}
+
+		attributes = append(attributes, attribute)
+	}
+
+	return nodes, extensionType, attributes, nil
+}
+
+// writeExtension encodes an extension element with the type attribute first.
+func writeExtension(encoder *xml.Encoder, start xml.StartElement, nodes []Node, extensionType string, attributes Attributes) error {
+	if extensionType != "" {
+		attributes = append(Attributes{{Name: xml.Name{Local: "type"}, Value: extensionType}}, attributes...)
+	}
+
+	return writeElement(encoder, start.Name.Local, attributes, nodes)
+}
+
+// UnmarshalXML decodes the extension with its arbitrary content.
+func (e *Extension) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	nodes, extensionType, attributes, err := readExtension(decoder, start)
+	if err != nil {
+		return err
+	}
+
+	*e = Extension{Nodes: nodes, Type: extensionType, Attributes: attributes}
+
+	return nil
+}
+
+// MarshalXML encodes the extension with its arbitrary content.
+func (e Extension) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	return writeExtension(encoder, start, e.Nodes, e.Type, e.Attributes)
+}
+
+// Find returns the elements of the extension matching the path, see Node.Find.
+func (e *Extension) Find(path string) []*Node {
+	return findNodes(e.Nodes, path)
+}
+
+// Content returns the character data of the extension, see Node.Content.
+func (e *Extension) Content() string {
+	return nodesContent(e.Nodes)
+}
+
+// UnmarshalXML decodes the creative extension with its arbitrary content.
+func (e *CreativeExtension) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	nodes, extensionType, attributes, err := readExtension(decoder, start)
+	if err != nil {
+		return err
+	}
+
+	*e = CreativeExtension{Nodes: nodes, Type: extensionType, Attributes: attributes}
+
+	return nil
+}
+
+// MarshalXML encodes the creative extension with its arbitrary content.
+func (e CreativeExtension) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	return writeExtension(encoder, start, e.Nodes, e.Type, e.Attributes)
+}
+
+// Find returns the elements of the creative extension matching the path, see Node.Find.
+func (e *CreativeExtension) Find(path string) []*Node {
+	return findNodes(e.Nodes, path)
+}
+
+// Content returns the character data of the creative extension, see Node.Content.
+func (e *CreativeExtension) Content() string {
+	return nodesContent(e.Nodes)
+}
+
+// decodeExtension decodes an extension into v. If v is a pointer to an interface, it is set to a pointer to a new
+// value of the registered type.
+func decodeExtension(extension any, extensionType string, v any) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("%w: %T is not a pointer", ErrDecodeExtension, v)
+	}
+
+	var value reflect.Value
+	if target.Elem().Kind() == reflect.Interface {
+		t, ok := registeredExtension(extensionType)
+		if !ok {
+			return fmt.Errorf("%w %q", ErrUnknownExtension, extensionType)
+		}
+
+		value = reflect.New(t)
+		v = value.Interface()
+	}
+
+	data, err := xml.Marshal(extension)
+	if err != nil {
+		return errors.Join(ErrDecodeExtension, err)
+	}
+
+	if err := decodeXML(data, v); err != nil {
+		return errors.Join(ErrDecodeExtension, err)
+	}
+
+	if value.IsValid() {
+		target.Elem().Set(value)
+	}
+
+	return nil
+}
+
+// encodeExtension encodes v as element with the name and decodes it into the extension.
+func encodeExtension(name string, v, extension any) error {
+	var buffer bytes.Buffer
+
+	if err := xml.NewEncoder(&buffer).EncodeElement(v, xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+		return errors.Join(ErrEncodeExtension, err)
+	}
+
+	if err := decodeXML(buffer.Bytes(), extension); err != nil {
+		return errors.Join(ErrEncodeExtension, err)
+	}
+
+	return nil
+}
+
+// Decode decodes the extension, see Extensions.Decode.
+func (e Extension) Decode(v any) error {
+	return decodeExtension(e, e.Type, v)
+}
+
+// NewExtension encodes v as extension with the type.
+func NewExtension(extensionType string, v any) (Extension, error) {
+	var extension Extension
+	if err := encodeExtension("Extension", v, &extension); err != nil {
+		return Extension{}, err
+	}
+
+	extension.Type = extensionType
+
+	return extension, nil
+}
+
+// Decode decodes the first extension with the type, which is case-insensitive, into v. If v is a pointer to an
+// interface, e.g. *any, it is set to a pointer to a new value of the type registered with RegisterExtension.
+// It returns ErrExtensionNotFound if there is no extension with the type.
+func (e *Extensions) Decode(extensionType string, v any) error {
+	if e != nil {
+		for _, extension := range e.Extension {
+			if strings.EqualFold(strings.TrimSpace(extension.Type), extensionType) {
+				return extension.Decode(v)
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: %q", ErrExtensionNotFound, extensionType)
+}
+
+// Encode encodes v as extension with the type. It replaces the first extension with the type, or adds a new one.
+func (e *Extensions) Encode(extensionType string, v any) error {
+	extension, err := NewExtension(extensionType, v)
+	if err != nil {
+		return err
+	}
+
+	for i := range e.Extension {
+		if strings.EqualFold(strings.TrimSpace(e.Extension[i].Type), extensionType) {
+			e.Extension[i] = extension
+			return nil
+		}
+	}
+
+	e.Extension = append(e.Extension, extension)
+
+	return nil
+}
+
+// Decode decodes the creative extension, see Extensions.Decode.
+func (e CreativeExtension) Decode(v any) error {
+	return decodeExtension(e, e.Type, v)
+}
+
+// NewCreativeExtension encodes v as creative extension with the type.
+func NewCreativeExtension(extensionType string, v any) (CreativeExtension, error) {
+	var extension CreativeExtension
+	if err := encodeExtension("CreativeExtension", v, &extension); err != nil {
+		return CreativeExtension{}, err
+	}
+
+	extension.Type = extensionType
+
+	return extension, nil
+}
+
+// Decode decodes the first creative extension with the type, see Extensions.Decode.
+func (e *CreativeExtensions) Decode(extensionType string, v any) error {
+	if e != nil {
+		for _, extension := range e.CreativeExtension {
+			if strings.EqualFold(strings.TrimSpace(extension.Type), extensionType) {
+				return extension.Decode(v)
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: %q", ErrExtensionNotFound, extensionType)
+}
+
+// Encode encodes v as creative extension with the type, see Extensions.Encode.
+func (e *CreativeExtensions) Encode(extensionType string, v any) error {
+	extension, err := NewCreativeExtension(extensionType, v)
+	if err != nil {
+		return err
+	}
+
+	for i := range e.CreativeExtension {
+		if strings.EqualFold(strings.TrimSpace(e.CreativeExtension[i].Type), extensionType) {
+			e.CreativeExtension[i] = extension
+			return nil
+		}
+	}
+
+	e.CreativeExtension = append(e.CreativeExtension, extension)
+
+	return nil
+}
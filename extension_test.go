@@ -0,0 +1,241 @@
+package vast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"io"
+	"strings"
+	"testing"
+)
+
+const extensionVAST = `<VAST version="3.0">
+  <Ad>
+    <InLine>
+      <Extensions>
+        <Extension type="waterfall" fallback_index="2"></Extension>
+        <Extension type="Geo" vendor="example">
+          <Country>DE</Country>
+          <Bandwidth>4</Bandwidth>
+        </Extension>
+        <Extension type="AdVerifications">
+          <AdVerifications>
+            <Verification vendor="example.com-omid">
+              <JavaScriptResource apiFramework="omid" browserOptional="true"><![CDATA[https://example.com/omid.js]]></JavaScriptResource>
+            </Verification>
+          </AdVerifications>
+        </Extension>
+      </Extensions>
+      <Creatives>
+        <Creative>
+          <CreativeExtensions>
+            <CreativeExtension type="example" version="1"><Skin color="red"/></CreativeExtension>
+          </CreativeExtensions>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`
+
+type skinExtension struct {
+	Version string `xml:"version,attr"`
+	Skin    struct {
+		Color string `xml:"color,attr"`
+	} `xml:"Skin"`
+}
+
+func readExtensionVAST(t *testing.T) *vast.VAST {
+	t.Helper()
+
+	testVAST, err := vast.Read(io.NopCloser(strings.NewReader(extensionVAST)))
+	if err != nil {
+		t.Fatal("unexpected error")
+	}
+
+	return testVAST
+}
+
+func TestExtensions_Decode(t *testing.T) {
+	extensions := readExtensionVAST(t).Ad[0].InLine.Extensions
+
+	var waterfall vast.WaterfallExtension
+	if err := extensions.Decode("waterfall", &waterfall); err != nil || waterfall.FallbackIndex != 2 {
+		t.Errorf("wrong waterfall: %v", waterfall)
+	}
+
+	var geo vast.GeoExtension
+	if err := extensions.Decode("geo", &geo); err != nil {
+		t.Error("unexpected error")
+	}
+
+	if diff := cmp.Diff(vast.GeoExtension{Country: "DE", Bandwidth: 4}, geo); diff != "" {
+		t.Errorf("wrong geo: %s", diff)
+	}
+
+	var verifications any
+	if err := extensions.Decode("adverifications", &verifications); err != nil {
+		t.Error("unexpected error")
+	}
+
+	extension, ok := verifications.(*vast.AdVerificationsExtension)
+	if !ok || len(extension.AdVerifications.Verification) != 1 || extension.AdVerifications.Verification[0].Vendor != "example.com-omid" {
+		t.Errorf("wrong verifications: %#v", verifications)
+	}
+
+	if err := extensions.Decode("unknown", &geo); !errors.Is(err, vast.ErrExtensionNotFound) {
+		t.Error("expected error")
+	}
+
+	if err := extensions.Decode("geo", geo); !errors.Is(err, vast.ErrDecodeExtension) {
+		t.Error("expected error")
+	}
+
+	var nilExtensions *vast.Extensions
+	if err := nilExtensions.Decode("geo", &geo); !errors.Is(err, vast.ErrExtensionNotFound) {
+		t.Error("expected error")
+	}
+}
+
+func TestCreativeExtensions_Decode(t *testing.T) {
+	extensions := readExtensionVAST(t).Ad[0].InLine.Creatives.Creative[0].CreativeExtensions
+
+	var value any
+	if err := extensions.Decode("example", &value); !errors.Is(err, vast.ErrUnknownExtension) {
+		t.Error("expected error")
+	}
+
+	vast.RegisterExtension("example", &skinExtension{})
+
+	if err := extensions.Decode("example", &value); err != nil {
+		t.Error("unexpected error")
+	}
+
+	skin, ok := value.(*skinExtension)
+	if !ok || skin.Version != "1" || skin.Skin.Color != "red" {
+		t.Errorf("wrong extension: %#v", value)
+	}
+
+	if err := extensions.Decode("missing", &value); !errors.Is(err, vast.ErrExtensionNotFound) {
+		t.Error("expected error")
+	}
+
+	var invalid struct {
+		Skin struct {
+			Color int `xml:"color,attr"`
+		} `xml:"Skin"`
+	}
+
+	if err := extensions.Decode("example", &invalid); !errors.Is(err, vast.ErrDecodeExtension) {
+		t.Error("expected error")
+	}
+}
+
+func TestExtensions_Encode(t *testing.T) {
+	extensions := &vast.Extensions{Extension: []vast.Extension{{Type: "waterfall", Nodes: []vast.Node{{Text: "old"}}}}}
+
+	if err := extensions.Encode("waterfall", vast.WaterfallExtension{FallbackIndex: 3}); err != nil {
+		t.Error("unexpected error")
+	}
+
+	if err := extensions.Encode("geo", &vast.GeoExtension{Country: "US"}); err != nil {
+		t.Error("unexpected error")
+	}
+
+	expected := []vast.Extension{
+		{Type: "waterfall", Attributes: vast.Attributes{{Name: xml.Name{Local: "fallback_index"}, Value: "3"}}},
+		{Type: "geo", Nodes: []vast.Node{{Name: "Country", Children: []vast.Node{{Text: "US"}}}}},
+	}
+
+	if diff := cmp.Diff(expected, extensions.Extension); diff != "" {
+		t.Errorf("wrong extensions: %s", diff)
+	}
+
+	var geo vast.GeoExtension
+	if err := extensions.Decode("geo", &geo); err != nil || geo.Country != "US" {
+		t.Errorf("wrong geo: %v", geo)
+	}
+
+	if err := extensions.Encode("invalid", make(chan int)); !errors.Is(err, vast.ErrEncodeExtension) {
+		t.Error("expected error")
+	}
+}
+
+func TestCreativeExtensions_Encode(t *testing.T) {
+	extensions := &vast.CreativeExtensions{}
+
+	value := skinExtension{Version: "2"}
+	value.Skin.Color = "blue"
+
+	if err := extensions.Encode("example", value); err != nil {
+		t.Error("unexpected error")
+	}
+
+	value.Skin.Color = "green"
+
+	if err := extensions.Encode("example", value); err != nil {
+		t.Error("unexpected error")
+	}
+
+	expected := []vast.CreativeExtension{{
+		Type:       "example",
+		Nodes:      []vast.Node{{Name: "Skin", Attributes: vast.Attributes{{Name: xml.Name{Local: "color"}, Value: "green"}}}},
+		Attributes: vast.Attributes{{Name: xml.Name{Local: "version"}, Value: "2"}},
+	}}
+
+	if diff := cmp.Diff(expected, extensions.CreativeExtension); diff != "" {
+		t.Errorf("wrong extensions: %s", diff)
+	}
+
+	if err := extensions.Encode("invalid", func() {}); !errors.Is(err, vast.ErrEncodeExtension) {
+		t.Error("expected error")
+	}
+}
+
+func TestExtension_xml(t *testing.T) {
+	testVAST := readExtensionVAST(t)
+
+	data, err := xml.Marshal(testVAST)
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	for _, attribute := range []string{`vendor="example"`, `fallback_index="2"`, `version="1"`} {
+		if !bytes.Contains(data, []byte(attribute)) {
+			t.Errorf("missing attribute %s", attribute)
+		}
+	}
+}
+
+func TestAttributes_json(t *testing.T) {
+	attributes := vast.Attributes{
+		{Name: xml.Name{Local: "vendor"}, Value: "example"},
+		{Name: xml.Name{Space: "xsi", Local: "type"}, Value: "Geo"},
+	}
+
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if want := `{"vendor":"example","xsi:type":"Geo"}`; string(data) != want {
+		t.Errorf("wrong JSON: %s", data)
+	}
+
+	var got vast.Attributes
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Error("unexpected error")
+	}
+
+	if diff := cmp.Diff(attributes, got); diff != "" {
+		t.Errorf("wrong attributes: %s", diff)
+	}
+
+	for _, invalid := range []string{`[]`, `{"a":1}`, `{"a":"b",}`} {
+		if err := json.Unmarshal([]byte(invalid), &got); err == nil {
+			t.Errorf("expected error for %s", invalid)
+		}
+	}
+}
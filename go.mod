@@ -5,9 +5,6 @@ go 1.23.0
 require (
 	aqwari.net/xml v0.0.0-20210331023308-d9421b293817
 	github.com/google/go-cmp v0.7.0
-)
-
-require (
-	golang.org/x/net v0.38.0 // indirect
-	golang.org/x/text v0.23.0 // indirect
+	golang.org/x/net v0.38.0
+	golang.org/x/text v0.23.0
 )
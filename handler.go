@@ -0,0 +1,166 @@
+package vast
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Handler serves the VAST documents returned by Func.
+//
+// The response is XML, or JSON if the request prefers `application/json` over XML in its Accept header.
+// It is compressed if the client accepts gzip. Requests with an Origin header get the CORS headers required by HTML5
+// video players, which request ads with credentials: the origin is echoed and credentials are allowed.
+//
+// If Func fails or returns no VAST, or if the VAST cannot be marshalled, an empty VAST without ads is served, which
+// contains ErrorURIs as Error elements. OnError is called with the error of Func or of the marshalling, if set.
+type Handler struct {
+	Func      func(r *http.Request) (*VAST, error)
+	ErrorURIs []string
+	OnError   func(r *http.Request, err error)
+}
+
+// NewHandler creates a new instance of Handler which serves the VAST documents returned by f.
+func NewHandler(f func(r *http.Request) (*VAST, error)) *Handler {
+	return &Handler{Func: f}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	header := w.Header()
+
+	if origin := r.Header.Get("Origin"); origin != "" {
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Set("Access-Control-Allow-Credentials", "true")
+		header.Add("Vary", "Origin")
+	}
+
+	if r.Method == http.MethodOptions {
+		header.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+
+		if requestHeaders := r.Header.Get("Access-Control-Request-Headers"); requestHeaders != "" {
+			header.Set("Access-Control-Allow-Headers", requestHeaders)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	header.Add("Vary", "Accept")
+
+	json := prefersJSON(r.Header.Get("Accept"))
+
+	vast, err := h.Func(r)
+	if err != nil {
+		h.handleError(r, err)
+	}
+
+	if err != nil || vast == nil {
+		vast = h.noAd()
+	}
+
+	body, contentType, err := encodeResponse(vast, json)
+	if err != nil {
+		h.handleError(r, err)
+
+		if body, contentType, err = encodeResponse(h.noAd(), json); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	header.Set("Content-Type", contentType)
+	header.Add("Vary", "Accept-Encoding")
+
+	if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write(body)
+
+		return
+	}
+
+	header.Set("Content-Encoding", "gzip")
+
+	gzipWriter := gzip.NewWriter(w)
+	_, _ = gzipWriter.Write(body)
+	_ = gzipWriter.Close()
+}
+
+func (h *Handler) handleError(r *http.Request, err error) {
+	if h.OnError != nil {
+		h.OnError(r, err)
+	}
+}
+
+// encodeResponse marshals the VAST to JSON or XML and returns the body and its content type.
+func encodeResponse(vast *VAST, json bool) ([]byte, string, error) {
+	if json {
+		body, err := vast.JSON()
+		return body, "application/json", err
+	}
+
+	body, err := vast.Bytes()
+
+	return body, "application/xml", err
+}
+
+func (h *Handler) noAd() *VAST {
+	vast := New()
+
+	for _, uri := range h.ErrorURIs {
+		vast.Error = append(vast.Error, CData{Value: uri})
+	}
+
+	return vast
+}
+
+// mediaRanges parses an Accept or Accept-Encoding header and returns the quality of each value.
+func mediaRanges(header string) map[string]float64 {
+	ranges := map[string]float64{}
+
+	for _, part := range strings.Split(header, ",") {
+		value, parameters, _ := strings.Cut(part, ";")
+		quality := 1.0
+
+		for _, parameter := range strings.Split(parameters, ";") {
+			if name, q, ok := strings.Cut(strings.TrimSpace(parameter), "="); ok && name == "q" {
+				if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+
+		ranges[strings.ToLower(strings.TrimSpace(value))] = quality
+	}
+
+	return ranges
+}
+
+func prefersJSON(accept string) bool {
+	ranges := mediaRanges(accept)
+
+	json, ok := ranges["application/json"]
+	if !ok || json <= 0 {
+		return false
+	}
+
+	for _, xml := range []string{"application/xml", "text/xml"} {
+		if ranges[xml] >= json {
+			return false
+		}
+	}
+
+	return true
+}
+
+func acceptsGzip(acceptEncoding string) bool {
+	ranges := mediaRanges(acceptEncoding)
+
+	if quality, ok := ranges["gzip"]; ok {
+		return quality > 0
+	}
+
+	return ranges["*"] > 0
+}
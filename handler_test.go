@@ -0,0 +1,174 @@
+package vast_test
+
+import (
+	"compress/gzip"
+	"errors"
+	"go.eigsys.de/go-vast"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serve(handler http.Handler, method string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, "/vast", nil)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	handler := vast.NewHandler(func(*http.Request) (*vast.VAST, error) {
+		return vast.New(), nil
+	})
+
+	response := serve(handler, http.MethodGet, nil)
+
+	if response.Code != http.StatusOK || response.Header().Get("Content-Type") != "application/xml" {
+		t.Errorf("unexpected response: %d %v", response.Code, response.Header())
+	}
+
+	if want := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<VAST version=\"4.2\" xmlns=\"http://www.iab.com/VAST\"></VAST>"; response.Body.String() != want {
+		t.Errorf("unexpected body: %s", response.Body.String())
+	}
+
+	if response.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Error("unexpected CORS header")
+	}
+}
+
+func TestHandler_ServeHTTP_cors(t *testing.T) {
+	handler := vast.NewHandler(func(*http.Request) (*vast.VAST, error) {
+		return vast.New(), nil
+	})
+
+	response := serve(handler, http.MethodGet, map[string]string{"Origin": "https://publisher.example"})
+
+	if response.Header().Get("Access-Control-Allow-Origin") != "https://publisher.example" || response.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Errorf("unexpected headers: %v", response.Header())
+	}
+
+	preflight := serve(handler, http.MethodOptions, map[string]string{
+		"Origin":                         "https://publisher.example",
+		"Access-Control-Request-Headers": "X-Example",
+	})
+
+	if preflight.Code != http.StatusNoContent || preflight.Header().Get("Access-Control-Allow-Headers") != "X-Example" || preflight.Body.Len() != 0 {
+		t.Errorf("unexpected preflight response: %d %v", preflight.Code, preflight.Header())
+	}
+}
+
+func TestHandler_ServeHTTP_gzip(t *testing.T) {
+	handler := vast.NewHandler(func(*http.Request) (*vast.VAST, error) {
+		return vast.New(), nil
+	})
+
+	for _, acceptEncoding := range []string{"gzip, deflate", "br;q=1.0, *;q=0.5"} {
+		response := serve(handler, http.MethodGet, map[string]string{"Accept-Encoding": acceptEncoding})
+		if response.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("unexpected headers: %v", response.Header())
+		}
+
+		reader, err := gzip.NewReader(response.Body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		body, _ := io.ReadAll(reader)
+		if !strings.HasSuffix(string(body), "<VAST version=\"4.2\" xmlns=\"http://www.iab.com/VAST\"></VAST>") {
+			t.Errorf("unexpected body: %s", body)
+		}
+	}
+
+	if response := serve(handler, http.MethodGet, map[string]string{"Accept-Encoding": "gzip;q=0, deflate"}); response.Header().Get("Content-Encoding") != "" {
+		t.Errorf("unexpected headers: %v", response.Header())
+	}
+}
+
+func TestHandler_ServeHTTP_json(t *testing.T) {
+	handler := vast.NewHandler(func(*http.Request) (*vast.VAST, error) {
+		return vast.New(), nil
+	})
+
+	testCases := map[string]string{
+		"application/json":                           "application/json",
+		"application/xml;q=0.5, application/json":    "application/json",
+		"application/json, application/xml":          "application/xml",
+		"application/json;q=0.5, text/xml;q=invalid": "application/xml",
+		"application/json;q=0":                       "application/xml",
+		"*/*":                                        "application/xml",
+	}
+
+	for accept, want := range testCases {
+		if response := serve(handler, http.MethodGet, map[string]string{"Accept": accept}); response.Header().Get("Content-Type") != want {
+			t.Errorf("unexpected content type for %q: %s", accept, response.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestHandler_ServeHTTP_noAd(t *testing.T) {
+	var handlerErr error
+
+	handler := vast.NewHandler(func(*http.Request) (*vast.VAST, error) {
+		return nil, errors.New("no fill")
+	})
+	handler.ErrorURIs = []string{"https://example.com/error?code=[ERRORCODE]"}
+	handler.OnError = func(_ *http.Request, err error) {
+		handlerErr = err
+	}
+
+	response := serve(handler, http.MethodGet, nil)
+
+	if want := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<VAST version=\"4.2\" xmlns=\"http://www.iab.com/VAST\">\n  <Error><![CDATA[https://example.com/error?code=[ERRORCODE]]]></Error>\n</VAST>"; response.Body.String() != want {
+		t.Errorf("unexpected body: %s", response.Body.String())
+	}
+
+	if handlerErr == nil || handlerErr.Error() != "no fill" {
+		t.Errorf("unexpected error: %v", handlerErr)
+	}
+}
+
+func TestHandler_ServeHTTP_nil(t *testing.T) {
+	handler := vast.NewHandler(func(*http.Request) (*vast.VAST, error) {
+		return nil, nil
+	})
+	handler.ErrorURIs = []string{"https://example.com/error"}
+	handler.OnError = func(_ *http.Request, err error) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	response := serve(handler, http.MethodGet, nil)
+
+	if want := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<VAST version=\"4.2\" xmlns=\"http://www.iab.com/VAST\">\n  <Error><![CDATA[https://example.com/error]]></Error>\n</VAST>"; response.Code != http.StatusOK || response.Body.String() != want {
+		t.Errorf("unexpected response: %d %s", response.Code, response.Body.String())
+	}
+}
+
+func TestHandler_ServeHTTP_marshalError(t *testing.T) {
+	var handlerErr error
+
+	handler := vast.NewHandler(func(*http.Request) (*vast.VAST, error) {
+		return &vast.VAST{Ad: []vast.Ad{{InLine: &vast.InLine{AdDefinitionBase: vast.AdDefinitionBase{Pricing: &vast.Pricing{Value: math.Inf(1)}}}}}}, nil
+	})
+	handler.ErrorURIs = []string{"https://example.com/error"}
+	handler.OnError = func(_ *http.Request, err error) {
+		handlerErr = err
+	}
+
+	response := serve(handler, http.MethodGet, map[string]string{"Accept": "application/json"})
+
+	if want := "{\n  \"error\": [\n    \"https://example.com/error\"\n  ],\n  \"version\": \"4.2\",\n  \"xmlns\": \"http://www.iab.com/VAST\"\n}"; response.Code != http.StatusOK || response.Body.String() != want {
+		t.Errorf("unexpected response: %d %s", response.Code, response.Body.String())
+	}
+
+	if !errors.Is(handlerErr, vast.ErrMarshalVAST) {
+		t.Errorf("unexpected error: %v", handlerErr)
+	}
+}
@@ -0,0 +1,107 @@
+package vast
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInsecureURL        = errors.New("insecure URL")
+	ErrUnknownHTTPSAction = errors.New("unknown HTTPS action")
+)
+
+// HTTPSRule is reported by SanitizeHTTPS.
+var HTTPSRule = Rule{"https", "URLs should use HTTPS, since many environments block insecure assets."}
+
+// HTTPSAction is applied to insecure URLs by SanitizeHTTPS.
+type HTTPSAction string
+
+const (
+	// UpgradeHTTPS replaces the http scheme by https. URLs which cannot be upgraded are dropped.
+	UpgradeHTTPS HTTPSAction = "upgrade"
+	// DropInsecure drops insecure URLs.
+	DropInsecure HTTPSAction = "drop"
+	// FailInsecure leaves the VAST unchanged and fails if it contains insecure URLs.
+	FailInsecure HTTPSAction = "fail"
+)
+
+// HTTPSPolicy configures SanitizeHTTPS. The zero value of Action is UpgradeHTTPS, other actions than the declared ones
+// are rejected. Upgradable reports whether an insecure URL is also served via HTTPS. All URLs are considered upgradable
+// if Upgradable is nil.
+type HTTPSPolicy struct {
+	Action     HTTPSAction
+	Upgradable func(URL) bool
+}
+
+// SanitizeHTTPS applies the policy to all URLs using the http scheme, see URLs, and returns a violation of HTTPSRule
+// for each of them. Dropping a URL removes repeated elements like MediaFile or Tracking, clears other elements like
+// ClickThrough and removes wrapper ads if their VASTAdTagURI is dropped.
+// With FailInsecure, ErrInsecureURL is returned if there are violations. The VAST is left unchanged and
+// ErrUnknownHTTPSAction is returned if the action is unknown.
+func (m *VAST) SanitizeHTTPS(policy HTTPSPolicy) ([]Violation, error) {
+	switch policy.Action {
+	case "":
+		policy.Action = UpgradeHTTPS
+	case UpgradeHTTPS, DropInsecure, FailInsecure:
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownHTTPSAction, policy.Action)
+	}
+
+	var (
+		violations      []Violation
+		droppedWrappers = map[*string]bool{}
+	)
+
+	visitURLs(m, func(u URL, _ Path, value *string) bool {
+		if !strings.HasPrefix(strings.ToLower(u.Value), "http://") {
+			return true
+		}
+
+		report := func(severity Severity, format string) {
+			violations = append(violations, Violation{
+				Rule:     HTTPSRule.ID,
+				Severity: severity,
+				Path:     u.Path,
+				Message:  fmt.Sprintf(format, u.Value),
+			})
+		}
+
+		switch {
+		case policy.Action == FailInsecure:
+			report(ErrorSeverity, "insecure URL %q")
+			return true
+		case policy.Action == UpgradeHTTPS && (policy.Upgradable == nil || policy.Upgradable(u)):
+			*value = "https://" + u.Value[len("http://"):]
+			report(WarningSeverity, "insecure URL %q upgraded to HTTPS")
+
+			return true
+		}
+
+		report(WarningSeverity, "insecure URL %q dropped")
+
+		if u.Category == WrapperURL {
+			droppedWrappers[value] = true
+		}
+
+		return false
+	})
+
+	if len(droppedWrappers) > 0 {
+		ads := make([]Ad, 0, len(m.Ad))
+
+		for _, ad := range m.Ad {
+			if ad.Wrapper == nil || !droppedWrappers[&ad.Wrapper.VASTAdTagURI.Value] {
+				ads = append(ads, ad)
+			}
+		}
+
+		m.Ad = ads
+	}
+
+	if policy.Action == FailInsecure && len(violations) > 0 {
+		return violations, ErrInsecureURL
+	}
+
+	return violations, nil
+}
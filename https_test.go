@@ -0,0 +1,135 @@
+package vast_test
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"strings"
+	"testing"
+)
+
+func insecureVAST() *vast.VAST {
+	testVAST := trackerVAST()
+
+	inLine := testVAST.Ad[0].InLine
+	inLine.Impression = append(inLine.Impression, vast.Impression{Value: "HTTP://example.com/impression"})
+	inLine.Creatives.Creative[0].Linear.MediaFiles.MediaFile = []vast.MediaFile{
+		{Value: "https://example.com/video.mp4"},
+		{Value: " http://example.com/video.webm "},
+	}
+	inLine.Creatives.Creative[2].CompanionAds.Companion[0].CompanionClickThrough = &vast.CData{Value: "http://example.com/"}
+
+	testVAST.Ad = append(testVAST.Ad, vast.Ad{Wrapper: &vast.Wrapper{VASTAdTagURI: vast.CData{Value: "http://example.com/vast.xml"}}})
+
+	return testVAST
+}
+
+func TestVAST_SanitizeHTTPS_upgrade(t *testing.T) {
+	testVAST := insecureVAST()
+
+	violations, err := testVAST.SanitizeHTTPS(vast.HTTPSPolicy{
+		Action: vast.UpgradeHTTPS,
+		Upgradable: func(u vast.URL) bool {
+			return u.Category != vast.MediaURL
+		},
+	})
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	expected := []vast.Violation{
+		{Rule: "https", Severity: vast.WarningSeverity, Path: "/VAST/Ad[1]/InLine/Impression[2]", Message: `insecure URL "HTTP://example.com/impression" upgraded to HTTPS`},
+		{Rule: "https", Severity: vast.WarningSeverity, Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/MediaFiles/MediaFile[2]", Message: `insecure URL "http://example.com/video.webm" dropped`},
+		{Rule: "https", Severity: vast.WarningSeverity, Path: "/VAST/Ad[1]/InLine/Creatives/Creative[3]/CompanionAds/Companion[1]/CompanionClickThrough", Message: `insecure URL "http://example.com/" upgraded to HTTPS`},
+		{Rule: "https", Severity: vast.WarningSeverity, Path: "/VAST/Ad[3]/Wrapper/VASTAdTagURI", Message: `insecure URL "http://example.com/vast.xml" upgraded to HTTPS`},
+	}
+
+	if diff := cmp.Diff(expected, violations); diff != "" {
+		t.Errorf("wrong violations: %s", diff)
+	}
+
+	inLine := testVAST.Ad[0].InLine
+	if inLine.Impression[1].Value != "https://example.com/impression" ||
+		len(inLine.Creatives.Creative[0].Linear.MediaFiles.MediaFile) != 1 ||
+		inLine.Creatives.Creative[2].CompanionAds.Companion[0].CompanionClickThrough.Value != "https://example.com/" ||
+		testVAST.Ad[2].Wrapper.VASTAdTagURI.Value != "https://example.com/vast.xml" {
+		t.Error("insecure URLs not sanitized")
+	}
+
+	if violations, _ := testVAST.SanitizeHTTPS(vast.HTTPSPolicy{Action: vast.FailInsecure}); len(violations) != 0 {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+}
+
+func TestVAST_SanitizeHTTPS_drop(t *testing.T) {
+	testVAST := insecureVAST()
+
+	violations, err := testVAST.SanitizeHTTPS(vast.HTTPSPolicy{Action: vast.DropInsecure})
+	if err != nil || len(violations) != 4 {
+		t.Errorf("unexpected result: %v %v", violations, err)
+	}
+
+	inLine := testVAST.Ad[0].InLine
+	if len(inLine.Impression) != 1 || len(inLine.Creatives.Creative[0].Linear.MediaFiles.MediaFile) != 1 ||
+		inLine.Creatives.Creative[2].CompanionAds.Companion[0].CompanionClickThrough != nil || len(testVAST.Ad) != 2 {
+		t.Error("insecure URLs not dropped")
+	}
+}
+
+func TestVAST_SanitizeHTTPS_fail(t *testing.T) {
+	testVAST := insecureVAST()
+
+	violations, err := testVAST.SanitizeHTTPS(vast.HTTPSPolicy{Action: vast.FailInsecure})
+	if !errors.Is(err, vast.ErrInsecureURL) || len(violations) != 4 || violations[0].Severity != vast.ErrorSeverity {
+		t.Errorf("unexpected result: %v %v", violations, err)
+	}
+
+	if diff := cmp.Diff(insecureVAST(), testVAST); diff != "" {
+		t.Errorf("wrong VAST: %s", diff)
+	}
+}
+
+func TestVAST_SanitizeHTTPS_defaultAction(t *testing.T) {
+	testVAST := insecureVAST()
+
+	violations, err := testVAST.SanitizeHTTPS(vast.HTTPSPolicy{})
+	if err != nil || len(violations) != 4 || len(testVAST.Ad) != 3 {
+		t.Errorf("unexpected result: %v %v", violations, err)
+	}
+
+	for _, violation := range violations {
+		if !strings.HasSuffix(violation.Message, "upgraded to HTTPS") {
+			t.Errorf("unexpected violation: %v", violation)
+		}
+	}
+}
+
+func TestVAST_SanitizeHTTPS_unknownAction(t *testing.T) {
+	testVAST := insecureVAST()
+
+	violations, err := testVAST.SanitizeHTTPS(vast.HTTPSPolicy{Action: "upgrde"})
+	if !errors.Is(err, vast.ErrUnknownHTTPSAction) || violations != nil {
+		t.Errorf("unexpected result: %v %v", violations, err)
+	}
+
+	if diff := cmp.Diff(insecureVAST(), testVAST); diff != "" {
+		t.Errorf("wrong VAST: %s", diff)
+	}
+}
+
+func TestVAST_SanitizeHTTPS_fixtures(t *testing.T) {
+	for _, testCase := range iabFixtures {
+		t.Run(testCase, func(t *testing.T) {
+			testVAST := mustReadFixture(t, testCase)
+			if _, err := testVAST.SanitizeHTTPS(vast.HTTPSPolicy{Action: vast.UpgradeHTTPS}); err != nil {
+				t.Error("unexpected error")
+			}
+
+			for _, u := range testVAST.URLs() {
+				if u.Value[:5] == "http:" {
+					t.Errorf("insecure URL: %s", u.Value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package vast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidPosition = errors.New("invalid position")
+
+// IconWindow is the interval of the linear creative during which an icon is displayed.
+type IconWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Contains reports whether the icon is displayed at the position within the linear creative.
+func (w IconWindow) Contains(position time.Duration) bool {
+	return position >= w.Start && position < w.End
+}
+
+// Window returns the interval during which the icon is displayed, given the duration of the linear creative.
+// The icon is displayed from its offset, or from the start if it has none, for its duration, or until the end of the
+// linear creative if it has none. The window is limited to the linear creative and is empty if the offset is after its
+// end.
+func (i *Icon) Window(linearDuration time.Duration) (IconWindow, error) {
+	var window IconWindow
+
+	if strings.TrimSpace(string(i.Offset)) != "" {
+		offset, err := i.Offset.Parse()
+		if err != nil {
+			return IconWindow{}, err
+		}
+
+		window.Start = min(offset, linearDuration)
+	}
+
+	window.End = linearDuration
+
+	if strings.TrimSpace(string(i.Duration)) != "" {
+		duration, err := i.Duration.Parse()
+		if err != nil {
+			return IconWindow{}, err
+		}
+
+		window.End = min(window.Start+duration, linearDuration)
+	}
+
+	return window, nil
+}
+
+// IconLayout is the area of the player covered by an icon in device pixels, relative to the top left corner.
+type IconLayout struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// Layout returns the area covered by the icon in a player with the size in device pixels. The numeric positions of
+// the icon are in CSS pixels and are scaled by the device pixel ratio, which is 1 if zero. The width and height are
+// the size of the icon creative, which is intended for the pxratio of the icon, 1 if zero, so they are scaled by the
+// device pixel ratio divided by the pxratio. Icons without position are placed in the top left corner, and icons are
+// moved into the player if they would exceed it.
+func (i *Icon) Layout(playerWidth, playerHeight int, pixelRatio float64) (IconLayout, error) {
+	if pixelRatio <= 0 {
+		pixelRatio = 1
+	}
+
+	iconPixelRatio := i.PXRatio
+	if iconPixelRatio <= 0 {
+		iconPixelRatio = 1
+	}
+
+	sizeRatio := pixelRatio / iconPixelRatio
+	layout := IconLayout{Width: scalePixels(i.Width, sizeRatio), Height: scalePixels(i.Height, sizeRatio)}
+
+	x, ok := iconPosition(string(i.XPosition), "left", "right", playerWidth, layout.Width, pixelRatio)
+	if !ok {
+		return IconLayout{}, fmt.Errorf("%w xPosition %q", ErrInvalidPosition, i.XPosition)
+	}
+
+	y, ok := iconPosition(string(i.YPosition), "top", "bottom", playerHeight, layout.Height, pixelRatio)
+	if !ok {
+		return IconLayout{}, fmt.Errorf("%w yPosition %q", ErrInvalidPosition, i.YPosition)
+	}
+
+	layout.X, layout.Y = x, y
+
+	return layout, nil
+}
+
+// iconPosition resolves a position keyword or a number of CSS pixels to the offset of an icon within the player.
+func iconPosition(value, start, end string, playerSize, iconSize int, pixelRatio float64) (int, bool) {
+	var position int
+
+	switch value = strings.TrimSpace(value); value {
+	case "", start:
+	case end:
+		position = playerSize - iconSize
+	default:
+		pixels, err := strconv.Atoi(value)
+		if err != nil || pixels < 0 {
+			return 0, false
+		}
+
+		position = scalePixels(pixels, pixelRatio)
+	}
+
+	return max(min(position, playerSize-iconSize), 0), true
+}
+
+func scalePixels(pixels int, pixelRatio float64) int {
+	return int(math.Round(float64(pixels) * pixelRatio))
+}
+
+// FallbackImage returns the fallback image to display on a screen with the size in pixels when the icon is clicked
+// on a device which cannot open the IconClickThrough, e.g. a connected TV. The largest image fitting the screen is
+// chosen, or the smallest image if none fits. Images without size fit any screen but are preferred least. Images
+// without StaticResource are ignored, nil is returned if there is no image.
+func (c *IconClicks) FallbackImage(screenWidth, screenHeight int) *IconClickFallbackImage {
+	if c == nil || c.IconClickFallbackImages == nil {
+		return nil
+	}
+
+	var best *IconClickFallbackImage
+
+	for i := range c.IconClickFallbackImages.IconClickFallbackImage {
+		image := &c.IconClickFallbackImages.IconClickFallbackImage[i]
+
+		if image.StaticResource == nil || strings.TrimSpace(image.StaticResource.Value) == "" {
+			continue
+		}
+
+		if best == nil || betterFallbackImage(image, best, screenWidth, screenHeight) {
+			best = image
+		}
+	}
+
+	return best
+}
+
+func betterFallbackImage(image, best *IconClickFallbackImage, screenWidth, screenHeight int) bool {
+	fits := image.Width <= screenWidth && image.Height <= screenHeight
+	bestFits := best.Width <= screenWidth && best.Height <= screenHeight
+
+	if fits != bestFits {
+		return fits
+	}
+
+	area, bestArea := image.Width*image.Height, best.Width*best.Height
+	if fits {
+		return area > bestArea
+	}
+
+	return area < bestArea
+}
@@ -0,0 +1,120 @@
+package vast_test
+
+import (
+	"errors"
+	"go.eigsys.de/go-vast"
+	"testing"
+	"time"
+)
+
+func TestIcon_Window(t *testing.T) {
+	tests := []struct {
+		offset   vast.Duration
+		duration vast.Duration
+		expected vast.IconWindow
+	}{
+		{"", "", vast.IconWindow{Start: 0, End: 30 * time.Second}},
+		{"00:00:05", "", vast.IconWindow{Start: 5 * time.Second, End: 30 * time.Second}},
+		{"00:00:05", "00:00:10.500", vast.IconWindow{Start: 5 * time.Second, End: 15500 * time.Millisecond}},
+		{"", "00:01:00", vast.IconWindow{Start: 0, End: 30 * time.Second}},
+		{"00:00:45", "00:00:10", vast.IconWindow{Start: 30 * time.Second, End: 30 * time.Second}},
+	}
+
+	for _, testCase := range tests {
+		icon := vast.Icon{Offset: testCase.offset, Duration: testCase.duration}
+
+		window, err := icon.Window(30 * time.Second)
+		if err != nil {
+			t.Error("unexpected error")
+		}
+
+		if window != testCase.expected {
+			t.Errorf("wrong window for %q %q: %v", testCase.offset, testCase.duration, window)
+		}
+	}
+
+	for _, icon := range []vast.Icon{{Offset: "5s"}, {Duration: "10"}} {
+		if _, err := icon.Window(30 * time.Second); !errors.Is(err, vast.ErrInvalidDuration) {
+			t.Error("expected error")
+		}
+	}
+}
+
+func TestIconWindow_Contains(t *testing.T) {
+	window := vast.IconWindow{Start: 5 * time.Second, End: 10 * time.Second}
+
+	if window.Contains(4*time.Second) || !window.Contains(5*time.Second) || !window.Contains(9*time.Second) || window.Contains(10*time.Second) {
+		t.Error("wrong result")
+	}
+}
+
+func TestIcon_Layout(t *testing.T) {
+	tests := []struct {
+		icon       vast.Icon
+		pixelRatio float64
+		expected   vast.IconLayout
+	}{
+		{vast.Icon{Width: 20, Height: 10, XPosition: "left", YPosition: "top"}, 0, vast.IconLayout{X: 0, Y: 0, Width: 20, Height: 10}},
+		{vast.Icon{Width: 20, Height: 10, XPosition: "right", YPosition: "bottom"}, 1, vast.IconLayout{X: 620, Y: 350, Width: 20, Height: 10}},
+		{vast.Icon{Width: 20, Height: 10, XPosition: "right", YPosition: "bottom"}, 2, vast.IconLayout{X: 600, Y: 340, Width: 40, Height: 20}},
+		{vast.Icon{Width: 20, Height: 10, XPosition: "10", YPosition: " 5 "}, 1.5, vast.IconLayout{X: 15, Y: 8, Width: 30, Height: 15}},
+		{vast.Icon{Width: 20, Height: 10, XPosition: "1000", YPosition: "360"}, 1, vast.IconLayout{X: 620, Y: 350, Width: 20, Height: 10}},
+		{vast.Icon{Width: 800, Height: 10}, 1, vast.IconLayout{X: 0, Y: 0, Width: 800, Height: 10}},
+		{vast.Icon{Width: 40, Height: 20, XPosition: "right", YPosition: "10", PXRatio: 2}, 2, vast.IconLayout{X: 600, Y: 20, Width: 40, Height: 20}},
+		{vast.Icon{Width: 40, Height: 20, XPosition: "right", YPosition: "10", PXRatio: 2}, 1, vast.IconLayout{X: 620, Y: 10, Width: 20, Height: 10}},
+	}
+
+	for _, testCase := range tests {
+		layout, err := testCase.icon.Layout(640, 360, testCase.pixelRatio)
+		if err != nil {
+			t.Error("unexpected error")
+		}
+
+		if layout != testCase.expected {
+			t.Errorf("wrong layout for %v: %v", testCase.icon, layout)
+		}
+	}
+
+	for _, icon := range []vast.Icon{{XPosition: "center"}, {YPosition: "-5"}, {YPosition: "left"}} {
+		if _, err := icon.Layout(640, 360, 1); !errors.Is(err, vast.ErrInvalidPosition) {
+			t.Errorf("expected error for %v", icon)
+		}
+	}
+}
+
+func TestIconClicks_FallbackImage(t *testing.T) {
+	iconClicks := &vast.IconClicks{IconClickFallbackImages: &vast.IconClickFallbackImages{IconClickFallbackImage: []vast.IconClickFallbackImage{
+		{Width: 1920, Height: 1080, AltText: "missing"},
+		{AltText: "unsized", StaticResource: &vast.CData{Value: "https://example.com/unsized.png"}},
+		{Width: 1920, Height: 1080, AltText: "large", StaticResource: &vast.CData{Value: "https://example.com/large.png"}},
+		{Width: 1280, Height: 720, AltText: "medium", StaticResource: &vast.CData{Value: "https://example.com/medium.png"}},
+		{Width: 960, Height: 540, AltText: "small", StaticResource: &vast.CData{Value: " "}},
+	}}}
+
+	tests := []struct {
+		width    int
+		height   int
+		expected string
+	}{
+		{3840, 2160, "large"},
+		{1920, 1080, "large"},
+		{1280, 800, "medium"},
+		{800, 600, "unsized"},
+	}
+
+	for _, testCase := range tests {
+		if image := iconClicks.FallbackImage(testCase.width, testCase.height); image == nil || image.AltText != testCase.expected {
+			t.Errorf("wrong image for %dx%d: %v", testCase.width, testCase.height, image)
+		}
+	}
+
+	iconClicks.IconClickFallbackImages.IconClickFallbackImage = iconClicks.IconClickFallbackImages.IconClickFallbackImage[2:4]
+	if image := iconClicks.FallbackImage(800, 600); image == nil || image.AltText != "medium" {
+		t.Errorf("wrong image: %v", image)
+	}
+
+	var nilClicks *vast.IconClicks
+	if nilClicks.FallbackImage(800, 600) != nil || (&vast.IconClicks{}).FallbackImage(800, 600) != nil {
+		t.Error("unexpected image")
+	}
+}
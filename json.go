@@ -0,0 +1,103 @@
+package vast
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// JSONSchema contains the JSON Schema (draft 2020-12) of the JSON representation of VAST.
+//
+//go:embed vast.schema.json
+var JSONSchema []byte
+
+// MarshalJSON encodes a NumericBool as a JSON boolean.
+func (n NumericBool) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bool(n))
+}
+
+// UnmarshalJSON decodes a NumericBool from a JSON boolean.
+func (n *NumericBool) UnmarshalJSON(data []byte) error {
+	var value bool
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*n = NumericBool(value)
+
+	return nil
+}
+
+// MarshalJSON encodes a CData as a JSON string.
+func (c CData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Value)
+}
+
+// UnmarshalJSON decodes a CData from a JSON string.
+func (c *CData) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &c.Value)
+}
+
+// MarshalJSON encodes a Duration as a JSON number of seconds. Values which are no valid durations are encoded as
+// JSON strings, so that they are not lost.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	duration, err := d.Parse()
+	if err != nil {
+		return json.Marshal(string(d))
+	}
+
+	return json.Marshal(duration.Seconds())
+}
+
+// UnmarshalJSON decodes a Duration from a JSON number of seconds, see NewDuration, or from a JSON string.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch value := value.(type) {
+	case float64:
+		*d = NewDuration(time.Duration(math.Round(value * float64(time.Second))))
+	case string:
+		*d = Duration(value)
+	default:
+		return fmt.Errorf("%w %s", ErrInvalidDuration, data)
+	}
+
+	return nil
+}
+
+// ReadJSON creates a new instance of VAST and reads the JSON representation from an io.ReadCloser.
+func ReadJSON(reader io.ReadCloser) (*VAST, error) {
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Join(ErrReadVAST, err)
+	}
+
+	_ = reader.Close()
+
+	vast := &VAST{}
+	if err := json.Unmarshal(body, vast); err != nil {
+		return nil, errors.Join(ErrUnmarshalVAST, err)
+	}
+
+	return vast, nil
+}
+
+// JSON marshals the VAST to its JSON representation with indentations.
+// The representation is described by JSONSchema. It is defined by the json tags of the model and the MarshalJSON and
+// UnmarshalJSON methods of NumericBool, CData, Duration and Attributes, so VAST needs no JSON methods of its own and
+// can be used with encoding/json directly. JSON and ReadJSON only correspond to Bytes and Read.
+func (m *VAST) JSON() ([]byte, error) {
+	jsonData, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, errors.Join(ErrMarshalVAST, err)
+	}
+
+	return jsonData, nil
+}
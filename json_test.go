@@ -0,0 +1,248 @@
+package vast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNumericBool_MarshalJSON(t *testing.T) {
+	result, err := json.Marshal(vast.NumericBool(true))
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if !bytes.Equal(result, []byte("true")) {
+		t.Error("unexpected result")
+	}
+}
+
+func TestNumericBool_UnmarshalJSON(t *testing.T) {
+	var numericBool vast.NumericBool
+
+	if err := json.Unmarshal([]byte("true"), &numericBool); err != nil {
+		t.Error("unexpected error")
+	}
+
+	if !numericBool {
+		t.Error("unexpected result")
+	}
+}
+
+func TestNumericBool_UnmarshalJSON_invalid(t *testing.T) {
+	var numericBool vast.NumericBool
+
+	if err := json.Unmarshal([]byte(`"1"`), &numericBool); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestCData_MarshalJSON(t *testing.T) {
+	result, err := json.Marshal(vast.CData{Value: "https://example.com/"})
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if !bytes.Equal(result, []byte(`"https://example.com/"`)) {
+		t.Error("unexpected result")
+	}
+}
+
+func TestDuration_MarshalJSON(t *testing.T) {
+	tests := map[vast.Duration]string{
+		"00:00:16":     `16`,
+		"01:02:03.450": `3723.45`,
+		"":             `""`,
+		"16":           `"16"`,
+	}
+
+	for duration, expected := range tests {
+		result, err := json.Marshal(duration)
+		if err != nil {
+			t.Error("unexpected error")
+		}
+
+		if string(result) != expected {
+			t.Errorf("wrong result for %q: %s", duration, result)
+		}
+	}
+}
+
+func TestDuration_UnmarshalJSON(t *testing.T) {
+	tests := map[string]vast.Duration{
+		`16`:         "00:00:16",
+		`3723.45`:    "01:02:03.450",
+		`0.0004`:     "00:00:00",
+		`"later"`:    "later",
+		`"00:00:05"`: "00:00:05",
+	}
+
+	for data, expected := range tests {
+		var duration vast.Duration
+		if err := json.Unmarshal([]byte(data), &duration); err != nil {
+			t.Error("unexpected error")
+		}
+
+		if duration != expected {
+			t.Errorf("wrong duration for %s: %q", data, duration)
+		}
+	}
+
+	var duration vast.Duration
+	for _, data := range []string{`true`, `{`} {
+		if err := json.Unmarshal([]byte(data), &duration); err == nil {
+			t.Errorf("expected error for %s", data)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`[]`), &duration); !errors.Is(err, vast.ErrInvalidDuration) {
+		t.Error("expected error")
+	}
+}
+
+func TestReadJSON_ErrReadVAST(t *testing.T) {
+	reader := NopReadCloser{}
+	if _, err := vast.ReadJSON(reader); !errors.Is(err, vast.ErrReadVAST) {
+		t.Error("unexpected error")
+	}
+}
+
+func TestReadJSON_ErrUnmarshalVAST(t *testing.T) {
+	reader := io.NopCloser(strings.NewReader(`{"ad": {}}`))
+	if _, err := vast.ReadJSON(reader); !errors.Is(err, vast.ErrUnmarshalVAST) {
+		t.Error("unexpected error")
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, testCase := range iabFixtures {
+		t.Run(testCase, func(t *testing.T) {
+			testVAST, err := vast.Read(mustOpenFixture(testCase))
+			if err != nil {
+				t.Error("unexpected error")
+			}
+
+			output, err := testVAST.JSON()
+			if err != nil {
+				t.Error("unexpected error")
+			}
+
+			outputVAST, err := vast.ReadJSON(io.NopCloser(bytes.NewReader(output)))
+			if err != nil {
+				t.Error("unexpected error")
+			}
+
+			if diff := cmp.Diff(testVAST, outputVAST); diff != "" {
+				t.Errorf("wrong VAST: %s", diff)
+			}
+		})
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(vast.JSONSchema, &schema); err != nil {
+		t.Fatal("unexpected error")
+	}
+
+	for _, testCase := range iabFixtures {
+		t.Run(testCase, func(t *testing.T) {
+			testVAST, err := vast.Read(mustOpenFixture(testCase))
+			if err != nil {
+				t.Error("unexpected error")
+			}
+
+			output, err := testVAST.JSON()
+			if err != nil {
+				t.Error("unexpected error")
+			}
+
+			var document any
+			if err := json.Unmarshal(output, &document); err != nil {
+				t.Error("unexpected error")
+			}
+
+			for _, violation := range validateJSONSchema(schema, schema, document, "") {
+				t.Errorf("schema violation: %s", violation)
+			}
+		})
+	}
+}
+
+// validateJSONSchema implements the subset of JSON Schema used by vast.JSONSchema.
+func validateJSONSchema(root, schema map[string]any, value any, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		return validateJSONSchema(root, root["$defs"].(map[string]any)[name].(map[string]any), value, path)
+	}
+
+	var violations []string
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected object", path)}
+		}
+
+		properties := schema["properties"].(map[string]any)
+		for key, propertyValue := range object {
+			propertySchema, ok := properties[key].(map[string]any)
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%s: unexpected property %q", path, key))
+				continue
+			}
+
+			violations = append(violations, validateJSONSchema(root, propertySchema, propertyValue, path+"/"+key)...)
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected array", path)}
+		}
+
+		for i, item := range array {
+			violations = append(violations, validateJSONSchema(root, schema["items"].(map[string]any), item, fmt.Sprintf("%s/%d", path, i))...)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected string", path)}
+		}
+
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(str) {
+			violations = append(violations, fmt.Sprintf("%s: %q does not match %q", path, str, pattern))
+		}
+
+		if enum, ok := schema["enum"].([]any); ok && !containsJSONValue(enum, str) {
+			violations = append(violations, fmt.Sprintf("%s: %q is not allowed", path, str))
+		}
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			return []string{fmt.Sprintf("%s: expected number", path)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: expected boolean", path)}
+		}
+	}
+
+	return violations
+}
+
+func containsJSONValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
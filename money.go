@@ -0,0 +1,280 @@
+package vast
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidCurrency     = errors.New("invalid currency")
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrCurrencyMismatch    = errors.New("currency mismatch")
+	ErrMissingExchangeRate = errors.New("missing exchange rate")
+	ErrNoPrice             = errors.New("no price")
+)
+
+// currencyMinorUnits are the active ISO 4217 currencies with the number of digits of their minor unit.
+var currencyMinorUnits = map[Currency]int{
+	"AED": 2, "AFN": 2, "ALL": 2, "AMD": 2, "ANG": 2, "AOA": 2, "ARS": 2, "AUD": 2, "AWG": 2, "AZN": 2,
+	"BAM": 2, "BBD": 2, "BDT": 2, "BGN": 2, "BHD": 3, "BIF": 0, "BMD": 2, "BND": 2, "BOB": 2, "BOV": 2,
+	"BRL": 2, "BSD": 2, "BTN": 2, "BWP": 2, "BYN": 2, "BZD": 2, "CAD": 2, "CDF": 2, "CHE": 2, "CHF": 2,
+	"CHW": 2, "CLF": 4, "CLP": 0, "CNY": 2, "COP": 2, "COU": 2, "CRC": 2, "CUP": 2, "CVE": 2, "CZK": 2,
+	"DJF": 0, "DKK": 2, "DOP": 2, "DZD": 2, "EGP": 2, "ERN": 2, "ETB": 2, "EUR": 2, "FJD": 2, "FKP": 2,
+	"GBP": 2, "GEL": 2, "GHS": 2, "GIP": 2, "GMD": 2, "GNF": 0, "GTQ": 2, "GYD": 2, "HKD": 2, "HNL": 2,
+	"HTG": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "IQD": 3, "IRR": 2, "ISK": 0, "JMD": 2, "JOD": 3,
+	"JPY": 0, "KES": 2, "KGS": 2, "KHR": 2, "KMF": 0, "KPW": 2, "KRW": 0, "KWD": 3, "KYD": 2, "KZT": 2,
+	"LAK": 2, "LBP": 2, "LKR": 2, "LRD": 2, "LSL": 2, "LYD": 3, "MAD": 2, "MDL": 2, "MGA": 2, "MKD": 2,
+	"MMK": 2, "MNT": 2, "MOP": 2, "MRU": 2, "MUR": 2, "MVR": 2, "MWK": 2, "MXN": 2, "MXV": 2, "MYR": 2,
+	"MZN": 2, "NAD": 2, "NGN": 2, "NIO": 2, "NOK": 2, "NPR": 2, "NZD": 2, "OMR": 3, "PAB": 2, "PEN": 2,
+	"PGK": 2, "PHP": 2, "PKR": 2, "PLN": 2, "PYG": 0, "QAR": 2, "RON": 2, "RSD": 2, "RUB": 2, "RWF": 0,
+	"SAR": 2, "SBD": 2, "SCR": 2, "SDG": 2, "SEK": 2, "SGD": 2, "SHP": 2, "SLE": 2, "SOS": 2, "SRD": 2,
+	"SSP": 2, "STN": 2, "SVC": 2, "SYP": 2, "SZL": 2, "THB": 2, "TJS": 2, "TMT": 2, "TND": 3, "TOP": 2,
+	"TRY": 2, "TTD": 2, "TWD": 2, "TZS": 2, "UAH": 2, "UGX": 0, "USD": 2, "USN": 2, "UYI": 0, "UYU": 2,
+	"UYW": 4, "UZS": 2, "VED": 2, "VES": 2, "VND": 0, "VUV": 0, "WST": 2, "XAF": 0, "XCD": 2, "XCG": 2,
+	"XOF": 0, "XPF": 0, "YER": 2, "ZAR": 2, "ZMW": 2, "ZWG": 2,
+}
+
+// Normalize returns the currency code in uppercase without surrounding white space.
+func (c Currency) Normalize() Currency {
+	return Currency(strings.ToUpper(strings.TrimSpace(string(c))))
+}
+
+// Valid checks whether the currency is an active ISO 4217 currency. The code is case-insensitive.
+func (c Currency) Valid() bool {
+	_, ok := currencyMinorUnits[c.Normalize()]
+	return ok
+}
+
+// MinorUnits returns the number of digits of the minor unit of the currency, e.g. 2 for USD and 0 for JPY.
+func (c Currency) MinorUnits() (int, bool) {
+	units, ok := currencyMinorUnits[c.Normalize()]
+	return units, ok
+}
+
+// Money is an amount of an ISO 4217 currency. The amount is a rational number, so that arithmetic is exact.
+// The zero value is an amount of zero without currency.
+type Money struct {
+	amount   *big.Rat
+	currency Currency
+}
+
+// NewMoney creates an amount of money from a decimal string, e.g. `1.23`.
+func NewMoney(amount string, currency Currency) (Money, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidAmount, amount)
+	}
+
+	return NewMoneyFromRat(value, currency)
+}
+
+// NewMoneyFromRat creates an amount of money from a rational number. The number is copied.
+func NewMoneyFromRat(amount *big.Rat, currency Currency) (Money, error) {
+	if !currency.Valid() {
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidCurrency, currency)
+	}
+
+	return Money{amount: new(big.Rat).Set(amount), currency: currency.Normalize()}, nil
+}
+
+func (m Money) rat() *big.Rat {
+	if m.amount == nil {
+		return new(big.Rat)
+	}
+
+	return m.amount
+}
+
+// Currency returns the normalized currency code.
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// Rat returns a copy of the amount.
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).Set(m.rat())
+}
+
+// Float64 returns the nearest float64 value of the amount.
+func (m Money) Float64() float64 {
+	value, _ := m.rat().Float64()
+	return value
+}
+
+// Add returns the sum of two amounts of the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency)
+	}
+
+	return Money{amount: new(big.Rat).Add(m.rat(), other.rat()), currency: m.currency}, nil
+}
+
+// Sub returns the difference of two amounts of the same currency.
+func (m Money) Sub(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency)
+	}
+
+	return Money{amount: new(big.Rat).Sub(m.rat(), other.rat()), currency: m.currency}, nil
+}
+
+// Mul returns the amount multiplied by a factor, e.g. a revenue share.
+func (m Money) Mul(factor *big.Rat) Money {
+	return Money{amount: new(big.Rat).Mul(m.rat(), factor), currency: m.currency}
+}
+
+// Cmp compares two amounts of the same currency and returns -1, 0 or +1.
+func (m Money) Cmp(other Money) (int, error) {
+	if m.currency != other.currency {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency)
+	}
+
+	return m.rat().Cmp(other.rat()), nil
+}
+
+// Round rounds the amount half away from zero to the minor unit of the currency.
+func (m Money) Round() Money {
+	rounded, _ := new(big.Rat).SetString(m.decimal())
+	return Money{amount: rounded, currency: m.currency}
+}
+
+// decimal formats the amount rounded half away from zero to the minor unit of the currency.
+func (m Money) decimal() string {
+	units, ok := m.currency.MinorUnits()
+	if !ok {
+		units = 2
+	}
+
+	return m.rat().FloatString(units)
+}
+
+// String formats the amount rounded to the minor unit of the currency, followed by the currency, e.g. `1.23 USD`.
+func (m Money) String() string {
+	return strings.TrimSpace(m.decimal() + " " + string(m.currency))
+}
+
+// ExchangeRates are the rates of currencies relative to a common base currency, i.e. the amount of a currency which
+// equals one unit of the base currency. The rate of the base currency is 1.
+type ExchangeRates map[Currency]*big.Rat
+
+// Convert converts the amount to another currency. The result is not rounded.
+func (m Money) Convert(to Currency, rates ExchangeRates) (Money, error) {
+	if !to.Valid() {
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidCurrency, to)
+	}
+
+	to = to.Normalize()
+	if to == m.currency {
+		return Money{amount: m.Rat(), currency: to}, nil
+	}
+
+	from, ok := rates[m.currency]
+	if !ok || from.Sign() <= 0 {
+		return Money{}, fmt.Errorf("%w for %s", ErrMissingExchangeRate, m.currency)
+	}
+
+	rate, ok := rates[to]
+	if !ok || rate.Sign() <= 0 {
+		return Money{}, fmt.Errorf("%w for %s", ErrMissingExchangeRate, to)
+	}
+
+	amount := new(big.Rat).Mul(m.rat(), rate)
+
+	return Money{amount: amount.Quo(amount, from), currency: to}, nil
+}
+
+// Price is the price of an ad in its pricing model.
+type Price struct {
+	Money
+	Model Model
+}
+
+// Pricing returns the price as Pricing element.
+func (p Price) Pricing() *Pricing {
+	return &Pricing{Value: p.Float64(), Model: p.Model, Currency: p.Currency()}
+}
+
+// Price returns the exact price. Since the value is parsed as float64, the shortest decimal representation of the
+// float64 is used, which equals the value of the document for up to 15 significant digits.
+func (p *Pricing) Price() (Price, error) {
+	money, err := NewMoney(strconv.FormatFloat(p.Value, 'f', -1, 64), p.Currency)
+	if err != nil {
+		return Price{}, err
+	}
+
+	return Price{Money: money, Model: Model(strings.ToUpper(strings.TrimSpace(string(p.Model))))}, nil
+}
+
+// Price returns the price of the Pricing element or, if there is none, of the first extension containing a price.
+// Supported extensions contain a `Price` or `Pricing` element with `model` and `currency` attributes, like the
+// Pricing element:
+//
+//	<Extension type="price">
+//	  <Price model="CPM" currency="USD"><![CDATA[1.23]]></Price>
+//	</Extension>
+//
+// Extensions with the type `price` or `pricing` may contain the value only, the model is CPM and the currency USD
+// if they are not given as attributes.
+func (a *AdDefinitionBase) Price() (Price, error) {
+	if a.Pricing != nil {
+		return a.Pricing.Price()
+	}
+
+	if a.Extensions != nil {
+		for _, extension := range a.Extensions.Extension {
+			if price, err := extensionPrice(extension); !errors.Is(err, ErrNoPrice) {
+				return price, err
+			}
+		}
+	}
+
+	return Price{}, ErrNoPrice
+}
+
+// extensionPrice reads the price of an extension. It returns ErrNoPrice if the extension contains no price.
+func extensionPrice(extension Extension) (Price, error) {
+	var (
+		value    string
+		model    = CPMModel
+		currency = "USD"
+		found    bool
+	)
+
+	for _, node := range extension.Find("//*") {
+		if name := strings.ToLower(node.Name); name != "price" && name != "pricing" {
+			continue
+		}
+
+		value, found = node.Content(), true
+
+		if attribute, ok := node.Attribute("model"); ok {
+			model = Model(attribute)
+		}
+
+		if attribute, ok := node.Attribute("currency"); ok {
+			currency = attribute
+		}
+
+		break
+	}
+
+	extensionType := strings.ToLower(strings.TrimSpace(extension.Type))
+	if !found && (extensionType == "price" || extensionType == "pricing") {
+		value = extension.Content()
+	}
+
+	if !found && strings.TrimSpace(value) == "" {
+		return Price{}, ErrNoPrice
+	}
+
+	money, err := NewMoney(value, Currency(currency))
+	if err != nil {
+		return Price{}, err
+	}
+
+	return Price{Money: money, Model: Model(strings.ToUpper(strings.TrimSpace(string(model))))}, nil
+}
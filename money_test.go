@@ -0,0 +1,194 @@
+package vast_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"math/big"
+	"testing"
+)
+
+func mustMoney(t *testing.T, amount string, currency vast.Currency) vast.Money {
+	t.Helper()
+
+	money, err := vast.NewMoney(amount, currency)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return money
+}
+
+func TestCurrency_Valid(t *testing.T) {
+	if !vast.Currency("usd").Valid() || !vast.Currency(" EUR ").Valid() || vast.Currency("EURO").Valid() || vast.Currency("XYZ").Valid() {
+		t.Error("wrong result")
+	}
+
+	if units, ok := vast.Currency("JPY").MinorUnits(); !ok || units != 0 {
+		t.Errorf("wrong minor units: %d", units)
+	}
+
+	if units, ok := vast.Currency("KWD").MinorUnits(); !ok || units != 3 {
+		t.Errorf("wrong minor units: %d", units)
+	}
+}
+
+func TestNewMoney(t *testing.T) {
+	if _, err := vast.NewMoney("1,23", "USD"); !errors.Is(err, vast.ErrInvalidAmount) {
+		t.Error("expected error")
+	}
+
+	if _, err := vast.NewMoney("1.23", "EURO"); !errors.Is(err, vast.ErrInvalidCurrency) {
+		t.Error("expected error")
+	}
+
+	money := mustMoney(t, " 0.1 ", "usd")
+	if money.Currency() != "USD" || money.Rat().Cmp(big.NewRat(1, 10)) != 0 || money.Float64() != 0.1 {
+		t.Errorf("wrong money: %s", money)
+	}
+
+	if (vast.Money{}).String() != "0.00" {
+		t.Errorf("wrong zero value: %s", vast.Money{})
+	}
+}
+
+func TestMoney_arithmetic(t *testing.T) {
+	sum := mustMoney(t, "0", "USD")
+
+	for range 10 {
+		var err error
+		if sum, err = sum.Add(mustMoney(t, "0.1", "USD")); err != nil {
+			t.Error("unexpected error")
+		}
+	}
+
+	if cmp, err := sum.Cmp(mustMoney(t, "1", "USD")); err != nil || cmp != 0 {
+		t.Errorf("wrong sum: %s", sum)
+	}
+
+	difference, err := sum.Sub(mustMoney(t, "0.3", "USD"))
+	if err != nil || difference.String() != "0.70 USD" {
+		t.Errorf("wrong difference: %s", difference)
+	}
+
+	if share := mustMoney(t, "1.25", "USD").Mul(big.NewRat(7, 10)); share.String() != "0.88 USD" || share.Round().Rat().Cmp(big.NewRat(88, 100)) != 0 {
+		t.Errorf("wrong share: %s", share)
+	}
+
+	if rounded := mustMoney(t, "1234.5", "JPY").Round(); rounded.String() != "1235 JPY" {
+		t.Errorf("wrong rounding: %s", rounded)
+	}
+
+	if _, err := sum.Add(mustMoney(t, "1", "EUR")); !errors.Is(err, vast.ErrCurrencyMismatch) {
+		t.Error("expected error")
+	}
+
+	if _, err := sum.Sub(mustMoney(t, "1", "EUR")); !errors.Is(err, vast.ErrCurrencyMismatch) {
+		t.Error("expected error")
+	}
+
+	if _, err := sum.Cmp(mustMoney(t, "1", "EUR")); !errors.Is(err, vast.ErrCurrencyMismatch) {
+		t.Error("expected error")
+	}
+}
+
+func TestMoney_Convert(t *testing.T) {
+	rates := vast.ExchangeRates{"EUR": big.NewRat(1, 1), "USD": big.NewRat(108, 100), "JPY": big.NewRat(16250, 100)}
+	money := mustMoney(t, "2.70", "USD")
+
+	tests := map[vast.Currency]string{"EUR": "2.50 EUR", "jpy": "406 JPY", "USD": "2.70 USD"}
+
+	for currency, expected := range tests {
+		converted, err := money.Convert(currency, rates)
+		if err != nil || converted.String() != expected {
+			t.Errorf("wrong conversion to %s: %s", currency, converted)
+		}
+	}
+
+	if _, err := money.Convert("GBP", rates); !errors.Is(err, vast.ErrMissingExchangeRate) {
+		t.Error("expected error")
+	}
+
+	if _, err := mustMoney(t, "1", "GBP").Convert("USD", rates); !errors.Is(err, vast.ErrMissingExchangeRate) {
+		t.Error("expected error")
+	}
+
+	if _, err := money.Convert("EURO", rates); !errors.Is(err, vast.ErrInvalidCurrency) {
+		t.Error("expected error")
+	}
+}
+
+func TestAdDefinitionBase_Price(t *testing.T) {
+	tests := []struct {
+		base     vast.AdDefinitionBase
+		expected string
+		model    vast.Model
+	}{
+		{
+			base:     vast.AdDefinitionBase{Pricing: &vast.Pricing{Value: 25.1, Model: "cpm", Currency: "EUR"}},
+			expected: "25.10 EUR",
+			model:    vast.CPMModel,
+		},
+		{
+			base: vast.AdDefinitionBase{Extensions: &vast.Extensions{Extension: []vast.Extension{
+				{Type: "waterfall", Nodes: []vast.Node{{Name: "Waterfall", Attributes: vast.Attributes{{Name: xml.Name{Local: "index"}, Value: "0"}}}}},
+				{Type: "price", Nodes: []vast.Node{{Name: "Price", Attributes: vast.Attributes{
+					{Name: xml.Name{Local: "model"}, Value: "CPC"},
+					{Name: xml.Name{Local: "currency"}, Value: "GBP"},
+				}, Children: []vast.Node{{Text: " 0.35 "}}}}},
+			}}},
+			expected: "0.35 GBP",
+			model:    vast.CPCModel,
+		},
+		{
+			base:     vast.AdDefinitionBase{Extensions: &vast.Extensions{Extension: []vast.Extension{{Type: "Pricing", Nodes: []vast.Node{{Text: "1.5"}}}}}},
+			expected: "1.50 USD",
+			model:    vast.CPMModel,
+		},
+	}
+
+	for _, testCase := range tests {
+		price, err := testCase.base.Price()
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if price.String() != testCase.expected || price.Model != testCase.model {
+			t.Errorf("wrong price: %s %s", price, price.Model)
+		}
+	}
+}
+
+func TestAdDefinitionBase_Price_error(t *testing.T) {
+	tests := map[error]vast.AdDefinitionBase{
+		vast.ErrNoPrice: {Extensions: &vast.Extensions{Extension: []vast.Extension{
+			{Type: "waterfall", Nodes: []vast.Node{{Text: "1.5"}}},
+			{Type: "price", Nodes: []vast.Node{{Text: " "}}},
+			{Type: "price"},
+		}}},
+		vast.ErrInvalidCurrency: {Pricing: &vast.Pricing{Value: 1, Currency: "EURO"}},
+		vast.ErrInvalidAmount: {Extensions: &vast.Extensions{Extension: []vast.Extension{{Nodes: []vast.Node{{
+			Name:       "Price",
+			Attributes: vast.Attributes{{Name: xml.Name{Local: "currency"}, Value: "USD"}},
+			Children:   []vast.Node{{Text: "n/a"}},
+		}}}}}},
+	}
+
+	for expected, base := range tests {
+		if _, err := base.Price(); !errors.Is(err, expected) {
+			t.Errorf("expected %v, got %v", expected, err)
+		}
+	}
+}
+
+func TestPrice_Pricing(t *testing.T) {
+	price, err := (&vast.Pricing{Value: 0.3, Model: vast.CPVModel, Currency: "USD"}).Price()
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if diff := cmp.Diff(&vast.Pricing{Value: 0.3, Model: vast.CPVModel, Currency: "USD"}, price.Pricing()); diff != "" {
+		t.Errorf("wrong pricing: %s", diff)
+	}
+}
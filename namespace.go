@@ -0,0 +1,92 @@
+package vast
+
+import (
+	"encoding/xml"
+	"sync"
+)
+
+// XMLSchemaInstanceNamespace is the namespace of the `xsi` attributes, e.g. `xsi:noNamespaceSchemaLocation`.
+const XMLSchemaInstanceNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// wellKnownPrefixes are the conventional prefixes of namespaces, used if a namespace is not declared.
+var wellKnownPrefixes = map[string]string{
+	XMLSchemaInstanceNamespace:         "xsi",
+	"http://www.w3.org/2001/XMLSchema": "xs",
+}
+
+// encoderScopes holds the namespace declarations of the root element while an encoder marshals a VAST, so that
+// extensions can use the prefixes declared on the root. Extensions are marshalled by encoding/xml, which only passes
+// the encoder to their MarshalXML methods, so the scope is keyed by the encoder and removed when the VAST is written.
+// Extensions marshalled on their own declare all namespaces they use.
+var encoderScopes sync.Map
+
+// rawVAST is VAST without its MarshalXML method.
+type rawVAST VAST
+
+// MarshalXML encodes the VAST with the namespace declarations and prefixed attributes of the root element.
+func (m VAST) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	scope := newNamespaceScope()
+
+	root := rawVAST(m)
+	root.Attributes = scope.attributes(m.Attributes)
+
+	encoderScopes.Store(encoder, scope)
+	defer encoderScopes.Delete(encoder)
+
+	return encoder.EncodeElement(root, start)
+}
+
+// Namespaces returns the prefixes and namespaces declared on the root element, without the default namespace.
+func (m *VAST) Namespaces() map[string]string {
+	namespaces := map[string]string{}
+
+	for _, attribute := range m.Attributes {
+		if attribute.Name.Space == "xmlns" {
+			namespaces[attribute.Name.Local] = attribute.Value
+		}
+	}
+
+	return namespaces
+}
+
+// DeclareNamespace declares a prefix for a namespace on the root element, replacing a declaration of the prefix.
+func (m *VAST) DeclareNamespace(prefix, namespace string) {
+	m.setAttribute(xml.Name{Space: "xmlns", Local: prefix}, namespace)
+}
+
+// SetSchemaLocation sets the `xsi:schemaLocation` attribute of the root element, i.e. pairs of namespaces and
+// locations of their XML schemas separated by white space, and declares the `xsi` prefix if necessary.
+func (m *VAST) SetSchemaLocation(location string) {
+	m.setSchemaInstanceAttribute("schemaLocation", location)
+}
+
+// SetNoNamespaceSchemaLocation sets the `xsi:noNamespaceSchemaLocation` attribute of the root element, e.g.
+// `vast.xsd`, and declares the `xsi` prefix if necessary.
+func (m *VAST) SetNoNamespaceSchemaLocation(location string) {
+	m.setSchemaInstanceAttribute("noNamespaceSchemaLocation", location)
+}
+
+func (m *VAST) setSchemaInstanceAttribute(name, value string) {
+	declared := false
+
+	for _, namespace := range m.Namespaces() {
+		declared = declared || namespace == XMLSchemaInstanceNamespace
+	}
+
+	if !declared {
+		m.DeclareNamespace("xsi", XMLSchemaInstanceNamespace)
+	}
+
+	m.setAttribute(xml.Name{Space: XMLSchemaInstanceNamespace, Local: name}, value)
+}
+
+func (m *VAST) setAttribute(name xml.Name, value string) {
+	for i := range m.Attributes {
+		if m.Attributes[i].Name == name {
+			m.Attributes[i].Value = value
+			return
+		}
+	}
+
+	m.Attributes = append(m.Attributes, xml.Attr{Name: name, Value: value})
+}
@@ -0,0 +1,118 @@
+package vast_test
+
+import (
+	"encoding/xml"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"io"
+	"strings"
+	"testing"
+)
+
+const namespaceVAST = `<VAST version="3.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="vast.xsd" xmlns:ex="https://example.com/ns">
+  <Ad>
+    <InLine>
+      <Extensions>
+        <Extension type="example">
+          <ex:Skin ex:color="red"><ex:Image>https://example.com/skin.png</ex:Image></ex:Skin>
+        </Extension>
+      </Extensions>
+    </InLine>
+  </Ad>
+</VAST>`
+
+func TestVAST_MarshalXML_namespaces(t *testing.T) {
+	testVAST, err := vast.Read(io.NopCloser(strings.NewReader(namespaceVAST)))
+	if err != nil {
+		t.Fatal("unexpected error")
+	}
+
+	expected := map[string]string{"xsi": vast.XMLSchemaInstanceNamespace, "ex": "https://example.com/ns"}
+	if diff := cmp.Diff(expected, testVAST.Namespaces()); diff != "" {
+		t.Errorf("wrong namespaces: %s", diff)
+	}
+
+	data, err := testVAST.Bytes()
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	for _, expected := range []string{
+		`<VAST version="3.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="vast.xsd" xmlns:ex="https://example.com/ns">`,
+		`<ex:Skin ex:color="red"><ex:Image>https://example.com/skin.png</ex:Image></ex:Skin>`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("missing %s in %s", expected, data)
+		}
+	}
+
+	reread, err := vast.Read(io.NopCloser(strings.NewReader(string(data))))
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if diff := cmp.Diff(testVAST, reread); diff != "" {
+		t.Errorf("wrong VAST: %s", diff)
+	}
+
+	jsonData, err := testVAST.JSON()
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	fromJSON, err := vast.ReadJSON(io.NopCloser(strings.NewReader(string(jsonData))))
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if diff := cmp.Diff(testVAST, fromJSON); diff != "" {
+		t.Errorf("wrong VAST: %s", diff)
+	}
+}
+
+func TestVAST_SetSchemaLocation(t *testing.T) {
+	testVAST := vast.New()
+	testVAST.SetSchemaLocation("http://www.iab.com/VAST vast4.xsd")
+	testVAST.SetNoNamespaceSchemaLocation("vast.xsd")
+	testVAST.SetNoNamespaceSchemaLocation("vast3.xsd")
+	testVAST.DeclareNamespace("ex", "https://example.com/ns")
+	testVAST.DeclareNamespace("ex", "https://example.com/v2")
+
+	data, err := xml.Marshal(testVAST)
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	expected := `<VAST version="4.2" xmlns="http://www.iab.com/VAST" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ` +
+		`xsi:schemaLocation="http://www.iab.com/VAST vast4.xsd" xsi:noNamespaceSchemaLocation="vast3.xsd" xmlns:ex="https://example.com/v2"></VAST>`
+	if string(data) != expected {
+		t.Errorf("wrong XML: %s", data)
+	}
+}
+
+func TestVAST_MarshalXML_undeclared(t *testing.T) {
+	testVAST := &vast.VAST{
+		Version: vast.VAST42Version,
+		Attributes: vast.Attributes{
+			{Name: xml.Name{Space: vast.XMLSchemaInstanceNamespace, Local: "schemaLocation"}, Value: "vast.xsd"},
+			{Name: xml.Name{Space: "https://example.com/ns", Local: "id"}, Value: "1"},
+		},
+		Ad: []vast.Ad{{InLine: &vast.InLine{AdDefinitionBase: vast.AdDefinitionBase{Extensions: &vast.Extensions{Extension: []vast.Extension{{
+			Nodes: []vast.Node{{Name: "Skin", Namespace: "https://example.com/ns"}, {Name: "Other", Namespace: "https://example.com/other"}},
+		}}}}}}},
+	}
+
+	data, err := xml.Marshal(testVAST)
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	for _, expected := range []string{
+		`<VAST version="4.2" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="vast.xsd" xmlns:ns1="https://example.com/ns" ns1:id="1">`,
+		`<Extension><ns1:Skin/><Other xmlns="https://example.com/other"/></Extension>`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("missing %s in %s", expected, data)
+		}
+	}
+}
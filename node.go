@@ -0,0 +1,419 @@
+package vast
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// Node is an XML element or, if it has no name, character data. It represents the arbitrary content of extensions.
+// Comments, processing instructions and directives are not preserved.
+type Node struct {
+	// Name is the local name of the element.
+	Name string `xml:"-" json:"name,omitempty"`
+	// Namespace is the namespace URI of the element if it differs from the namespace of the extension.
+	Namespace  string     `xml:"-" json:"namespace,omitempty"`
+	Attributes Attributes `xml:",any,attr" json:"attributes,omitempty"`
+	Children   []Node     `xml:",any" json:"children,omitempty"`
+	Text       string     `xml:",chardata" json:"text,omitempty"`
+	// CData reports whether the character data is a CDATA section. CDATA sections are detected when the document is
+	// read with Read or ReadJSON, or the extension is created with NewExtension. encoding/xml does not distinguish
+	// CDATA sections, so decoding with xml.Unmarshal or an xml.Decoder reports all character data as text.
+	CData bool `xml:"-" json:"cdata,omitempty"`
+}
+
+// IsElement reports whether the node is an element.
+func (n *Node) IsElement() bool {
+	return n.Name != ""
+}
+
+// Attribute returns the value of the attribute with the local name and whether the element has the attribute.
+func (n *Node) Attribute(name string) (string, bool) {
+	for _, attribute := range n.Attributes {
+		if attribute.Name.Local == name {
+			return attribute.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// SetAttribute sets the value of the attribute with the local name or adds the attribute.
+func (n *Node) SetAttribute(name, value string) {
+	for i := range n.Attributes {
+		if n.Attributes[i].Name.Local == name {
+			n.Attributes[i].Value = value
+			return
+		}
+	}
+
+	n.Attributes = append(n.Attributes, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+}
+
+// Content returns the character data of the node and all its descendants.
+func (n *Node) Content() string {
+	if !n.IsElement() {
+		return n.Text
+	}
+
+	return nodesContent(n.Children)
+}
+
+func nodesContent(nodes []Node) string {
+	var content strings.Builder
+
+	for i := range nodes {
+		content.WriteString(nodes[i].Content())
+	}
+
+	return content.String()
+}
+
+// Find returns the elements matching a path relative to the node. The path consists of local names separated by
+// `/`, where `*` matches any element and a 1-based index like `Verification[2]` selects one of the matching siblings.
+// A path starting with `//` matches its first name at any depth, e.g. `//Price`.
+// The returned nodes can be modified in place.
+func (n *Node) Find(path string) []*Node {
+	return findNodes(n.Children, path)
+}
+
+func findNodes(nodes []Node, path string) []*Node {
+	descendants := strings.HasPrefix(path, "//")
+	steps := strings.Split(strings.Trim(path, "/"), "/")
+
+	if steps[0] == "" {
+		return nil
+	}
+
+	current := []*Node{{Children: nodes}}
+
+	for i, step := range steps {
+		name, index := step, 0
+
+		if open := strings.IndexByte(step, '['); open >= 0 && strings.HasSuffix(step, "]") {
+			value, err := strconv.Atoi(step[open+1 : len(step)-1])
+			if err != nil || value < 1 {
+				return nil
+			}
+
+			name, index = step[:open], value
+		}
+
+		var next []*Node
+
+		for _, parent := range current {
+			var matches []*Node
+
+			if i == 0 && descendants {
+				matches = matchDescendants(parent, name)
+			} else {
+				matches = matchChildren(parent, name)
+			}
+
+			if index > 0 {
+				if index > len(matches) {
+					continue
+				}
+
+				matches = matches[index-1 : index]
+			}
+
+			next = append(next, matches...)
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+func matchChildren(parent *Node, name string) []*Node {
+	var matches []*Node
+
+	for i := range parent.Children {
+		if child := &parent.Children[i]; child.IsElement() && (name == "*" || child.Name == name) {
+			matches = append(matches, child)
+		}
+	}
+
+	return matches
+}
+
+func matchDescendants(parent *Node, name string) []*Node {
+	var matches []*Node
+
+	for _, child := range matchChildren(parent, "*") {
+		if name == "*" || child.Name == name {
+			matches = append(matches, child)
+		}
+
+		matches = append(matches, matchDescendants(child, name)...)
+	}
+
+	return matches
+}
+
+// UnmarshalXML decodes an element with its attributes and content.
+func (n *Node) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	children, err := readNodes(decoder, start.Name.Space)
+	if err != nil {
+		return err
+	}
+
+	*n = Node{Name: start.Name.Local, Namespace: start.Name.Space, Attributes: nodeAttributes(start.Attr), Children: children}
+
+	return nil
+}
+
+// cdataSection precedes the character data of a CDATA section in the tokens of decoders created by decodeXML.
+// Unmarshalling ignores tokens of unknown types, so only readNodes observes it.
+type cdataSection struct{}
+
+// cdataTokenReader reads the tokens of an XML document and marks CDATA sections, which encoding/xml reports as
+// character data like any other text.
+type cdataTokenReader struct {
+	decoder *xml.Decoder
+	data    []byte
+	next    xml.Token
+}
+
+func (r *cdataTokenReader) Token() (xml.Token, error) {
+	if r.next != nil {
+		token := r.next
+		r.next = nil
+
+		return token, nil
+	}
+
+	offset := r.decoder.InputOffset()
+
+	token, err := r.decoder.Token()
+	if _, ok := token.(xml.CharData); ok && bytes.HasPrefix(r.data[offset:], []byte("<![CDATA[")) {
+		r.next = xml.CopyToken(token)
+		return cdataSection{}, nil
+	}
+
+	return token, err
+}
+
+// decodeXML decodes the XML document into v. Unlike xml.Unmarshal, CDATA sections within nodes are detected.
+func decodeXML(data []byte, v any) error {
+	return xml.NewTokenDecoder(&cdataTokenReader{decoder: xml.NewDecoder(bytes.NewReader(data)), data: data}).Decode(v)
+}
+
+// readNodes reads the content of an element until its end. Namespaces equal to the base namespace are omitted.
+func readNodes(decoder *xml.Decoder, base string) ([]Node, error) {
+	var (
+		nodes []Node
+		cdata bool
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			children, err := readNodes(decoder, base)
+			if err != nil {
+				return nil, err
+			}
+
+			node := Node{Name: token.Name.Local, Attributes: nodeAttributes(token.Attr), Children: children}
+			if token.Name.Space != base {
+				node.Namespace = token.Name.Space
+			}
+
+			nodes = append(nodes, node)
+		case cdataSection:
+			cdata = true
+		case xml.CharData:
+			if last := len(nodes) - 1; last >= 0 && !nodes[last].IsElement() && nodes[last].CData == cdata {
+				nodes[last].Text += string(token)
+			} else {
+				nodes = append(nodes, Node{Text: string(token), CData: cdata})
+			}
+
+			cdata = false
+		case xml.EndElement:
+			return nodes, nil
+		}
+	}
+}
+
+func nodeAttributes(attributes []xml.Attr) Attributes {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	return attributes
+}
+
+// MarshalXML encodes the element, or the character data if the node is no element. Namespaces are written with the
+// prefixes declared by the attributes of the element and its ancestors, or declared as default namespace.
+func (n Node) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
+	if !n.IsElement() {
+		return encoder.EncodeToken(xml.CharData(n.Text))
+	}
+
+	return writeElement(encoder, n.Name, n.Attributes, n.Children)
+}
+
+// rawElement is an element with pre-rendered attribute names and content.
+type rawElement struct {
+	XMLName    xml.Name
+	Attributes []xml.Attr `xml:",any,attr"`
+	Content    string     `xml:",innerxml"`
+}
+
+// writeElement encodes an element with the attributes and children, which are rendered without the encoder to keep
+// their namespace prefixes.
+func writeElement(encoder *xml.Encoder, name string, attributes Attributes, children []Node) error {
+	scope := newNamespaceScope()
+	if root, ok := encoderScopes.Load(encoder); ok {
+		scope = root.(*namespaceScope).child()
+	}
+
+	rendered := scope.attributes(attributes)
+	// The namespaces of the children are relative to the element.
+	scope.defaultSpace = ""
+
+	var content strings.Builder
+	for _, child := range children {
+		writeNode(&content, child, scope)
+	}
+
+	return encoder.Encode(rawElement{XMLName: xml.Name{Local: name}, Attributes: rendered, Content: content.String()})
+}
+
+// namespaceScope tracks the namespace declarations in scope while writing nodes.
+type namespaceScope struct {
+	prefixes     map[string]string
+	defaultSpace string
+	generated    int
+}
+
+func newNamespaceScope() *namespaceScope {
+	return &namespaceScope{prefixes: map[string]string{}}
+}
+
+func (s *namespaceScope) child() *namespaceScope {
+	prefixes := make(map[string]string, len(s.prefixes))
+	for space, prefix := range s.prefixes {
+		prefixes[space] = prefix
+	}
+
+	return &namespaceScope{prefixes: prefixes, defaultSpace: s.defaultSpace, generated: s.generated}
+}
+
+// attributes declares the namespaces of the attributes in the scope and returns the attributes with rendered names.
+func (s *namespaceScope) attributes(attributes Attributes) []xml.Attr {
+	rendered := make([]xml.Attr, 0, len(attributes))
+
+	for _, attribute := range attributes {
+		switch {
+		case attribute.Name.Space == "" && attribute.Name.Local == "xmlns":
+			s.defaultSpace = attribute.Value
+		case attribute.Name.Space == "xmlns":
+			s.prefixes[attribute.Value] = attribute.Name.Local
+		}
+	}
+
+	for _, attribute := range attributes {
+		name := attribute.Name.Local
+
+		switch space := attribute.Name.Space; space {
+		case "":
+		case "xmlns":
+			name = "xmlns:" + name
+		case xmlNamespace:
+			name = "xml:" + name
+		default:
+			prefix, ok := s.prefixes[space]
+			if !ok {
+				prefix = s.newPrefix(space)
+				s.prefixes[space] = prefix
+				rendered = append(rendered, xml.Attr{Name: xml.Name{Local: "xmlns:" + prefix}, Value: space})
+			}
+
+			name = prefix + ":" + name
+		}
+
+		rendered = append(rendered, xml.Attr{Name: xml.Name{Local: name}, Value: attribute.Value})
+	}
+
+	return rendered
+}
+
+// newPrefix returns the well-known prefix of the namespace if it is not in use, or a generated prefix.
+func (s *namespaceScope) newPrefix(space string) string {
+	used := make(map[string]bool, len(s.prefixes))
+	for _, prefix := range s.prefixes {
+		used[prefix] = true
+	}
+
+	if prefix, ok := wellKnownPrefixes[space]; ok && !used[prefix] {
+		return prefix
+	}
+
+	for {
+		s.generated++
+
+		if prefix := "ns" + strconv.Itoa(s.generated); !used[prefix] {
+			return prefix
+		}
+	}
+}
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+
+func writeNode(builder *strings.Builder, node Node, parent *namespaceScope) {
+	if !node.IsElement() {
+		if node.CData {
+			builder.WriteString("<![CDATA[" + strings.ReplaceAll(node.Text, "]]>", "]]]]><![CDATA[>") + "]]>")
+		} else {
+			builder.WriteString(textEscaper.Replace(node.Text))
+		}
+
+		return
+	}
+
+	scope := parent.child()
+	attributes := scope.attributes(node.Attributes)
+	name := node.Name
+
+	if node.Namespace != scope.defaultSpace {
+		if prefix, ok := scope.prefixes[node.Namespace]; ok {
+			name = prefix + ":" + name
+		} else {
+			scope.defaultSpace = node.Namespace
+			attributes = append([]xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: node.Namespace}}, attributes...)
+		}
+	}
+
+	builder.WriteString("<" + name)
+
+	for _, attribute := range attributes {
+		builder.WriteString(" " + attribute.Name.Local + `="`)
+		_ = xml.EscapeText(builder, []byte(attribute.Value))
+		builder.WriteString(`"`)
+	}
+
+	if len(node.Children) == 0 {
+		builder.WriteString("/>")
+		return
+	}
+
+	builder.WriteString(">")
+
+	for _, child := range node.Children {
+		writeNode(builder, child, scope)
+	}
+
+	builder.WriteString("</" + name + ">")
+}
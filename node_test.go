@@ -0,0 +1,275 @@
+package vast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"io"
+	"strings"
+	"testing"
+)
+
+const nodeVAST = `<VAST version="4.2" xmlns="http://www.iab.com/VAST">
+  <Ad>
+    <InLine>
+      <Extensions>
+        <Extension type="example" xmlns:ex="https://example.com/ns">
+          <ex:Skin ex:color="red">
+            <ex:Image><![CDATA[https://example.com/skin.png?a=1&b=2]]></ex:Image>
+          </ex:Skin>
+          <Items count="2">
+            <Item>a &amp; b</Item>
+            <Item><![CDATA[c]]></Item>
+          </Items>
+          <Other xmlns="https://example.com/other"><Child/></Other>
+          <Attributed xmlns:at="https://example.com/at" at:value="1" xml:lang="en"/>
+        </Extension>
+      </Extensions>
+    </InLine>
+  </Ad>
+</VAST>`
+
+func readNodeVAST(t *testing.T) *vast.VAST {
+	t.Helper()
+
+	testVAST, err := vast.Read(io.NopCloser(strings.NewReader(nodeVAST)))
+	if err != nil {
+		t.Fatal("unexpected error")
+	}
+
+	return testVAST
+}
+
+func TestExtension_Find(t *testing.T) {
+	extension := &readNodeVAST(t).Ad[0].InLine.Extensions.Extension[0]
+
+	tests := map[string][]string{
+		"Skin":          {"Skin"},
+		"Skin/Image":    {"Image"},
+		"Items/Item":    {"Item", "Item"},
+		"Items/Item[2]": {"Item"},
+		"Items/Item[3]": nil,
+		"*":             {"Skin", "Items", "Other", "Attributed"},
+		"//Item":        {"Item", "Item"},
+		"//*":           {"Skin", "Image", "Items", "Item", "Item", "Other", "Child", "Attributed"},
+		"/Other/Child":  {"Child"},
+		"":              nil,
+		"Items/Item[x]": nil,
+		"Items/Item[0]": nil,
+		"Missing/Item":  nil,
+	}
+
+	for path, expected := range tests {
+		var names []string
+		for _, node := range extension.Find(path) {
+			names = append(names, node.Name)
+		}
+
+		if diff := cmp.Diff(expected, names); diff != "" {
+			t.Errorf("wrong nodes for %q: %s", path, diff)
+		}
+	}
+
+	if content := extension.Find("Items/Item[1]")[0].Content(); content != "a & b" {
+		t.Errorf("wrong content: %q", content)
+	}
+
+	if content := extension.Find("Items")[0].Content(); strings.Join(strings.Fields(content), " ") != "a & b c" {
+		t.Errorf("wrong content: %q", content)
+	}
+
+	if value, ok := extension.Find("Skin")[0].Attribute("color"); !ok || value != "red" {
+		t.Errorf("wrong attribute: %q", value)
+	}
+
+	if _, ok := extension.Find("Skin")[0].Attribute("missing"); ok {
+		t.Error("unexpected attribute")
+	}
+
+	if strings.TrimSpace(extension.Content()) == "" {
+		t.Error("missing content")
+	}
+
+	if nodes := extension.Find("Skin")[0].Find("Image"); len(nodes) != 1 || !nodes[0].Children[0].CData {
+		t.Error("missing CDATA")
+	}
+}
+
+func TestExtension_modify(t *testing.T) {
+	testVAST := readNodeVAST(t)
+	extension := &testVAST.Ad[0].InLine.Extensions.Extension[0]
+
+	extension.Find("Skin")[0].SetAttribute("color", "blue")
+	extension.Find("Items")[0].SetAttribute("total", "3")
+	extension.Find("Skin/Image")[0].Children[0].Text = "https://example.com/blue.png"
+
+	items := extension.Find("Items")[0]
+	items.Children = append(items.Children, vast.Node{Name: "Item", Children: []vast.Node{{Text: "d < e"}}})
+
+	data, err := testVAST.Bytes()
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	for _, expected := range []string{
+		`<ex:Skin ex:color="blue">`,
+		`<ex:Image><![CDATA[https://example.com/blue.png]]></ex:Image>`,
+		`<Items count="2" total="3">`,
+		`<Item>a &amp; b</Item>`,
+		`<Item><![CDATA[c]]></Item>`,
+		`<Item>d &lt; e</Item>`,
+		`<Other xmlns="https://example.com/other"><Child/></Other>`,
+		`<Attributed xmlns:at="https://example.com/at" at:value="1" xml:lang="en"/>`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("missing %s in %s", expected, data)
+		}
+	}
+
+	reread, err := vast.Read(io.NopCloser(strings.NewReader(string(data))))
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if diff := cmp.Diff(testVAST.Ad[0].InLine.Extensions, reread.Ad[0].InLine.Extensions); diff != "" {
+		t.Errorf("wrong extensions: %s", diff)
+	}
+}
+
+func TestNode_xml(t *testing.T) {
+	nodes := []vast.Node{
+		{Name: "Item", Namespace: "https://example.com/ns", Children: []vast.Node{{Text: "x]]>y", CData: true}}},
+		{Name: "Item", Attributes: vast.Attributes{{Name: xml.Name{Space: "https://example.com/attr", Local: "value"}, Value: `"1"`}}},
+		{Text: "\r"},
+	}
+
+	data, err := xml.Marshal(vast.Extension{Nodes: nodes})
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	expected := `<Extension><Item xmlns="https://example.com/ns"><![CDATA[x]]]]><![CDATA[>y]]></Item>` +
+		`<Item xmlns:ns1="https://example.com/attr" ns1:value="&#34;1&#34;"/>&#xD;</Extension>`
+	if string(data) != expected {
+		t.Errorf("wrong XML: %s", data)
+	}
+
+	var extension vast.Extension
+	if err := xml.Unmarshal(data, &extension); err != nil {
+		t.Error("unexpected error")
+	}
+
+	// CDATA sections are only detected by Read.
+	expectedNode := nodes[0]
+	expectedNode.Attributes = vast.Attributes{{Name: xml.Name{Local: "xmlns"}, Value: "https://example.com/ns"}}
+	expectedNode.Children = []vast.Node{{Text: "x]]>y"}}
+
+	if diff := cmp.Diff(expectedNode, extension.Nodes[0]); diff != "" {
+		t.Errorf("wrong node: %s", diff)
+	}
+
+	if err := xml.Unmarshal([]byte(`<Extension><Item>`), &extension); err == nil {
+		t.Error("expected error")
+	}
+
+	if err := xml.Unmarshal([]byte(`<CreativeExtension><Item><Child></Item></CreativeExtension>`), &vast.CreativeExtension{}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestNode_cdata(t *testing.T) {
+	testVAST, err := vast.Read(io.NopCloser(strings.NewReader(`<VAST version="4.2"><Ad><InLine><Extensions><Extension>` +
+		`<A>&amp;&amp;&amp;</A><B><![CDATA[x & y]]></B><C>a &lt;<![CDATA[<b>]]>c</C></Extension></Extensions></InLine></Ad></VAST>`)))
+	if err != nil {
+		t.Fatal("unexpected error")
+	}
+
+	expected := []vast.Node{
+		{Name: "A", Children: []vast.Node{{Text: "&&&"}}},
+		{Name: "B", Children: []vast.Node{{Text: "x & y", CData: true}}},
+		{Name: "C", Children: []vast.Node{{Text: "a <"}, {Text: "<b>", CData: true}, {Text: "c"}}},
+	}
+
+	if diff := cmp.Diff(expected, testVAST.Ad[0].InLine.Extensions.Extension[0].Nodes); diff != "" {
+		t.Errorf("wrong nodes: %s", diff)
+	}
+
+	data, err := xml.Marshal(testVAST.Ad[0].InLine.Extensions.Extension[0])
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if want := `<Extension><A>&amp;&amp;&amp;</A><B><![CDATA[x & y]]></B><C>a &lt;<![CDATA[<b>]]>c</C></Extension>`; string(data) != want {
+		t.Errorf("wrong XML: %s", data)
+	}
+
+	jsonData, err := testVAST.JSON()
+	if err != nil {
+		t.Fatal("unexpected error")
+	}
+
+	fromJSON, err := vast.ReadJSON(io.NopCloser(bytes.NewReader(jsonData)))
+	if err != nil {
+		t.Fatal("unexpected error")
+	}
+
+	if diff := cmp.Diff(expected, fromJSON.Ad[0].InLine.Extensions.Extension[0].Nodes); diff != "" {
+		t.Errorf("wrong nodes after JSON round trip: %s", diff)
+	}
+}
+
+func TestNode_standalone(t *testing.T) {
+	var value struct {
+		XMLName xml.Name  `xml:"Root"`
+		Node    vast.Node `xml:"Node"`
+		Text    vast.Node `xml:"Text"`
+	}
+
+	if err := xml.Unmarshal([]byte(`<Root><Node a="1"><Child>text</Child></Node></Root>`), &value); err != nil {
+		t.Error("unexpected error")
+	}
+
+	if value.Node.Name != "Node" || len(value.Node.Find("Child")) != 1 {
+		t.Errorf("wrong node: %v", value.Node)
+	}
+
+	value.Text = vast.Node{Text: "a & b"}
+
+	data, err := xml.Marshal(value)
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if expected := `<Root><Node a="1"><Child>text</Child></Node>a &amp; b</Root>`; string(data) != expected {
+		t.Errorf("wrong XML: %s", data)
+	}
+}
+
+func TestNode_json(t *testing.T) {
+	extension := readNodeVAST(t).Ad[0].InLine.Extensions.Extension[0]
+
+	data, err := json.Marshal(extension)
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	var got vast.Extension
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Error("unexpected error")
+	}
+
+	if diff := cmp.Diff(extension, got); diff != "" {
+		t.Errorf("wrong extension: %s", diff)
+	}
+}
+
+func TestCreativeExtension_Find(t *testing.T) {
+	extension := &readExtensionVAST(t).Ad[0].InLine.Creatives.Creative[0].CreativeExtensions.CreativeExtension[0]
+
+	nodes := extension.Find("Skin")
+	if len(nodes) != 1 || extension.Content() != "" {
+		t.Errorf("wrong nodes: %v", nodes)
+	}
+}
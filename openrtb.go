@@ -0,0 +1,208 @@
+package vast
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// protocols are the OpenRTB protocol IDs of the VAST versions, without and with wrapper.
+var protocols = map[Version][2]int{
+	"1.0":         {1, 4},
+	VAST20Version: {2, 5},
+	VAST30Version: {3, 6},
+	VAST40Version: {7, 8},
+	VAST41Version: {11, 12},
+	VAST42Version: {13, 14},
+	VAST43Version: {15, 16},
+}
+
+// Protocol returns the OpenRTB protocol ID of the version, e.g. 13 for VAST 4.2 and 14 for VAST 4.2 Wrapper, or 0
+// if the version is unknown.
+func (v Version) Protocol(wrapper bool) int {
+	ids, ok := protocols[Version(strings.TrimSpace(string(v)))]
+	if !ok {
+		return 0
+	}
+
+	if wrapper {
+		return ids[1]
+	}
+
+	return ids[0]
+}
+
+// ProtocolVersion returns the VAST version of an OpenRTB protocol ID and whether it is a wrapper.
+// It returns false if the protocol is not a VAST protocol.
+func ProtocolVersion(protocol int) (Version, bool, bool) {
+	for version, ids := range protocols {
+		if ids[0] == protocol || ids[1] == protocol {
+			return version, ids[1] == protocol, true
+		}
+	}
+
+	return "", false, false
+}
+
+// API framework IDs of OpenRTB.
+const (
+	VPAID1API  = 1
+	VPAID2API  = 2
+	MRAID1API  = 3
+	ORMMAAPI   = 4
+	MRAID2API  = 5
+	MRAID3API  = 6
+	OMID1API   = 7
+	SIMID10API = 8
+	SIMID11API = 9
+)
+
+var apiFrameworks = map[string]int{
+	"vpaid1": VPAID1API, "vpaid10": VPAID1API,
+	"vpaid": VPAID2API, "vpaid2": VPAID2API, "vpaid20": VPAID2API,
+	"mraid": MRAID1API, "mraid1": MRAID1API, "mraid10": MRAID1API,
+	"ormma":  ORMMAAPI,
+	"mraid2": MRAID2API, "mraid20": MRAID2API,
+	"mraid3": MRAID3API, "mraid30": MRAID3API,
+	"omid": OMID1API, "omid1": OMID1API, "omid10": OMID1API,
+	"simid": SIMID10API, "simid1": SIMID10API, "simid10": SIMID10API,
+	"simid11": SIMID11API,
+}
+
+// APIFrameworkID returns the OpenRTB API framework ID of an apiFramework attribute, e.g. 2 for `VPAID` and 7 for
+// `omid`, or 0 if it is unknown. Frameworks without version are assumed to be VPAID 2.0, MRAID 1.0, OMID 1.0 and
+// SIMID 1.0.
+func APIFrameworkID(apiFramework string) int {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" -_.", r) {
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(apiFramework))
+
+	return apiFrameworks[name]
+}
+
+// BidFields are the fields of an OpenRTB bid describing the VAST returned in adm or by the nurl.
+type BidFields struct {
+	// Dur is the longest duration of the linear creatives of InLine ads in seconds, rounded up.
+	Dur int
+	// Protocol is the protocol ID of the version, see Version.Protocol. The wrapper protocol is used if the VAST
+	// contains a Wrapper ad.
+	Protocol int
+	// MIMEs are the types of the media files of InLine ads.
+	MIMEs []string
+	// API are the IDs of the API frameworks of creatives, media files, companions, non-linear ads and verifications.
+	API []int
+}
+
+// BidFields derives the fields of an OpenRTB bid from the VAST.
+func (m *VAST) BidFields() BidFields {
+	var (
+		fields  BidFields
+		wrapper bool
+	)
+
+	addAPI := func(apiFramework string) {
+		if id := APIFrameworkID(apiFramework); id > 0 && !slices.Contains(fields.API, id) {
+			fields.API = append(fields.API, id)
+		}
+	}
+
+	_ = Walk(m, Visitor{
+		Wrapper: func(*Wrapper, Path) error {
+			wrapper = true
+			return nil
+		},
+		InLineCreative: func(creative *InLineCreative, _ Path) error {
+			addAPI(creative.APIFramework)
+			return nil
+		},
+		WrapperCreative: func(creative *WrapperCreative, _ Path) error {
+			addAPI(creative.APIFramework)
+			return nil
+		},
+		LinearInLine: func(linear *LinearInLine, _ Path) error {
+			if duration, err := linear.Duration.Parse(); err == nil {
+				fields.Dur = max(fields.Dur, int(math.Ceil(duration.Seconds())))
+			}
+
+			for _, file := range linear.MediaFiles.InteractiveCreativeFile {
+				addAPI(file.APIFramework)
+			}
+
+			return nil
+		},
+		MediaFile: func(mediaFile *MediaFile, _ Path) error {
+			if mimeType := strings.TrimSpace(mediaFile.Type); mimeType != "" && !slices.Contains(fields.MIMEs, mimeType) {
+				fields.MIMEs = append(fields.MIMEs, mimeType)
+			}
+
+			addAPI(mediaFile.APIFramework)
+
+			return nil
+		},
+		NonLinear: func(nonLinear *NonLinearAdInLine, _ Path) error {
+			addAPI(nonLinear.APIFramework)
+			return nil
+		},
+		Companion: func(companion *CompanionAd, _ Path) error {
+			addAPI(companion.APIFramework)
+			return nil
+		},
+		Verification: func(verification *Verification, _ Path) error {
+			for _, resource := range verification.JavaScriptResource {
+				addAPI(resource.APIFramework)
+			}
+
+			return nil
+		},
+	})
+
+	fields.Protocol = m.Version.Protocol(wrapper)
+	slices.Sort(fields.API)
+
+	return fields
+}
+
+// ADM marshals the VAST without indentation for the adm field of an OpenRTB bid.
+func (m *VAST) ADM() (string, error) {
+	xmlData, err := xml.Marshal(m)
+	if err != nil {
+		return "", errors.Join(ErrMarshalVAST, err)
+	}
+
+	return xml.Header + string(xmlData), nil
+}
+
+// MarshalADM encodes an adm value as JSON string. Unlike json.Marshal, it does not escape `<`, `>` and `&`, which
+// would enlarge VAST documents considerably. The result can be used as json.RawMessage.
+func MarshalADM(adm string) []byte {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(adm)
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n"))
+}
+
+// ReadADM parses the adm field of an OpenRTB bid. Leading white space and byte order marks are ignored, and adm
+// values which were encoded as JSON string twice are decoded.
+func ReadADM(adm string) (*VAST, error) {
+	adm = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(adm), "\uFEFF"))
+
+	if strings.HasPrefix(adm, `"`) {
+		if err := json.Unmarshal([]byte(adm), &adm); err != nil {
+			return nil, errors.Join(ErrReadVAST, err)
+		}
+	}
+
+	return Read(io.NopCloser(strings.NewReader(adm)))
+}
@@ -0,0 +1,131 @@
+package vast_test
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"strings"
+	"testing"
+)
+
+func TestVersion_Protocol(t *testing.T) {
+	tests := []struct {
+		version  vast.Version
+		wrapper  bool
+		protocol int
+	}{
+		{version: vast.VAST20Version, protocol: 2},
+		{version: vast.VAST30Version, wrapper: true, protocol: 6},
+		{version: vast.VAST40Version, protocol: 7},
+		{version: vast.VAST41Version, wrapper: true, protocol: 12},
+		{version: " 4.2 ", protocol: 13},
+		{version: vast.VAST42Version, wrapper: true, protocol: 14},
+		{version: "5.0", protocol: 0},
+	}
+
+	for _, testCase := range tests {
+		if protocol := testCase.version.Protocol(testCase.wrapper); protocol != testCase.protocol {
+			t.Errorf("wrong protocol for %s: %d", testCase.version, protocol)
+		}
+	}
+}
+
+func TestProtocolVersion(t *testing.T) {
+	if version, wrapper, ok := vast.ProtocolVersion(12); !ok || !wrapper || version != vast.VAST41Version {
+		t.Errorf("wrong version: %s", version)
+	}
+
+	if version, wrapper, ok := vast.ProtocolVersion(3); !ok || wrapper || version != vast.VAST30Version {
+		t.Errorf("wrong version: %s", version)
+	}
+
+	if _, _, ok := vast.ProtocolVersion(9); ok {
+		t.Error("unexpected DAAST version")
+	}
+}
+
+func TestAPIFrameworkID(t *testing.T) {
+	tests := map[string]int{
+		"VPAID":     vast.VPAID2API,
+		"vpaid 1.0": vast.VPAID1API,
+		"MRAID-2":   vast.MRAID2API,
+		"omid":      vast.OMID1API,
+		"SIMID":     vast.SIMID10API,
+		"SIMID_1.1": vast.SIMID11API,
+		"flash":     0,
+	}
+
+	for apiFramework, expected := range tests {
+		if id := vast.APIFrameworkID(apiFramework); id != expected {
+			t.Errorf("wrong ID for %q: %d", apiFramework, id)
+		}
+	}
+}
+
+func TestVAST_BidFields(t *testing.T) {
+	testVAST := mustReadFixture(t, "iab/Inline_Linear_Tag-test.xml")
+
+	if diff := cmp.Diff(vast.BidFields{Dur: 16, Protocol: 13, MIMEs: []string{"video/mp4"}}, testVAST.BidFields()); diff != "" {
+		t.Errorf("wrong bid fields: %s", diff)
+	}
+
+	testVAST = trackerVAST()
+	testVAST.Version = vast.VAST41Version
+	inLine := testVAST.Ad[0].InLine
+	inLine.AdVerifications = &vast.AdVerifications{Verification: []vast.Verification{{JavaScriptResource: []vast.JavaScriptResource{{APIFramework: "omid"}}}}}
+	inLine.Creatives.Creative[0].APIFramework = "VPAID"
+	inLine.Creatives.Creative[0].Linear.Duration = "00:00:15.200"
+	inLine.Creatives.Creative[0].Linear.MediaFiles = vast.MediaFiles{
+		MediaFile:               []vast.MediaFile{{Type: "video/mp4"}, {Type: "application/javascript", APIFramework: "VPAID"}, {Type: "video/mp4"}},
+		InteractiveCreativeFile: []vast.InteractiveCreativeFile{{APIFramework: "SIMID"}},
+	}
+	inLine.Creatives.Creative[1].NonLinearAds.NonLinear[0].APIFramework = "MRAID-3"
+	inLine.Creatives.Creative[2].CompanionAds.Companion[0].APIFramework = "mraid"
+	testVAST.Ad[1].Wrapper.Creatives.Creative[0].APIFramework = "ORMMA"
+
+	expected := vast.BidFields{Dur: 16, Protocol: 12, MIMEs: []string{"video/mp4", "application/javascript"}, API: []int{2, 3, 4, 6, 7, 8}}
+
+	if diff := cmp.Diff(expected, testVAST.BidFields()); diff != "" {
+		t.Errorf("wrong bid fields: %s", diff)
+	}
+}
+
+func TestVAST_ADM(t *testing.T) {
+	testVAST := mustReadFixture(t, "iab/Inline_Linear_Tag-test.xml")
+
+	adm, err := testVAST.ADM()
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	indented, _ := testVAST.Bytes()
+	if len(adm) >= len(indented) {
+		t.Error("adm is indented")
+	}
+
+	encoded := vast.MarshalADM(adm)
+	if strings.Contains(string(encoded), `\u003c`) || !strings.HasPrefix(string(encoded), `"<?xml`) {
+		t.Errorf("wrong encoding: %.40s", encoded)
+	}
+
+	for _, value := range []string{adm, "\uFEFF " + adm, string(encoded)} {
+		got, err := vast.ReadADM(value)
+		if err != nil {
+			t.Error("unexpected error")
+		}
+
+		if diff := cmp.Diff(testVAST, got); diff != "" {
+			t.Errorf("wrong VAST: %s", diff)
+		}
+	}
+}
+
+func TestReadADM_error(t *testing.T) {
+	if _, err := vast.ReadADM(`"<VAST`); !errors.Is(err, vast.ErrReadVAST) {
+		t.Error("expected error")
+	}
+
+	if _, err := vast.ReadADM(`<VAST`); !errors.Is(err, vast.ErrUnmarshalVAST) {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,298 @@
+package vast
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// PolicyAction is taken by Policy.Apply if a rule matches.
+type PolicyAction string
+
+const (
+	// RejectAction removes the ad.
+	RejectAction PolicyAction = "reject"
+	// PruneAction removes the creative. If the match is not part of a creative, or if all creatives of an InLine ad
+	// are removed, the ad is removed.
+	PruneAction PolicyAction = "prune"
+)
+
+// PolicyRule matches URLs, advertisers and categories of ads. RejectAction is used if Action is empty.
+//
+// Domains match the hosts of URLs including their subdomains. If Allow is set, the rule matches URLs whose host is
+// not listed instead. URLCategories restricts the URLs the rule applies to, all URLs are checked if it is empty.
+// Advertisers match the Advertiser of InLine ads case-insensitively, or as domain including subdomains.
+// Categories match the Category of InLine ads, see BlockedCategories.
+type PolicyRule struct {
+	ID            string
+	Action        PolicyAction
+	Domains       []string
+	Allow         bool
+	URLCategories []URLCategory
+	Advertisers   []string
+	Categories    []BlockedAdCategories
+}
+
+// BlockedAdCategoriesRule creates a rule rejecting ads with categories blocked by the wrappers.
+func BlockedAdCategoriesRule(wrappers ...*Wrapper) PolicyRule {
+	rule := PolicyRule{ID: "blocked-ad-categories", Action: RejectAction}
+
+	for _, wrapper := range wrappers {
+		rule.Categories = append(rule.Categories, wrapper.BlockedAdCategories...)
+	}
+
+	return rule
+}
+
+// PolicyMatch is a rule matching an element of an ad. Rule is the ID of the rule.
+type PolicyMatch struct {
+	Rule   string
+	Action PolicyAction
+	Path   string
+	Value  string
+}
+
+// Policy evaluates ads against rules.
+type Policy struct {
+	Rules []PolicyRule
+}
+
+// policyMatch is a match with the 1-based positions of its ad and creative, which are 0 if it is not part of one.
+type policyMatch struct {
+	PolicyMatch
+	ad       int
+	creative int
+}
+
+// Evaluate returns the matches of all rules, see URLs for the checked URLs.
+func (p *Policy) Evaluate(m *VAST) []PolicyMatch {
+	return publicMatches(p.evaluate(m))
+}
+
+func (p *Policy) evaluate(m *VAST) []policyMatch {
+	var matches []policyMatch
+
+	report := func(rule PolicyRule, ad, creative int, path, value string) {
+		action := rule.Action
+		if action == "" {
+			action = RejectAction
+		}
+
+		matches = append(matches, policyMatch{
+			PolicyMatch: PolicyMatch{Rule: rule.ID, Action: action, Path: path, Value: value},
+			ad:          ad,
+			creative:    creative,
+		})
+	}
+
+	visitURLs(m, func(u URL, path Path, _ *string) bool {
+		ad, creative := policyTarget(path)
+
+		for _, rule := range p.Rules {
+			if rule.matchesURL(u) {
+				report(rule, ad, creative, u.Path, u.Value)
+			}
+		}
+
+		return true
+	})
+
+	for i, ad := range m.Ad {
+		if ad.InLine == nil {
+			continue
+		}
+
+		path := indexed("/VAST", "Ad", i) + "/InLine"
+
+		for _, rule := range p.Rules {
+			if rule.matchesAdvertiser(ad.InLine.Advertiser) {
+				report(rule, i+1, 0, path+"/Advertiser", ad.InLine.Advertiser)
+			}
+
+			for j, category := range ad.InLine.Category {
+				if rule.matchesCategory(category) {
+					report(rule, i+1, 0, indexed(path, "Category", j), category.Value)
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+func publicMatches(matches []policyMatch) []PolicyMatch {
+	if matches == nil {
+		return nil
+	}
+
+	result := make([]PolicyMatch, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, match.PolicyMatch)
+	}
+
+	return result
+}
+
+// Apply evaluates the rules, removes rejected ads and pruned creatives, and returns the matches.
+// Matches of URLs outside of ads, i.e. the error URIs of the root element, are only reported.
+func (p *Policy) Apply(m *VAST) []PolicyMatch {
+	matches := p.evaluate(m)
+
+	rejected := map[int]bool{}
+	pruned := map[int]map[int]bool{}
+
+	for _, match := range matches {
+		ad, creative := match.ad, match.creative
+
+		switch {
+		case ad == 0:
+			continue
+		case match.Action == PruneAction && creative > 0:
+			if pruned[ad] == nil {
+				pruned[ad] = map[int]bool{}
+			}
+
+			pruned[ad][creative] = true
+		default:
+			rejected[ad] = true
+		}
+	}
+
+	if len(rejected) == 0 && len(pruned) == 0 {
+		return publicMatches(matches)
+	}
+
+	ads := make([]Ad, 0, len(m.Ad))
+
+	for i, ad := range m.Ad {
+		if rejected[i+1] || (pruned[i+1] != nil && !pruneCreatives(&ad, pruned[i+1])) {
+			continue
+		}
+
+		ads = append(ads, ad)
+	}
+
+	m.Ad = ads
+
+	return publicMatches(matches)
+}
+
+// policyTarget returns the 1-based positions of the ad and creative of a walked path, or 0 if the path is not part of
+// one.
+func policyTarget(path Path) (int, int) {
+	var ad, creative int
+
+	for _, element := range path {
+		switch element.Node.(type) {
+		case *Ad:
+			ad = element.Index
+		case *InLineCreative, *WrapperCreative:
+			creative = element.Index
+		}
+	}
+
+	return ad, creative
+}
+
+// pruneCreatives removes the creatives at the 1-based positions. It returns false if the ad is an InLine ad without
+// remaining creatives.
+func pruneCreatives(ad *Ad, positions map[int]bool) bool {
+	if ad.InLine != nil {
+		var creatives []InLineCreative
+
+		for i, creative := range ad.InLine.Creatives.Creative {
+			if !positions[i+1] {
+				creatives = append(creatives, creative)
+			}
+		}
+
+		ad.InLine.Creatives.Creative = creatives
+
+		return len(creatives) > 0
+	}
+
+	if ad.Wrapper != nil && ad.Wrapper.Creatives != nil {
+		var creatives []WrapperCreative
+
+		for i, creative := range ad.Wrapper.Creatives.Creative {
+			if !positions[i+1] {
+				creatives = append(creatives, creative)
+			}
+		}
+
+		ad.Wrapper.Creatives.Creative = creatives
+	}
+
+	return true
+}
+
+func (r *PolicyRule) matchesURL(u URL) bool {
+	if len(r.Domains) == 0 {
+		return false
+	}
+
+	if len(r.URLCategories) > 0 && !slices.Contains(r.URLCategories, u.Category) {
+		return false
+	}
+
+	parsed, err := url.Parse(u.Value)
+	if err != nil || parsed.Hostname() == "" {
+		return false
+	}
+
+	return matchesDomain(parsed.Hostname(), r.Domains) != r.Allow
+}
+
+func (r *PolicyRule) matchesAdvertiser(advertiser string) bool {
+	advertiser = strings.TrimSpace(advertiser)
+	if advertiser == "" {
+		return false
+	}
+
+	return matchesDomain(advertiser, r.Advertisers)
+}
+
+func (r *PolicyRule) matchesCategory(category Category) bool {
+	for _, blocked := range r.Categories {
+		if categoriesOverlap(category, blocked) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesDomain checks whether the host equals one of the domains or is a subdomain of it.
+func matchesDomain(host string, domains []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// categoryCodes splits a comma separated list of category codes.
+func categoryCodes(value string) []string {
+	var codes []string
+
+	for _, code := range strings.Split(value, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+
+	return codes
+}
+
+// normalizeAuthority removes the scheme, `www.` and trailing slashes of an authority URL.
+func normalizeAuthority(authority string) string {
+	authority = strings.ToLower(strings.TrimSpace(authority))
+	authority = strings.TrimPrefix(strings.TrimPrefix(authority, "https://"), "http://")
+
+	return strings.TrimSuffix(strings.TrimPrefix(authority, "www."), "/")
+}
@@ -0,0 +1,107 @@
+package vast_test
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"testing"
+)
+
+func TestPolicy_Evaluate(t *testing.T) {
+	testVAST := mustReadFixture(t, "iab/Category-test.xml")
+	testVAST.Ad[0].InLine.Advertiser = "ads.Brand.example"
+
+	policy := &vast.Policy{Rules: []vast.PolicyRule{
+		{ID: "blocked-advertisers", Advertisers: []string{"brand.example"}},
+		{ID: "media-hosts", Action: vast.PruneAction, Allow: true, Domains: []string{"cdn.example.com"}, URLCategories: []vast.URLCategory{vast.MediaURL}},
+		{ID: "landing-pages", Domains: []string{"iabtechlab.com"}, URLCategories: []vast.URLCategory{vast.ClickURL}},
+		{ID: "food", Categories: []vast.BlockedAdCategories{{Value: "Vegan, Vegetarian", Authority: "http://iabtechlab.com/categoryauthority/"}}},
+		{ID: "other-authority", Categories: []vast.BlockedAdCategories{{Value: "Guitar", Authority: "https://example.com"}}},
+	}}
+
+	creative := "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear"
+	expected := []vast.PolicyMatch{
+		{Rule: "landing-pages", Action: vast.RejectAction, Path: creative + "/VideoClicks/ClickThrough", Value: "https://iabtechlab.com"},
+		{Rule: "media-hosts", Action: vast.PruneAction, Path: creative + "/MediaFiles/MediaFile[1]", Value: "https://iab-publicfiles.s3.amazonaws.com/vast/VAST-4.0-Short-Intro.mp4"},
+		{Rule: "media-hosts", Action: vast.PruneAction, Path: creative + "/MediaFiles/MediaFile[2]", Value: "https://iab-publicfiles.s3.amazonaws.com/vast/VAST-4.0-Short-Intro-mid-resolution.mp4"},
+		{Rule: "media-hosts", Action: vast.PruneAction, Path: creative + "/MediaFiles/MediaFile[3]", Value: "https://iab-publicfiles.s3.amazonaws.com/vast/VAST-4.0-Short-Intro-low-resolution.mp4"},
+		{Rule: "blocked-advertisers", Action: vast.RejectAction, Path: "/VAST/Ad[1]/InLine/Advertiser", Value: "ads.Brand.example"},
+		{Rule: "food", Action: vast.RejectAction, Path: "/VAST/Ad[1]/InLine/Category[3]", Value: "Vegan"},
+	}
+
+	if diff := cmp.Diff(expected, policy.Evaluate(testVAST)); diff != "" {
+		t.Errorf("wrong matches: %s", diff)
+	}
+
+	if diff := cmp.Diff(mustReadFixture(t, "iab/Category-test.xml").Ad[0].InLine.Creatives, testVAST.Ad[0].InLine.Creatives); diff != "" {
+		t.Errorf("wrong VAST: %s", diff)
+	}
+}
+
+func TestPolicy_Apply(t *testing.T) {
+	testVAST := trackerVAST()
+	testVAST.Error = []vast.CData{{Value: "https://blocked.example/noAd"}}
+	testVAST.Ad[0].InLine.Creatives.Creative[2].CompanionAds.Companion[0].CompanionClickTracking = []string{"https://blocked.example/click"}
+	testVAST.Ad = append(testVAST.Ad, vast.Ad{InLine: &vast.InLine{
+		AdDefinitionBase: vast.AdDefinitionBase{Impression: []vast.Impression{{Value: "https://blocked.example/impression"}}},
+	}}, vast.Ad{InLine: &vast.InLine{
+		Creatives: vast.InLineCreatives{Creative: []vast.InLineCreative{{
+			Linear: &vast.LinearInLine{MediaFiles: vast.MediaFiles{MediaFile: []vast.MediaFile{{Value: "https://blocked.example/video.mp4"}}}},
+		}}},
+	}})
+
+	policy := &vast.Policy{Rules: []vast.PolicyRule{
+		{ID: "blocked", Action: vast.PruneAction, Domains: []string{"blocked.example"}},
+	}}
+
+	matches := policy.Apply(testVAST)
+	if len(matches) != 4 {
+		t.Errorf("unexpected matches: %v", matches)
+	}
+
+	if len(testVAST.Ad) != 2 || len(testVAST.Ad[0].InLine.Creatives.Creative) != 2 || len(testVAST.Error) != 1 {
+		t.Errorf("unexpected ads: %v", testVAST.Ad)
+	}
+
+	if matches := policy.Apply(testVAST); len(matches) != 1 {
+		t.Errorf("unexpected matches: %v", matches)
+	}
+}
+
+func TestPolicy_Apply_wrapper(t *testing.T) {
+	testVAST := trackerVAST()
+	testVAST.Ad[1].Wrapper.Creatives.Creative[0].Linear.VideoClicks.ClickTracking[0].Value = "https://blocked.example/click"
+
+	policy := &vast.Policy{Rules: []vast.PolicyRule{
+		{ID: "blocked", Action: vast.PruneAction, Domains: []string{"blocked.example"}},
+	}}
+
+	if matches := policy.Apply(testVAST); len(matches) != 1 {
+		t.Errorf("unexpected matches: %v", matches)
+	}
+
+	if len(testVAST.Ad) != 2 || len(testVAST.Ad[1].Wrapper.Creatives.Creative) != 0 {
+		t.Errorf("unexpected ads: %v", testVAST.Ad)
+	}
+}
+
+func TestBlockedAdCategoriesRule(t *testing.T) {
+	wrapper := mustReadFixture(t, "iab/Wrapper_Tag-test.xml").Ad[0].Wrapper
+	wrapper.BlockedAdCategories = []vast.BlockedAdCategories{{Value: "Guitar", Authority: "https://www.iabtechlab.com/categoryauthority"}}
+
+	testVAST := mustReadFixture(t, "iab/Category-test.xml")
+	testVAST.Ad = append(testVAST.Ad, mustReadFixture(t, "iab/Inline_Simple.xml").Ad...)
+
+	matches := (&vast.Policy{Rules: []vast.PolicyRule{vast.BlockedAdCategoriesRule(wrapper)}}).Apply(testVAST)
+
+	expected := []vast.PolicyMatch{
+		{Rule: "blocked-ad-categories", Action: vast.RejectAction, Path: "/VAST/Ad[1]/InLine/Category[2]", Value: "Guitar"},
+	}
+
+	if diff := cmp.Diff(expected, matches); diff != "" {
+		t.Errorf("wrong matches: %s", diff)
+	}
+
+	if len(testVAST.Ad) != 1 || testVAST.Ad[0].InLine.AdTitle != mustReadFixture(t, "iab/Inline_Simple.xml").Ad[0].InLine.AdTitle {
+		t.Errorf("unexpected ads: %v", testVAST.Ad)
+	}
+}
@@ -0,0 +1,302 @@
+package vast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrFetchVAST     = errors.New("cannot fetch VAST")
+	ErrNoAd          = errors.New("no ad")
+	ErrWrapperLimit  = errors.New("wrapper limit reached")
+	ErrMissingTagURI = errors.New("missing VASTAdTagURI")
+)
+
+// DefaultMaxWrappers is the number of wrappers a Resolver follows by default.
+const DefaultMaxWrappers = 5
+
+// Fetcher retrieves the VAST document referenced by a VASTAdTagURI.
+type Fetcher interface {
+	Fetch(ctx context.Context, uri string) (*VAST, error)
+}
+
+// HTTPFetcher fetches VAST documents via HTTP GET. It uses http.DefaultClient if Client is nil.
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+// Fetch requests the URI and reads the response body.
+func (f *HTTPFetcher) Fetch(ctx context.Context, uri string) (*VAST, error) {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, errors.Join(ErrFetchVAST, err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, errors.Join(ErrFetchVAST, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		return nil, errors.Join(ErrFetchVAST, fmt.Errorf("unexpected status code %d", response.StatusCode))
+	}
+
+	return Read(response.Body)
+}
+
+// Hop is a single request of a wrapper chain.
+type Hop struct {
+	URI      string
+	Duration time.Duration
+	Ad       *Ad
+}
+
+// Resolver follows wrapper chains.
+// MaxWrappers limits the number of wrappers. DefaultMaxWrappers is used if MaxWrappers is 0 or negative.
+type Resolver struct {
+	Fetcher     Fetcher
+	MaxWrappers int
+}
+
+// Resolve follows the VASTAdTagURI of a wrapper ad until it reaches an InLine ad.
+// The first ad of each response is used. The trackers of all wrappers are merged into the returned InLine ad,
+// see InLine.MergeWrapper. The hops are returned even if resolving fails. An InLine ad is returned unchanged.
+// The followAdditionalWrappers attribute is not evaluated, since its default value cannot be distinguished from false.
+func (r *Resolver) Resolve(ctx context.Context, ad *Ad) (*Ad, []Hop, error) {
+	maxWrappers := r.MaxWrappers
+	if maxWrappers <= 0 {
+		maxWrappers = DefaultMaxWrappers
+	}
+
+	var (
+		hops     []Hop
+		wrappers []*Wrapper
+	)
+
+	for ad.Wrapper != nil {
+		if len(wrappers) >= maxWrappers {
+			return nil, hops, ErrWrapperLimit
+		}
+
+		wrappers = append(wrappers, ad.Wrapper)
+
+		uri := strings.TrimSpace(ad.Wrapper.VASTAdTagURI.Value)
+		if uri == "" {
+			return nil, hops, ErrMissingTagURI
+		}
+
+		start := time.Now()
+		response, err := r.Fetcher.Fetch(ctx, uri)
+		hop := Hop{URI: uri, Duration: time.Since(start)}
+
+		if err != nil {
+			return nil, append(hops, hop), err
+		}
+
+		if len(response.Ad) == 0 {
+			return nil, append(hops, hop), ErrNoAd
+		}
+
+		ad = &response.Ad[0]
+		hop.Ad = ad
+		hops = append(hops, hop)
+	}
+
+	if ad.InLine == nil {
+		return nil, hops, ErrNoAd
+	}
+
+	for i := len(wrappers) - 1; i >= 0; i-- {
+		ad.InLine.MergeWrapper(wrappers[i])
+	}
+
+	return ad, hops, nil
+}
+
+// MergeWrapper adds the impressions, error URIs, viewability trackers, verifications, extensions and creative trackers
+// of a wrapper to the InLine ad. Tracking events and clicks of wrapper creatives are added to all creatives of the same
+// kind. Wrapper icons are only added if no icon with the same program is present, so MergeWrapper must be called for
+// the wrapper closest to the InLine ad first, like Resolve does. Wrapper companions with resources are added to the
+// companions of the InLine ad.
+func (i *InLine) MergeWrapper(wrapper *Wrapper) {
+	i.Impression = append(i.Impression, wrapper.Impression...)
+	i.Error = append(i.Error, wrapper.Error...)
+
+	if wrapper.ViewableImpression != nil {
+		if i.ViewableImpression == nil {
+			i.ViewableImpression = &ViewableImpression{}
+		}
+
+		i.ViewableImpression.Viewable = append(i.ViewableImpression.Viewable, wrapper.ViewableImpression.Viewable...)
+		i.ViewableImpression.NotViewable = append(i.ViewableImpression.NotViewable, wrapper.ViewableImpression.NotViewable...)
+		i.ViewableImpression.ViewUndetermined = append(i.ViewableImpression.ViewUndetermined, wrapper.ViewableImpression.ViewUndetermined...)
+	}
+
+	if wrapper.AdVerifications != nil {
+		if i.AdVerifications == nil {
+			i.AdVerifications = &AdVerifications{}
+		}
+
+		i.AdVerifications.Verification = append(i.AdVerifications.Verification, wrapper.AdVerifications.Verification...)
+	}
+
+	if wrapper.Extensions != nil {
+		if i.Extensions == nil {
+			i.Extensions = &Extensions{}
+		}
+
+		i.Extensions.Extension = append(i.Extensions.Extension, wrapper.Extensions.Extension...)
+	}
+
+	if wrapper.Creatives == nil {
+		return
+	}
+
+	for _, wrapperCreative := range wrapper.Creatives.Creative {
+		for j := range i.Creatives.Creative {
+			creative := &i.Creatives.Creative[j]
+
+			if creative.Linear != nil && wrapperCreative.Linear != nil {
+				mergeLinear(creative.Linear, wrapperCreative.Linear)
+			}
+
+			if creative.NonLinearAds != nil && wrapperCreative.NonLinearAds != nil {
+				mergeNonLinearAds(creative.NonLinearAds, wrapperCreative.NonLinearAds)
+			}
+
+			if creative.CompanionAds != nil && wrapperCreative.CompanionAds != nil {
+				mergeCompanionTracking(creative.CompanionAds, wrapperCreative.CompanionAds)
+			}
+		}
+
+		if wrapperCreative.CompanionAds != nil {
+			i.mergeCompanions(wrapperCreative.CompanionAds)
+		}
+	}
+}
+
+func mergeTrackingEvents(trackingEvents **TrackingEvents, wrapperTrackingEvents *TrackingEvents) {
+	if wrapperTrackingEvents == nil || len(wrapperTrackingEvents.Tracking) == 0 {
+		return
+	}
+
+	if *trackingEvents == nil {
+		*trackingEvents = &TrackingEvents{}
+	}
+
+	(*trackingEvents).Tracking = append((*trackingEvents).Tracking, wrapperTrackingEvents.Tracking...)
+}
+
+func mergeLinear(linear *LinearInLine, wrapperLinear *LinearWrapper) {
+	mergeTrackingEvents(&linear.TrackingEvents, wrapperLinear.TrackingEvents)
+
+	if wrapperLinear.Icons != nil {
+		if linear.Icons == nil {
+			linear.Icons = &Icons{}
+		}
+
+		linear.Icons.Icon = mergeIcons(linear.Icons.Icon, wrapperLinear.Icons.Icon)
+	}
+
+	if wrapperLinear.VideoClicks != nil {
+		if linear.VideoClicks == nil {
+			linear.VideoClicks = &VideoClicks{}
+		}
+
+		linear.VideoClicks.ClickTracking = append(linear.VideoClicks.ClickTracking, wrapperLinear.VideoClicks.ClickTracking...)
+		linear.VideoClicks.CustomClick = append(linear.VideoClicks.CustomClick, wrapperLinear.VideoClicks.CustomClick...)
+	}
+}
+
+// mergeIcons adds wrapper icons whose program is not displayed yet. Since wrappers are merged starting with the one
+// closest to the InLine ad, the icon closest to the InLine ad is displayed for each program, and the first one if a
+// wrapper has several icons for the program. Icons without program are always added.
+func mergeIcons(icons, wrapperIcons []Icon) []Icon {
+	programs := map[string]bool{}
+
+	for _, icon := range icons {
+		programs[strings.ToLower(strings.TrimSpace(icon.Program))] = true
+	}
+
+	for _, icon := range wrapperIcons {
+		program := strings.ToLower(strings.TrimSpace(icon.Program))
+		if program != "" && programs[program] {
+			continue
+		}
+
+		icons = append(icons, icon)
+
+		if program != "" {
+			programs[program] = true
+		}
+	}
+
+	return icons
+}
+
+func mergeNonLinearAds(nonLinearAds *NonLinearAds, wrapperNonLinearAds *NonLinearAds) {
+	mergeTrackingEvents(&nonLinearAds.TrackingEvents, wrapperNonLinearAds.TrackingEvents)
+
+	for _, wrapperNonLinear := range wrapperNonLinearAds.NonLinear {
+		for j := range nonLinearAds.NonLinear {
+			nonLinear := &nonLinearAds.NonLinear[j]
+			nonLinear.NonLinearClickTracking = append(nonLinear.NonLinearClickTracking, wrapperNonLinear.NonLinearClickTracking...)
+		}
+	}
+}
+
+func hasResource(companion *CompanionAd) bool {
+	return len(companion.StaticResource) > 0 || len(companion.IFrameResource) > 0 || len(companion.HTMLResource) > 0
+}
+
+// mergeCompanionTracking adds the trackers of wrapper companions without resources to all companions.
+func mergeCompanionTracking(companionAds *CompanionAdsCollection, wrapperCompanionAds *CompanionAdsCollection) {
+	for _, wrapperCompanion := range wrapperCompanionAds.Companion {
+		if hasResource(&wrapperCompanion) {
+			continue
+		}
+
+		for j := range companionAds.Companion {
+			companion := &companionAds.Companion[j]
+			mergeTrackingEvents(&companion.TrackingEvents, wrapperCompanion.TrackingEvents)
+			companion.CompanionClickTracking = append(companion.CompanionClickTracking, wrapperCompanion.CompanionClickTracking...)
+		}
+	}
+}
+
+// mergeCompanions adds wrapper companions with resources to the first creative containing companions,
+// or to a new creative if there is none.
+func (i *InLine) mergeCompanions(wrapperCompanionAds *CompanionAdsCollection) {
+	var companions []CompanionAd
+
+	for _, wrapperCompanion := range wrapperCompanionAds.Companion {
+		if hasResource(&wrapperCompanion) {
+			companions = append(companions, wrapperCompanion)
+		}
+	}
+
+	if len(companions) == 0 {
+		return
+	}
+
+	for j := range i.Creatives.Creative {
+		if companionAds := i.Creatives.Creative[j].CompanionAds; companionAds != nil {
+			companionAds.Companion = append(companionAds.Companion, companions...)
+			return
+		}
+	}
+
+	i.Creatives.Creative = append(i.Creatives.Creative, InLineCreative{
+		CompanionAds: &CompanionAdsCollection{Companion: companions, Required: wrapperCompanionAds.Required},
+	})
+}
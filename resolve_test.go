@@ -0,0 +1,244 @@
+package vast_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+)
+
+const inlineCompanionTagURI = "https://raw.githubusercontent.com/InteractiveAdvertisingBureau/VAST_Samples/master/VAST%204.2%20Samples/Inline_Companion_Tag-test.xml"
+
+type fixtureFetcher map[string]string
+
+func (f fixtureFetcher) Fetch(_ context.Context, uri string) (*vast.VAST, error) {
+	fixture, ok := f[uri]
+	if !ok {
+		return nil, vast.ErrFetchVAST
+	}
+
+	return vast.Read(mustOpenFixture(fixture))
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	wrapper := mustReadFixture(t, "iab/Wrapper_Tag-test.xml")
+	resolver := &vast.Resolver{Fetcher: fixtureFetcher{inlineCompanionTagURI: "iab/Inline_Companion_Tag-test.xml"}}
+
+	ad, hops, err := resolver.Resolve(context.Background(), &wrapper.Ad[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(hops) != 1 || hops[0].URI != inlineCompanionTagURI || hops[0].Ad != ad {
+		t.Errorf("unexpected hops: %v", hops)
+	}
+
+	inLine := ad.InLine
+	if len(inLine.Impression) != 2 || inLine.Impression[1].Value != "https://example.com/track/impression" {
+		t.Errorf("unexpected impressions: %v", inLine.Impression)
+	}
+
+	if len(inLine.Error) != 2 {
+		t.Errorf("unexpected errors: %v", inLine.Error)
+	}
+
+	if companions := inLine.Creatives.Creative[0].CompanionAds.Companion; len(companions) != 2 || companions[1].AdSlotID != "3214" {
+		t.Errorf("unexpected companions: %v", companions)
+	}
+}
+
+func TestResolver_Resolve_inLine(t *testing.T) {
+	inLine := mustReadFixture(t, "iab/Inline_Simple.xml")
+
+	ad, hops, err := (&vast.Resolver{}).Resolve(context.Background(), &inLine.Ad[0])
+	if err != nil || ad != &inLine.Ad[0] || len(hops) != 0 {
+		t.Errorf("unexpected result: %v %v %v", ad, hops, err)
+	}
+}
+
+func TestResolver_Resolve_errors(t *testing.T) {
+	loop := fixtureFetcher{inlineCompanionTagURI: "iab/Wrapper_Tag-test.xml"}
+	wrapper := mustReadFixture(t, "iab/Wrapper_Tag-test.xml").Ad[0]
+
+	missingTagURI := mustReadFixture(t, "iab/Wrapper_Tag-test.xml").Ad[0]
+	missingTagURI.Wrapper.VASTAdTagURI.Value = ""
+
+	testCases := []struct {
+		name     string
+		resolver *vast.Resolver
+		ad       vast.Ad
+		hops     int
+		err      error
+	}{
+		{"fetch", &vast.Resolver{Fetcher: fixtureFetcher{}}, wrapper, 1, vast.ErrFetchVAST},
+		{"limit", &vast.Resolver{Fetcher: loop, MaxWrappers: 3}, wrapper, 3, vast.ErrWrapperLimit},
+		{"negative limit", &vast.Resolver{Fetcher: loop, MaxWrappers: -1}, wrapper, vast.DefaultMaxWrappers, vast.ErrWrapperLimit},
+		{"missing tag URI", &vast.Resolver{Fetcher: loop}, missingTagURI, 0, vast.ErrMissingTagURI},
+		{"empty response", &vast.Resolver{Fetcher: emptyFetcher{}}, wrapper, 1, vast.ErrNoAd},
+		{"no ad", &vast.Resolver{Fetcher: fixtureFetcher{}}, vast.Ad{}, 0, vast.ErrNoAd},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, hops, err := testCase.resolver.Resolve(context.Background(), &testCase.ad)
+			if !errors.Is(err, testCase.err) || len(hops) != testCase.hops {
+				t.Errorf("unexpected result: %v %v", hops, err)
+			}
+		})
+	}
+}
+
+type emptyFetcher struct{}
+
+func (emptyFetcher) Fetch(context.Context, string) (*vast.VAST, error) {
+	return vast.New(), nil
+}
+
+func TestInLine_MergeWrapper(t *testing.T) {
+	inLine := mustReadFixture(t, "iab/Inline_Non-Linear_Tag-test.xml").Ad[0].InLine
+	wrapper := mustReadFixture(t, "iab/Viewable_Impression-test.xml").Ad[0].Wrapper
+	wrapper.AdVerifications = &vast.AdVerifications{Verification: []vast.Verification{{Vendor: "example"}}}
+	wrapper.Extensions = &vast.Extensions{Extension: []vast.Extension{{Type: "example"}}}
+	wrapper.Creatives.Creative = append(wrapper.Creatives.Creative,
+		vast.WrapperCreative{
+			NonLinearAds: &vast.NonLinearAds{
+				TrackingEvents: &vast.TrackingEvents{Tracking: []vast.Tracking{{Event: "start", Value: "https://example.com/start"}}},
+				NonLinear:      []vast.NonLinearAdInLine{{NonLinearClickTracking: []vast.CData{{Value: "https://example.com/click"}}}},
+			},
+			Linear: &vast.LinearWrapper{
+				LinearBase:  vast.LinearBase{Icons: &vast.Icons{Icon: []vast.Icon{{Program: "AdChoices"}}}},
+				VideoClicks: &vast.VideoClicks{ClickTracking: []vast.CData{{Value: "https://example.com/click"}}},
+			},
+			CompanionAds: &vast.CompanionAdsCollection{Companion: []vast.CompanionAd{
+				{CompanionClickTracking: []string{"https://example.com/companion-click"}},
+				{StaticResource: []vast.StaticResource{{Value: "https://example.com/companion.png"}}},
+			}},
+		},
+	)
+
+	verifications, extensions := countVerificationsAndExtensions(inLine)
+	inLine.MergeWrapper(wrapper)
+
+	if inLine.ViewableImpression == nil || len(inLine.ViewableImpression.Viewable) != 1 {
+		t.Errorf("unexpected viewable impression: %v", inLine.ViewableImpression)
+	}
+
+	if gotVerifications, gotExtensions := countVerificationsAndExtensions(inLine); gotVerifications != verifications+1 || gotExtensions != extensions+1 {
+		t.Error("unexpected verifications or extensions")
+	}
+
+	nonLinearAds := inLine.Creatives.Creative[0].NonLinearAds
+	if tracking := nonLinearAds.TrackingEvents.Tracking; tracking[len(tracking)-1].Value != "https://example.com/start" {
+		t.Errorf("unexpected tracking: %v", tracking)
+	}
+
+	if clickTracking := nonLinearAds.NonLinear[0].NonLinearClickTracking; clickTracking[len(clickTracking)-1].Value != "https://example.com/click" {
+		t.Errorf("unexpected click tracking: %v", clickTracking)
+	}
+
+	last := inLine.Creatives.Creative[len(inLine.Creatives.Creative)-1]
+	if last.CompanionAds == nil || len(last.CompanionAds.Companion) != 1 {
+		t.Errorf("unexpected companions: %v", last.CompanionAds)
+	}
+}
+
+func countVerificationsAndExtensions(inLine *vast.InLine) (verifications, extensions int) {
+	if inLine.AdVerifications != nil {
+		verifications = len(inLine.AdVerifications.Verification)
+	}
+
+	if inLine.Extensions != nil {
+		extensions = len(inLine.Extensions.Extension)
+	}
+
+	return verifications, extensions
+}
+
+func TestInLine_MergeWrapper_linear(t *testing.T) {
+	inLine := mustReadFixture(t, "iab/Inline_Companion_Tag-test.xml").Ad[0].InLine
+	wrapper := mustReadFixture(t, "iab/Viewable_Impression-test.xml").Ad[0].Wrapper
+	wrapper.Creatives.Creative[0].Linear.Icons = &vast.Icons{Icon: []vast.Icon{{Program: "AdChoices"}}}
+	wrapper.Creatives.Creative[0].Linear.VideoClicks = &vast.VideoClicks{ClickTracking: []vast.CData{{Value: "https://example.com/click"}}}
+	wrapper.Creatives.Creative = append(wrapper.Creatives.Creative, vast.WrapperCreative{
+		CompanionAds: &vast.CompanionAdsCollection{Companion: []vast.CompanionAd{
+			{CompanionClickTracking: []string{"https://example.com/companion-click"}},
+		}},
+	})
+
+	inLine.MergeWrapper(wrapper)
+
+	linear := inLine.Creatives.Creative[1].Linear
+	if len(linear.TrackingEvents.Tracking) != 8 || len(linear.Icons.Icon) != 1 || len(linear.VideoClicks.ClickTracking) != 1 {
+		t.Errorf("unexpected linear: %v", linear)
+	}
+
+	if clickTracking := inLine.Creatives.Creative[0].CompanionAds.Companion[0].CompanionClickTracking; len(clickTracking) != 1 {
+		t.Errorf("unexpected click tracking: %v", clickTracking)
+	}
+}
+
+func TestInLine_MergeWrapper_icons(t *testing.T) {
+	inLine := &vast.InLine{Creatives: vast.InLineCreatives{Creative: []vast.InLineCreative{{Linear: &vast.LinearInLine{
+		LinearBase: vast.LinearBase{Icons: &vast.Icons{Icon: []vast.Icon{{Program: "AdChoices", APIFramework: "inline"}}}},
+	}}}}}
+
+	wrapperIcons := [][]vast.Icon{
+		{{Program: "adchoices", APIFramework: "first"}, {Program: "Logo", PXRatio: 1}, {Program: "Logo", PXRatio: 2}, {APIFramework: "first"}},
+		{{Program: "Logo", APIFramework: "second"}, {Program: "Disclosure"}, {APIFramework: "second"}},
+	}
+
+	for _, icons := range wrapperIcons {
+		inLine.MergeWrapper(&vast.Wrapper{Creatives: &vast.Creatives{Creative: []vast.WrapperCreative{
+			{Linear: &vast.LinearWrapper{LinearBase: vast.LinearBase{Icons: &vast.Icons{Icon: icons}}}},
+		}}})
+	}
+
+	got := inLine.Creatives.Creative[0].Linear.Icons.Icon
+	want := []vast.Icon{
+		{Program: "AdChoices", APIFramework: "inline"},
+		{Program: "Logo", PXRatio: 1},
+		{APIFramework: "first"},
+		{Program: "Disclosure"},
+		{APIFramework: "second"},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong icons: %s", diff)
+	}
+}
+
+func TestHTTPFetcher_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vast.xml" {
+			http.NotFound(w, r)
+			return
+		}
+
+		data, _ := os.ReadFile(path.Join("testdata", "iab", "Inline_Simple.xml"))
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	fetcher := &vast.HTTPFetcher{}
+
+	if result, err := fetcher.Fetch(context.Background(), server.URL+"/vast.xml"); err != nil || len(result.Ad) != 1 {
+		t.Errorf("unexpected result: %v %v", result, err)
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/missing.xml"); !errors.Is(err, vast.ErrFetchVAST) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), "http://[::1]:namedport"); !errors.Is(err, vast.ErrFetchVAST) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := (&vast.HTTPFetcher{Client: server.Client()}).Fetch(context.Background(), "http://127.0.0.1:0/"); !errors.Is(err, vast.ErrFetchVAST) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+package vast
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// FindingType classifies the findings of a Scanner.
+type FindingType string
+
+const (
+	InlineScriptFinding     FindingType = "inline-script"
+	DisallowedDomainFinding FindingType = "disallowed-domain"
+	DocumentWriteFinding    FindingType = "document-write"
+	AutoRedirectFinding     FindingType = "auto-redirect"
+	InsecureResourceFinding FindingType = "insecure-resource"
+)
+
+// Finding is a potentially malicious construct in a creative payload.
+// Path is the path of the element containing the payload, e.g. `/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/AdParameters`.
+type Finding struct {
+	Type    FindingType
+	Path    string
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s [%s]", f.Path, f.Message, f.Type)
+}
+
+// CreativeFindings are the findings of a creative. ID is the id attribute of the creative.
+type CreativeFindings struct {
+	Path     string
+	ID       string
+	Findings []Finding
+}
+
+// Scanner checks the HTMLResource, IFrameResource and AdParameters payloads of companions, non-linear ads, icons and
+// linear creatives. AllowedDomains restricts the domains of referenced URLs, including their subdomains.
+// All domains are allowed if AllowedDomains is empty.
+type Scanner struct {
+	AllowedDomains []string
+}
+
+var (
+	documentWritePattern = regexp.MustCompile(`\bdocument\s*\.\s*write(ln)?\s*\(`)
+	redirectPattern      = regexp.MustCompile(`\blocation(\s*\.\s*href)?\s*=[^=]|\blocation\s*\.\s*(assign|replace)\s*\(`)
+	refreshURLPattern    = regexp.MustCompile(`(?i)url\s*=\s*['"]?([^'"\s]+)`)
+)
+
+// navigationTags are elements whose URL attributes are opened on user interaction instead of being loaded.
+var navigationTags = map[string]bool{"a": true, "area": true, "form": true}
+
+// urlAttributes are attributes containing URLs.
+var urlAttributes = map[string]bool{"src": true, "href": true, "data": true, "action": true, "formaction": true, "poster": true}
+
+// Scan returns the findings of all creatives which have findings.
+func (s *Scanner) Scan(m *VAST) []CreativeFindings {
+	var results []CreativeFindings
+
+	collect := func(id string, path Path, findings []Finding) {
+		if len(findings) > 0 {
+			results = append(results, CreativeFindings{Path: path.String(), ID: id, Findings: findings})
+		}
+	}
+
+	_ = Walk(m, Visitor{
+		InLineCreative: func(creative *InLineCreative, path Path) error {
+			c := creativeScanner{scanner: s, path: path.String()}
+
+			if creative.Linear != nil {
+				c.adParameters(c.path+"/Linear", creative.Linear.AdParameters)
+				c.icons(c.path+"/Linear", creative.Linear.Icons)
+			}
+
+			c.nonLinearAds(creative.NonLinearAds)
+			c.companionAds(creative.CompanionAds)
+			collect(creative.ID, path, c.findings)
+
+			return ErrSkipSubtree
+		},
+		WrapperCreative: func(creative *WrapperCreative, path Path) error {
+			c := creativeScanner{scanner: s, path: path.String()}
+
+			if creative.Linear != nil {
+				c.icons(c.path+"/Linear", creative.Linear.Icons)
+			}
+
+			c.nonLinearAds(creative.NonLinearAds)
+			c.companionAds(creative.CompanionAds)
+			collect(creative.ID, path, c.findings)
+
+			return ErrSkipSubtree
+		},
+	})
+
+	return results
+}
+
+func (s *Scanner) allowed(host string) bool {
+	return len(s.AllowedDomains) == 0 || matchesDomain(host, s.AllowedDomains)
+}
+
+type creativeScanner struct {
+	scanner  *Scanner
+	path     string
+	findings []Finding
+}
+
+func (c *creativeScanner) report(findingType FindingType, path, format string, args ...any) {
+	c.findings = append(c.findings, Finding{Type: findingType, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *creativeScanner) icons(path string, icons *Icons) {
+	if icons == nil {
+		return
+	}
+
+	for i := range icons.Icon {
+		c.resources(indexed(path+"/Icons", "Icon", i), icons.Icon[i].HTMLResource, icons.Icon[i].IFrameResource)
+	}
+}
+
+func (c *creativeScanner) nonLinearAds(nonLinearAds *NonLinearAds) {
+	if nonLinearAds == nil {
+		return
+	}
+
+	for i := range nonLinearAds.NonLinear {
+		nonLinear := &nonLinearAds.NonLinear[i]
+		path := indexed(c.path+"/NonLinearAds", "NonLinear", i)
+		c.resources(path, nonLinear.HTMLResource, nonLinear.IFrameResource)
+		c.adParameters(path, nonLinear.AdParameters)
+	}
+}
+
+func (c *creativeScanner) companionAds(companionAds *CompanionAdsCollection) {
+	if companionAds == nil {
+		return
+	}
+
+	for i := range companionAds.Companion {
+		companion := &companionAds.Companion[i]
+		path := indexed(c.path+"/CompanionAds", "Companion", i)
+		c.resources(path, companion.HTMLResource, companion.IFrameResource)
+		c.adParameters(path, companion.AdParameters)
+	}
+}
+
+func (c *creativeScanner) adParameters(path string, adParameters *AdParameters) {
+	if adParameters != nil {
+		c.html(path+"/AdParameters", adParameters.Value)
+	}
+}
+
+func (c *creativeScanner) resources(path string, htmlResources, iFrameResources []CData) {
+	for i, resource := range htmlResources {
+		c.html(indexed(path, "HTMLResource", i), resource.Value)
+	}
+
+	for i, resource := range iFrameResources {
+		c.url(indexed(path, "IFrameResource", i), strings.TrimSpace(resource.Value), true)
+	}
+}
+
+// url checks the scheme and domain of a URL. Only subresources, which are loaded without user interaction, must be
+// secure.
+func (c *creativeScanner) url(path, value string, subresource bool) {
+	u, err := url.Parse(value)
+	if err != nil {
+		return
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "javascript":
+		c.report(InlineScriptFinding, path, "javascript URL %q", value)
+		return
+	case "http":
+		if subresource {
+			c.report(InsecureResourceFinding, path, "insecure subresource %q", value)
+		}
+	}
+
+	if u.Host != "" && !c.scanner.allowed(u.Hostname()) {
+		c.report(DisallowedDomainFinding, path, "disallowed domain %q in URL %q", u.Hostname(), value)
+	}
+}
+
+func (c *creativeScanner) script(path, code string) {
+	if documentWritePattern.MatchString(code) {
+		c.report(DocumentWriteFinding, path, "script calls document.write")
+	}
+
+	if redirectPattern.MatchString(code) {
+		c.report(AutoRedirectFinding, path, "script changes the location")
+	}
+}
+
+func (c *creativeScanner) html(path, payload string) {
+	document, err := html.Parse(strings.NewReader(payload))
+	if err != nil {
+		return
+	}
+
+	for node := range document.Descendants() {
+		if node.Type != html.ElementNode {
+			continue
+		}
+
+		c.element(path, node)
+	}
+}
+
+func (c *creativeScanner) element(path string, node *html.Node) {
+	for _, attribute := range node.Attr {
+		name := strings.ToLower(attribute.Key)
+
+		switch {
+		case strings.HasPrefix(name, "on"):
+			c.report(InlineScriptFinding, path, "inline event handler %s on <%s>", name, node.Data)
+			c.script(path, attribute.Val)
+		case urlAttributes[name]:
+			c.url(path, strings.TrimSpace(attribute.Val), !navigationTags[node.Data])
+		}
+	}
+
+	switch node.Data {
+	case "script":
+		var code strings.Builder
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.TextNode {
+				code.WriteString(child.Data)
+			}
+		}
+
+		if strings.TrimSpace(code.String()) != "" {
+			c.report(InlineScriptFinding, path, "inline script")
+			c.script(path, code.String())
+		}
+	case "meta":
+		if !strings.EqualFold(attributeValue(node, "http-equiv"), "refresh") {
+			return
+		}
+
+		c.report(AutoRedirectFinding, path, "meta refresh")
+
+		if match := refreshURLPattern.FindStringSubmatch(attributeValue(node, "content")); match != nil {
+			c.url(path, match[1], false)
+		}
+	}
+}
+
+func attributeValue(node *html.Node, name string) string {
+	for _, attribute := range node.Attr {
+		if strings.EqualFold(attribute.Key, name) {
+			return attribute.Val
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,96 @@
+package vast_test
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"testing"
+)
+
+func TestScanner_Scan(t *testing.T) {
+	testVAST := trackerVAST()
+
+	inLine := testVAST.Ad[0].InLine
+	inLine.Creatives.Creative[0].ID = "linear"
+	inLine.Creatives.Creative[0].Linear.AdParameters = &vast.AdParameters{Value: `{"url": "https://ads.example.com/"}`}
+	inLine.Creatives.Creative[0].Linear.Icons.Icon[0].IFrameResource = []vast.CData{{Value: "http://cdn.example.com/icon.html"}}
+	inLine.Creatives.Creative[1].NonLinearAds.NonLinear[0].AdParameters = &vast.AdParameters{
+		Value: `<meta http-equiv="refresh" content="0; url=https://evil.example.net/">`,
+	}
+	inLine.Creatives.Creative[2].CompanionAds.Companion[0].HTMLResource = []vast.CData{{Value: `<div onclick="top.location.href = 'https://ads.example.com/'">
+<script src="https://cdn.example.com/ad.js"></script>
+<script>document.write('<img src="http://tracker.example.org/pixel.gif">');</script>
+<a href="http://ads.example.com/landing"><img src="http://cdn.example.com/banner.png"></a>
+<a href="javascript:void(0)">close</a>
+</div>`}}
+
+	testVAST.Ad[1].Wrapper.Creatives.Creative[0].CompanionAds = &vast.CompanionAdsCollection{Companion: []vast.CompanionAd{{
+		HTMLResource: []vast.CData{{Value: `<img src="https://cdn.example.com/banner.png">`}},
+	}}}
+
+	scanner := &vast.Scanner{AllowedDomains: []string{"Example.com"}}
+	companionPath := "/VAST/Ad[1]/InLine/Creatives/Creative[3]/CompanionAds/Companion[1]/HTMLResource[1]"
+
+	expected := []vast.CreativeFindings{
+		{
+			Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]",
+			ID:   "linear",
+			Findings: []vast.Finding{
+				{Type: vast.InsecureResourceFinding, Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/Icons/Icon[1]/IFrameResource[1]", Message: `insecure subresource "http://cdn.example.com/icon.html"`},
+			},
+		},
+		{
+			Path: "/VAST/Ad[1]/InLine/Creatives/Creative[2]",
+			Findings: []vast.Finding{
+				{Type: vast.AutoRedirectFinding, Path: "/VAST/Ad[1]/InLine/Creatives/Creative[2]/NonLinearAds/NonLinear[1]/AdParameters", Message: "meta refresh"},
+				{Type: vast.DisallowedDomainFinding, Path: "/VAST/Ad[1]/InLine/Creatives/Creative[2]/NonLinearAds/NonLinear[1]/AdParameters", Message: `disallowed domain "evil.example.net" in URL "https://evil.example.net/"`},
+			},
+		},
+		{
+			Path: "/VAST/Ad[1]/InLine/Creatives/Creative[3]",
+			Findings: []vast.Finding{
+				{Type: vast.InlineScriptFinding, Path: companionPath, Message: "inline event handler onclick on <div>"},
+				{Type: vast.AutoRedirectFinding, Path: companionPath, Message: "script changes the location"},
+				{Type: vast.InlineScriptFinding, Path: companionPath, Message: "inline script"},
+				{Type: vast.DocumentWriteFinding, Path: companionPath, Message: "script calls document.write"},
+				{Type: vast.InsecureResourceFinding, Path: companionPath, Message: `insecure subresource "http://cdn.example.com/banner.png"`},
+				{Type: vast.InlineScriptFinding, Path: companionPath, Message: `javascript URL "javascript:void(0)"`},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expected, scanner.Scan(testVAST)); diff != "" {
+		t.Errorf("wrong findings: %s", diff)
+	}
+}
+
+func TestScanner_Scan_domains(t *testing.T) {
+	testVAST := trackerVAST()
+	testVAST.Ad[0].InLine.Creatives.Creative[0].Linear.Icons.Icon[0].HTMLResource = []vast.CData{{Value: `<img src="https://cdn.example.org/icon.png">`}}
+	testVAST.Ad[1].Wrapper.Creatives.Creative[0].Linear.Icons = &vast.Icons{Icon: []vast.Icon{{
+		HTMLResource: []vast.CData{{Value: `<iframe src="https://example.org/"></iframe>`}},
+	}}}
+
+	if findings := (&vast.Scanner{}).Scan(testVAST); len(findings) != 0 {
+		t.Errorf("unexpected findings: %v", findings)
+	}
+
+	findings := (&vast.Scanner{AllowedDomains: []string{"example.com"}}).Scan(testVAST)
+	if len(findings) != 2 || findings[1].Path != "/VAST/Ad[2]/Wrapper/Creatives/Creative[1]" ||
+		findings[1].Findings[0].String() != `/VAST/Ad[2]/Wrapper/Creatives/Creative[1]/Linear/Icons/Icon[1]/HTMLResource[1]: disallowed domain "example.org" in URL "https://example.org/" [disallowed-domain]` {
+		t.Errorf("unexpected findings: %v", findings)
+	}
+}
+
+func TestScanner_Scan_fixtures(t *testing.T) {
+	for _, testCase := range iabFixtures {
+		t.Run(testCase, func(t *testing.T) {
+			for _, creative := range (&vast.Scanner{}).Scan(mustReadFixture(t, testCase)) {
+				for _, finding := range creative.Findings {
+					if finding.Type != vast.InsecureResourceFinding && finding.Type != vast.InlineScriptFinding {
+						t.Errorf("unexpected finding: %v", finding)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package vast
+
+import (
+	"reflect"
+	"strings"
+)
+
+// TrimSpace removes leading and trailing white space from all character data and CDATA values,
+// since encoding/xml merges CDATA sections with surrounding white space.
+// Attributes and raw inner XML are left untouched.
+func (m *VAST) TrimSpace() {
+	trimSpace(reflect.ValueOf(m).Elem())
+}
+
+func trimSpace(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			trimSpace(value.Elem())
+		}
+	case reflect.Slice:
+		for i := range value.Len() {
+			trimSpace(value.Index(i))
+		}
+	case reflect.Struct:
+		for i := range value.NumField() {
+			_, options, _ := strings.Cut(value.Type().Field(i).Tag.Get("xml"), ",")
+			if strings.Contains(options, "attr") || strings.Contains(options, "innerxml") || strings.Contains(options, "any") {
+				continue
+			}
+
+			trimSpace(value.Field(i))
+		}
+	case reflect.String:
+		value.SetString(strings.TrimSpace(value.String()))
+	}
+}
@@ -0,0 +1,50 @@
+package vast_test
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestVAST_TrimSpace(t *testing.T) {
+	testVAST, err := vast.Read(io.NopCloser(strings.NewReader(`<VAST version="4.2">
+  <Ad id=" 1 ">
+    <InLine>
+      <AdTitle>
+        Title
+      </AdTitle>
+      <Impression>
+        <![CDATA[https://example.com/impression]]>
+      </Impression>
+      <Extensions>
+        <Extension type="example"> <Example/> </Extension>
+      </Extensions>
+    </InLine>
+  </Ad>
+</VAST>`)))
+	if err != nil {
+		t.Fatal("unexpected error")
+	}
+
+	testVAST.TrimSpace()
+
+	want := &vast.VAST{
+		Version: "4.2",
+		Ad: []vast.Ad{{
+			ID: " 1 ",
+			InLine: &vast.InLine{
+				AdDefinitionBase: vast.AdDefinitionBase{
+					Impression: []vast.Impression{{Value: "https://example.com/impression"}},
+					Extensions: &vast.Extensions{Extension: []vast.Extension{{Type: "example", Nodes: []vast.Node{{Text: " "}, {Name: "Example"}, {Text: " "}}}}},
+				},
+				AdTitle: "Title",
+			},
+		}},
+	}
+
+	if diff := cmp.Diff(want, testVAST); diff != "" {
+		t.Errorf("wrong VAST: %s", diff)
+	}
+}
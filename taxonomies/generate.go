@@ -0,0 +1,64 @@
+//go:build ignore
+
+// Generate downloads the official taxonomy files of the IAB Tech Lab which are embedded by the vast package.
+//
+// Run it with `go generate` in the root of the module.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const baseURL = "https://raw.githubusercontent.com/InteractiveAdvertisingBureau/Taxonomies/main/"
+
+// files maps the embedded files to their paths in the repository of the IAB Tech Lab.
+var files = map[string]string{
+	"content-2.0.tsv":    "Content Taxonomies/Content Taxonomy 2.0.tsv",
+	"content-2.1.tsv":    "Content Taxonomies/Content Taxonomy 2.1.tsv",
+	"content-2.2.tsv":    "Content Taxonomies/Content Taxonomy 2.2.tsv",
+	"content-3.0.tsv":    "Content Taxonomies/Content Taxonomy 3.0.tsv",
+	"ad-product-1.0.tsv": "Ad Product Taxonomies/Ad Product Taxonomy 1.0.tsv",
+	"ad-product-2.0.tsv": "Ad Product Taxonomies/Ad Product Taxonomy 2.0.tsv",
+}
+
+func main() {
+	client := &http.Client{Timeout: time.Minute}
+
+	for name, path := range files {
+		if err := download(client, baseURL+(&url.URL{Path: path}).EscapedPath(), filepath.Join("taxonomies", name)); err != nil {
+			log.Fatalf("%s: %v", name, err)
+		}
+	}
+}
+
+func download(client *http.Client, uri, name string) error {
+	response, err := client.Get(uri)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Contains(data, []byte("Unique ID")) {
+		return fmt.Errorf("missing header in %s", uri)
+	}
+
+	return os.WriteFile(name, data, 0o644)
+}
@@ -0,0 +1,384 @@
+package vast
+
+import (
+	"embed"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrReadTaxonomy = errors.New("cannot read taxonomy")
+
+// Taxonomy is an IAB Tech Lab taxonomy. The values are the category taxonomy IDs (cattax) of AdCOM and OpenRTB.
+type Taxonomy int
+
+const (
+	UnknownTaxonomy     Taxonomy = 0
+	ContentTaxonomy10   Taxonomy = 1
+	ContentTaxonomy20   Taxonomy = 2
+	AdProductTaxonomy10 Taxonomy = 3
+	AudienceTaxonomy11  Taxonomy = 4
+	ContentTaxonomy21   Taxonomy = 5
+	ContentTaxonomy22   Taxonomy = 6
+	ContentTaxonomy30   Taxonomy = 7
+	AdProductTaxonomy20 Taxonomy = 8
+)
+
+var taxonomyNames = map[Taxonomy]string{
+	ContentTaxonomy10:   "Content Taxonomy 1.0",
+	ContentTaxonomy20:   "Content Taxonomy 2.0",
+	AdProductTaxonomy10: "Ad Product Taxonomy 1.0",
+	AudienceTaxonomy11:  "Audience Taxonomy 1.1",
+	ContentTaxonomy21:   "Content Taxonomy 2.1",
+	ContentTaxonomy22:   "Content Taxonomy 2.2",
+	ContentTaxonomy30:   "Content Taxonomy 3.0",
+	AdProductTaxonomy20: "Ad Product Taxonomy 2.0",
+}
+
+func (t Taxonomy) String() string {
+	if name, ok := taxonomyNames[t]; ok {
+		return name
+	}
+
+	return "unknown taxonomy"
+}
+
+var (
+	authorityVersionPattern = regexp.MustCompile(`(\d+)(?:\.(\d+))?`)
+	content10CodePattern    = regexp.MustCompile(`(?i)^IAB\d+(-\d+)?$`)
+)
+
+// ParseTaxonomyAuthority recognizes the taxonomy of a Category or BlockedAdCategories authority. The authority can be
+// a cattax ID, e.g. `7`, or a URL or name mentioning the taxonomy and its version, e.g. the URL of the official TSV
+// file `https://github.com/InteractiveAdvertisingBureau/Taxonomies/blob/main/Content%20Taxonomies/Content%20Taxonomy%203.0.tsv`.
+// Content taxonomies without version are assumed to be Content Taxonomy 1.0.
+func ParseTaxonomyAuthority(authority string) Taxonomy {
+	authority = strings.TrimSpace(authority)
+
+	if id, err := strconv.Atoi(authority); err == nil {
+		if _, ok := taxonomyNames[Taxonomy(id)]; ok {
+			return Taxonomy(id)
+		}
+
+		return UnknownTaxonomy
+	}
+
+	if unescaped, err := url.PathUnescape(authority); err == nil {
+		authority = unescaped
+	}
+
+	authority = strings.NewReplacer("-", " ", "_", " ", "+", " ").Replace(strings.ToLower(authority))
+
+	// Only the part after the taxonomy name contains its version, the host name may contain digits.
+	version := ""
+	if index := strings.LastIndex(authority, "taxonom"); index >= 0 {
+		if match := authorityVersionPattern.FindStringSubmatch(authority[index:]); match != nil {
+			version = strings.TrimSuffix(match[1]+"."+match[2], ".")
+		}
+	}
+
+	switch {
+	case strings.Contains(authority, "ad product") || strings.Contains(authority, "adproduct"):
+		if version == "2" || version == "2.0" {
+			return AdProductTaxonomy20
+		}
+
+		return AdProductTaxonomy10
+	case strings.Contains(authority, "audience"):
+		return AudienceTaxonomy11
+	case strings.Contains(authority, "content") || strings.Contains(authority, "taxonom"):
+		switch version {
+		case "", "1", "1.0":
+			return ContentTaxonomy10
+		case "2", "2.0":
+			return ContentTaxonomy20
+		case "2.1":
+			return ContentTaxonomy21
+		case "2.2":
+			return ContentTaxonomy22
+		case "3", "3.0":
+			return ContentTaxonomy30
+		}
+	}
+
+	return UnknownTaxonomy
+}
+
+// categoryTaxonomy returns the taxonomy of a category code. Codes like `IAB1-1` without recognized authority are
+// Content Taxonomy 1.0 codes.
+func categoryTaxonomy(authority, code string) Taxonomy {
+	if taxonomy := ParseTaxonomyAuthority(authority); taxonomy != UnknownTaxonomy {
+		return taxonomy
+	}
+
+	if content10CodePattern.MatchString(code) {
+		return ContentTaxonomy10
+	}
+
+	return UnknownTaxonomy
+}
+
+// Taxonomy returns the taxonomy of the category, see ParseTaxonomyAuthority.
+func (c Category) Taxonomy() Taxonomy {
+	return categoryTaxonomy(c.Authority, strings.TrimSpace(c.Value))
+}
+
+// Name returns the name of the category in its taxonomy, or an empty string if the code is unknown.
+func (c Category) Name() string {
+	return LookupTaxonomy(c.Taxonomy()).Name(strings.TrimSpace(c.Value))
+}
+
+// TaxonomyEntry is a category of a taxonomy. Parent is the ID of the parent category, it is empty for tier 1 categories.
+type TaxonomyEntry struct {
+	ID     string
+	Parent string
+	Name   string
+}
+
+// TaxonomyTable is a taxonomy with its categories. The nil table contains no categories.
+type TaxonomyTable struct {
+	entries map[string]TaxonomyEntry
+}
+
+// ParseTaxonomy reads a taxonomy from a TSV file as published by the IAB Tech Lab. Rows before the header containing
+// the columns `Unique ID`, `Parent` or `Parent ID`, and `Name` are skipped.
+func ParseTaxonomy(r io.Reader) (*TaxonomyTable, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	columns := map[string]int{}
+	table := &TaxonomyTable{entries: map[string]TaxonomyEntry{}}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Join(ErrReadTaxonomy, err)
+		}
+
+		if len(columns) == 0 {
+			for i, column := range record {
+				switch strings.ToLower(strings.TrimSpace(column)) {
+				case "unique id":
+					columns["id"] = i
+				case "parent", "parent id":
+					columns["parent"] = i
+				case "name":
+					columns["name"] = i
+				}
+			}
+
+			if len(columns) < 3 {
+				clear(columns)
+			}
+
+			continue
+		}
+
+		field := func(column string) string {
+			if i := columns[column]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+
+			return ""
+		}
+
+		if id := field("id"); id != "" {
+			table.entries[id] = TaxonomyEntry{ID: id, Parent: field("parent"), Name: field("name")}
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.Join(ErrReadTaxonomy, errors.New("missing header"))
+	}
+
+	return table, nil
+}
+
+// Lookup returns the category with the ID.
+func (t *TaxonomyTable) Lookup(id string) (TaxonomyEntry, bool) {
+	if t == nil {
+		return TaxonomyEntry{}, false
+	}
+
+	entry, ok := t.entries[id]
+
+	return entry, ok
+}
+
+// Name returns the name of the category with the ID, or an empty string if it is unknown.
+func (t *TaxonomyTable) Name(id string) string {
+	entry, _ := t.Lookup(id)
+	return entry.Name
+}
+
+// Ancestors returns the IDs of the parent categories of a category, starting with its parent.
+func (t *TaxonomyTable) Ancestors(id string) []string {
+	var ancestors []string
+
+	for entry, ok := t.Lookup(id); ok && entry.Parent != "" && len(ancestors) < len(t.entries); entry, ok = t.Lookup(entry.Parent) {
+		ancestors = append(ancestors, entry.Parent)
+	}
+
+	return ancestors
+}
+
+// Overlaps checks whether two categories are equal or one is an ancestor of the other.
+func (t *TaxonomyTable) Overlaps(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+
+	for _, ancestor := range t.Ancestors(a) {
+		if strings.EqualFold(ancestor, b) {
+			return true
+		}
+	}
+
+	for _, ancestor := range t.Ancestors(b) {
+		if strings.EqualFold(ancestor, a) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//go:generate go run taxonomies/generate.go
+
+//go:embed taxonomies/*.tsv
+var taxonomyFiles embed.FS
+
+// embeddedTaxonomies are the candidate files of the embedded taxonomies, the first existing file is used. The files
+// of the Content Taxonomy 2.x and 3.0 and of the Ad Product Taxonomies are downloaded by `go generate`. Until then,
+// only tier 1 of the Content Taxonomy 2.x and 3.0 is available, the IDs of these categories are the same in all
+// versions.
+var embeddedTaxonomies = map[Taxonomy][]string{
+	ContentTaxonomy10:   {"taxonomies/content-1.0.tsv"},
+	ContentTaxonomy20:   {"taxonomies/content-2.0.tsv", "taxonomies/content-tier1.tsv"},
+	ContentTaxonomy21:   {"taxonomies/content-2.1.tsv", "taxonomies/content-tier1.tsv"},
+	ContentTaxonomy22:   {"taxonomies/content-2.2.tsv", "taxonomies/content-tier1.tsv"},
+	ContentTaxonomy30:   {"taxonomies/content-3.0.tsv", "taxonomies/content-tier1.tsv"},
+	AdProductTaxonomy10: {"taxonomies/ad-product-1.0.tsv"},
+	AdProductTaxonomy20: {"taxonomies/ad-product-2.0.tsv"},
+}
+
+var taxonomyRegistry = struct {
+	sync.RWMutex
+	tables map[Taxonomy]*TaxonomyTable
+}{tables: map[Taxonomy]*TaxonomyTable{}}
+
+// RegisterTaxonomy sets the table of a taxonomy, e.g. to use a newer version of an official table. The embedded table
+// is used again if the table is nil.
+func RegisterTaxonomy(taxonomy Taxonomy, table *TaxonomyTable) {
+	taxonomyRegistry.Lock()
+	defer taxonomyRegistry.Unlock()
+
+	if table == nil {
+		delete(taxonomyRegistry.tables, taxonomy)
+		return
+	}
+
+	taxonomyRegistry.tables[taxonomy] = table
+}
+
+// LookupTaxonomy returns the registered or embedded table of a taxonomy, or nil if there is none.
+// Only the Content Taxonomy 1.0 and tier 1 of the Content Taxonomy 2.x and 3.0 are embedded unless the official tables
+// have been written by `go generate`, see embeddedTaxonomies.
+func LookupTaxonomy(taxonomy Taxonomy) *TaxonomyTable {
+	taxonomyRegistry.RLock()
+	table, ok := taxonomyRegistry.tables[taxonomy]
+	taxonomyRegistry.RUnlock()
+
+	if ok {
+		return table
+	}
+
+	table = readEmbeddedTaxonomy(embeddedTaxonomies[taxonomy])
+	if table == nil {
+		return nil
+	}
+
+	taxonomyRegistry.Lock()
+	defer taxonomyRegistry.Unlock()
+
+	if registered, ok := taxonomyRegistry.tables[taxonomy]; ok {
+		return registered
+	}
+
+	taxonomyRegistry.tables[taxonomy] = table
+
+	return table
+}
+
+// readEmbeddedTaxonomy parses the first of the files which exists.
+func readEmbeddedTaxonomy(names []string) *TaxonomyTable {
+	for _, name := range names {
+		file, err := taxonomyFiles.Open(name)
+		if err != nil {
+			continue
+		}
+
+		table, err := ParseTaxonomy(file)
+		_ = file.Close()
+
+		if err == nil {
+			return table
+		}
+	}
+
+	return nil
+}
+
+// BlockedCategories returns the categories which are blocked, i.e. which are equal to, a parent of, or a child of a
+// blocked category of the same taxonomy. Categories of unknown taxonomies are compared by code, and by authority if
+// both have one. Values can contain comma separated lists of codes.
+func BlockedCategories(categories []Category, blocked []BlockedAdCategories) []Category {
+	var result []Category
+
+	for _, category := range categories {
+		for _, b := range blocked {
+			if categoriesOverlap(category, b) {
+				result = append(result, category)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+func categoriesOverlap(category Category, blocked BlockedAdCategories) bool {
+	for _, code := range categoryCodes(category.Value) {
+		taxonomy := categoryTaxonomy(category.Authority, code)
+
+		for _, blockedCode := range categoryCodes(blocked.Value) {
+			blockedTaxonomy := categoryTaxonomy(blocked.Authority, blockedCode)
+
+			switch {
+			case taxonomy != UnknownTaxonomy && taxonomy == blockedTaxonomy:
+				if LookupTaxonomy(taxonomy).Overlaps(code, blockedCode) {
+					return true
+				}
+			case taxonomy != UnknownTaxonomy && blockedTaxonomy != UnknownTaxonomy:
+				continue
+			case category.Authority != "" && blocked.Authority != "" && normalizeAuthority(category.Authority) != normalizeAuthority(blocked.Authority):
+				continue
+			case strings.EqualFold(code, blockedCode):
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,222 @@
+package vast_test
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestParseTaxonomyAuthority(t *testing.T) {
+	tests := map[string]vast.Taxonomy{
+		"7":   vast.ContentTaxonomy30,
+		"9":   vast.UnknownTaxonomy,
+		" 1 ": vast.ContentTaxonomy10,
+		"https://github.com/InteractiveAdvertisingBureau/Taxonomies/blob/main/Content%20Taxonomies/Content%20Taxonomy%203.0.tsv": vast.ContentTaxonomy30,
+		"IAB Content Taxonomy 2.2":                     vast.ContentTaxonomy22,
+		"iab-content-taxonomy-v2.1":                    vast.ContentTaxonomy21,
+		"https://iabtechlab.com/taxonomy2":             vast.ContentTaxonomy20,
+		"IAB Content Category Taxonomy":                vast.ContentTaxonomy10,
+		"Ad Product Taxonomy 2.0":                      vast.AdProductTaxonomy20,
+		"ad_product_taxonomy":                          vast.AdProductTaxonomy10,
+		"Audience Taxonomy 1.1":                        vast.AudienceTaxonomy11,
+		"Content Taxonomy 4.0":                         vast.UnknownTaxonomy,
+		"https://www.iabtechlab.com/categoryauthority": vast.UnknownTaxonomy,
+	}
+
+	for authority, expected := range tests {
+		if taxonomy := vast.ParseTaxonomyAuthority(authority); taxonomy != expected {
+			t.Errorf("wrong taxonomy for %q: %s", authority, taxonomy)
+		}
+	}
+}
+
+func TestTaxonomy_String(t *testing.T) {
+	if vast.ContentTaxonomy30.String() != "Content Taxonomy 3.0" || vast.UnknownTaxonomy.String() != "unknown taxonomy" {
+		t.Error("wrong name")
+	}
+}
+
+func TestCategory_Name(t *testing.T) {
+	tests := map[vast.Category]string{
+		{Value: "IAB8-16"}: "Vegan",
+		{Value: " IAB19 ", Authority: "https://www.iabtechlab.com/categoryauthority"}: "Technology & Computing",
+		{Value: "596", Authority: "Content Taxonomy 3.0"}:                             "Technology & Computing",
+		{Value: "597", Authority: "Content Taxonomy 3.0"}:                             "",
+		{Value: "1", Authority: "Ad Product Taxonomy 2.0"}:                            "",
+		{Value: "Vegan"}: "",
+	}
+
+	for category, expected := range tests {
+		if name := category.Name(); name != expected {
+			t.Errorf("wrong name for %v: %q", category, name)
+		}
+	}
+}
+
+func TestParseTaxonomy(t *testing.T) {
+	table, err := vast.ParseTaxonomy(strings.NewReader("Relational ID System\t\tTiered Categories\n" +
+		"Unique ID\tParent ID\tName\tTier 1\tTier 2\n" +
+		"1000\t\tApparel\tApparel\n" +
+		"1001\t1000\tShoes\tApparel\tShoes\n" +
+		"1002\t1001\tSneakers\n" +
+		"1003\n"))
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	if entry, ok := table.Lookup("1001"); !ok || entry != (vast.TaxonomyEntry{ID: "1001", Parent: "1000", Name: "Shoes"}) {
+		t.Errorf("wrong entry: %v", entry)
+	}
+
+	if entry, _ := table.Lookup("1003"); entry.Name != "" {
+		t.Errorf("wrong entry: %v", entry)
+	}
+
+	if diff := cmp.Diff([]string{"1001", "1000"}, table.Ancestors("1002")); diff != "" {
+		t.Errorf("wrong ancestors: %s", diff)
+	}
+
+	if !table.Overlaps("1000", "1002") || !table.Overlaps("1002", "1000") || table.Overlaps("1002", "1003") {
+		t.Error("wrong overlap")
+	}
+}
+
+func TestParseTaxonomy_error(t *testing.T) {
+	if _, err := vast.ParseTaxonomy(strings.NewReader("ID\tName\n1\tApparel\n")); !errors.Is(err, vast.ErrReadTaxonomy) {
+		t.Error("expected error")
+	}
+
+	if _, err := vast.ParseTaxonomy(iotest.ErrReader(errors.New("read error"))); !errors.Is(err, vast.ErrReadTaxonomy) {
+		t.Error("expected error")
+	}
+}
+
+func TestRegisterTaxonomy(t *testing.T) {
+	table, err := vast.ParseTaxonomy(strings.NewReader("Unique ID\tParent\tName\n1000\t\tApparel\n1001\t1000\tShoes\n"))
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	vast.RegisterTaxonomy(vast.AdProductTaxonomy20, table)
+	defer vast.RegisterTaxonomy(vast.AdProductTaxonomy20, nil)
+
+	if vast.LookupTaxonomy(vast.AdProductTaxonomy20) != table {
+		t.Error("taxonomy not registered")
+	}
+
+	blocked := []vast.BlockedAdCategories{{Value: "1000", Authority: "8"}}
+	categories := []vast.Category{{Value: "1001", Authority: "Ad Product Taxonomy 2.0"}, {Value: "1001", Authority: "Ad Product Taxonomy 1.0"}}
+
+	if diff := cmp.Diff(categories[:1], vast.BlockedCategories(categories, blocked)); diff != "" {
+		t.Errorf("wrong categories: %s", diff)
+	}
+}
+
+func TestBlockedCategories(t *testing.T) {
+	categories := []vast.Category{
+		{Value: "IAB8-16"},
+		{Value: "IAB17", Authority: "Content Taxonomy 1.0"},
+		{Value: "IAB1-6"},
+		{Value: "483", Authority: "Content Taxonomy 3.0"},
+		{Value: "Guitar", Authority: "https://www.iabtechlab.com/categoryauthority"},
+		{Value: "Vegan", Authority: "https://example.com"},
+		{Value: "IAB3, IAB20-3"},
+	}
+	blocked := []vast.BlockedAdCategories{
+		{Value: "IAB8"},
+		{Value: "IAB17-12", Authority: "1"},
+		{Value: "483", Authority: "Content Taxonomy 2.2"},
+		{Value: "guitar", Authority: "http://iabtechlab.com/categoryauthority/"},
+		{Value: "Vegan", Authority: "https://example.org"},
+		{Value: "IAB20"},
+	}
+
+	expected := []vast.Category{categories[0], categories[1], categories[4], categories[6]}
+
+	if diff := cmp.Diff(expected, vast.BlockedCategories(categories, blocked)); diff != "" {
+		t.Errorf("wrong categories: %s", diff)
+	}
+}
+
+func TestBlockedCategories_tiers(t *testing.T) {
+	table, err := vast.ParseTaxonomy(strings.NewReader("Unique ID\tParent\tName\n483\t\tSports\n484\t483\tAmerican Football\n" +
+		"1001\t484\tCollege Football\n552\t\tStyle & Fashion\n553\t552\tBeauty\n"))
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	vast.RegisterTaxonomy(vast.ContentTaxonomy30, table)
+	defer vast.RegisterTaxonomy(vast.ContentTaxonomy30, nil)
+
+	categories := []vast.Category{
+		{Value: "484", Authority: "7"},
+		{Value: "1001", Authority: "Content Taxonomy 3.0"},
+		{Value: "553", Authority: "7"},
+		{Value: "484", Authority: "Content Taxonomy 2.2"},
+	}
+	blocked := []vast.BlockedAdCategories{{Value: "483", Authority: "7"}}
+
+	if diff := cmp.Diff(categories[:2], vast.BlockedCategories(categories, blocked)); diff != "" {
+		t.Errorf("wrong categories: %s", diff)
+	}
+
+	blocked = []vast.BlockedAdCategories{{Value: "1001", Authority: "7"}}
+
+	if diff := cmp.Diff(categories[:2], vast.BlockedCategories(categories, blocked)); diff != "" {
+		t.Errorf("wrong categories: %s", diff)
+	}
+
+	if name := (vast.Category{Value: "1001", Authority: "7"}).Name(); name != "College Football" {
+		t.Errorf("wrong name: %s", name)
+	}
+}
+
+func TestRegisterTaxonomy_nil(t *testing.T) {
+	vast.RegisterTaxonomy(vast.ContentTaxonomy30, &vast.TaxonomyTable{})
+	vast.RegisterTaxonomy(vast.ContentTaxonomy30, nil)
+
+	if name := vast.LookupTaxonomy(vast.ContentTaxonomy30).Name("483"); name != "Sports" {
+		t.Errorf("wrong name: %s", name)
+	}
+}
+
+// skipUngenerated skips a test if the table of a taxonomy has not been written by `go generate`.
+func skipUngenerated(t *testing.T, name string) {
+	t.Helper()
+
+	if _, err := os.Stat("taxonomies/" + name); err != nil {
+		t.Skipf("%s not generated", name)
+	}
+}
+
+func TestCategory_Name_contentTaxonomy30(t *testing.T) {
+	skipUngenerated(t, "content-3.0.tsv")
+
+	category := vast.Category{Value: "484", Authority: "7"}
+	if name := category.Name(); name != "American Football" {
+		t.Errorf("wrong name: %s", name)
+	}
+
+	blocked := []vast.BlockedAdCategories{{Value: "483", Authority: "Content Taxonomy 3.0"}}
+	if diff := cmp.Diff([]vast.Category{category}, vast.BlockedCategories([]vast.Category{category}, blocked)); diff != "" {
+		t.Errorf("wrong categories: %s", diff)
+	}
+}
+
+func TestCategory_Name_adProductTaxonomy20(t *testing.T) {
+	skipUngenerated(t, "ad-product-2.0.tsv")
+
+	category := vast.Category{Value: "1002", Authority: "8"}
+	if category.Name() == "" {
+		t.Error("missing name")
+	}
+
+	blocked := []vast.BlockedAdCategories{{Value: "1001", Authority: "Ad Product Taxonomy 2.0"}}
+	if diff := cmp.Diff([]vast.Category{category}, vast.BlockedCategories([]vast.Category{category}, blocked)); diff != "" {
+		t.Errorf("wrong categories: %s", diff)
+	}
+}
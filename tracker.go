@@ -0,0 +1,301 @@
+package vast
+
+// TrackerType is the element containing a tracker URL.
+type TrackerType string
+
+const (
+	ImpressionTracker       TrackerType = "Impression"
+	ErrorTracker            TrackerType = "Error"
+	ViewableTracker         TrackerType = "Viewable"
+	NotViewableTracker      TrackerType = "NotViewable"
+	ViewUndeterminedTracker TrackerType = "ViewUndetermined"
+	TrackingEventTracker    TrackerType = "Tracking"
+	ClickTracker            TrackerType = "ClickTracking"
+	IconViewTracker         TrackerType = "IconViewTracking"
+)
+
+// CreativeType is the kind of creative a tracker belongs to. Trackers of the ad itself have no creative type.
+type CreativeType string
+
+const (
+	NoCreative        CreativeType = ""
+	LinearCreative    CreativeType = "Linear"
+	NonLinearCreative CreativeType = "NonLinear"
+	CompanionCreative CreativeType = "Companion"
+	IconCreative      CreativeType = "Icon"
+)
+
+// Tracker is a tracking URL. Event and Offset are only used by tracking events.
+type Tracker struct {
+	Type         TrackerType
+	CreativeType CreativeType
+	Event        Event
+	Offset       Offset
+	URL          string
+}
+
+// TrackerFunc returns the replacement of a tracker, or false to drop it.
+type TrackerFunc func(Tracker) (Tracker, bool)
+
+// RewriteTrackers calls fn for every tracker of the VAST, including the error URIs of the root element.
+// The URL of the returned tracker replaces the URL, and for tracking events also the event and offset.
+// Type and CreativeType of the returned tracker are ignored.
+func (m *VAST) RewriteTrackers(fn TrackerFunc) {
+	cdataTrackers{&m.Error}.rewrite(Tracker{Type: ErrorTracker}, fn)
+
+	eachTrackerList(m.Ad, func(TrackerType, CreativeType) bool { return false }, func(trackerType TrackerType, creativeType CreativeType, list trackerList) {
+		list.rewrite(Tracker{Type: trackerType, CreativeType: creativeType}, fn)
+	})
+}
+
+// InjectTrackers adds the trackers to every InLine and Wrapper ad, or to every creative of the tracker's creative type.
+// Missing elements like ViewableImpression or VideoClicks are created. If the VAST contains no ads, error trackers
+// without creative type are added to the root element. Trackers of types which are not supported by their creative type,
+// e.g. tracking events of icons, are ignored.
+func (m *VAST) InjectTrackers(trackers ...Tracker) {
+	matches := func(tracker Tracker, trackerType TrackerType, creativeType CreativeType) bool {
+		return tracker.Type == trackerType && tracker.CreativeType == creativeType
+	}
+
+	if len(m.Ad) == 0 {
+		for _, tracker := range trackers {
+			if matches(tracker, ErrorTracker, NoCreative) {
+				cdataTrackers{&m.Error}.add(tracker)
+			}
+		}
+
+		return
+	}
+
+	create := func(trackerType TrackerType, creativeType CreativeType) bool {
+		for _, tracker := range trackers {
+			if matches(tracker, trackerType, creativeType) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	eachTrackerList(m.Ad, create, func(trackerType TrackerType, creativeType CreativeType, list trackerList) {
+		for _, tracker := range trackers {
+			if matches(tracker, trackerType, creativeType) {
+				list.add(tracker)
+			}
+		}
+	})
+}
+
+// trackerList is a list of tracker URLs in the VAST tree.
+type trackerList interface {
+	rewrite(tracker Tracker, fn TrackerFunc)
+	add(tracker Tracker)
+}
+
+func rewriteValues[T any](values []T, tracker Tracker, url func(*T) *string, fn TrackerFunc) []T {
+	kept := values[:0]
+
+	for _, value := range values {
+		tracker.URL = *url(&value)
+
+		if rewritten, ok := fn(tracker); ok {
+			*url(&value) = rewritten.URL
+			kept = append(kept, value)
+		}
+	}
+
+	return kept
+}
+
+type cdataTrackers struct {
+	values *[]CData
+}
+
+func (l cdataTrackers) rewrite(tracker Tracker, fn TrackerFunc) {
+	*l.values = rewriteValues(*l.values, tracker, func(value *CData) *string { return &value.Value }, fn)
+}
+
+func (l cdataTrackers) add(tracker Tracker) {
+	*l.values = append(*l.values, CData{Value: tracker.URL})
+}
+
+type impressionTrackers struct {
+	values *[]Impression
+}
+
+func (l impressionTrackers) rewrite(tracker Tracker, fn TrackerFunc) {
+	*l.values = rewriteValues(*l.values, tracker, func(value *Impression) *string { return &value.Value }, fn)
+}
+
+func (l impressionTrackers) add(tracker Tracker) {
+	*l.values = append(*l.values, Impression{Value: tracker.URL})
+}
+
+type stringTrackers struct {
+	values *[]string
+}
+
+func (l stringTrackers) rewrite(tracker Tracker, fn TrackerFunc) {
+	*l.values = rewriteValues(*l.values, tracker, func(value *string) *string { return value }, fn)
+}
+
+func (l stringTrackers) add(tracker Tracker) {
+	*l.values = append(*l.values, tracker.URL)
+}
+
+type trackingTrackers struct {
+	trackingEvents **TrackingEvents
+}
+
+func (l trackingTrackers) rewrite(tracker Tracker, fn TrackerFunc) {
+	if *l.trackingEvents == nil {
+		return
+	}
+
+	kept := (*l.trackingEvents).Tracking[:0]
+
+	for _, tracking := range (*l.trackingEvents).Tracking {
+		tracker.Event = Event(tracking.Event)
+		tracker.Offset = tracking.Offset
+		tracker.URL = tracking.Value
+
+		if rewritten, ok := fn(tracker); ok {
+			kept = append(kept, Tracking{Value: rewritten.URL, Event: string(rewritten.Event), Offset: rewritten.Offset})
+		}
+	}
+
+	if len(kept) == 0 {
+		*l.trackingEvents = nil
+		return
+	}
+
+	(*l.trackingEvents).Tracking = kept
+}
+
+func (l trackingTrackers) add(tracker Tracker) {
+	if *l.trackingEvents == nil {
+		*l.trackingEvents = &TrackingEvents{}
+	}
+
+	(*l.trackingEvents).Tracking = append((*l.trackingEvents).Tracking, Tracking{
+		Value:  tracker.URL,
+		Event:  string(tracker.Event),
+		Offset: tracker.Offset,
+	})
+}
+
+// trackerLists visits the tracker lists of ads. Missing parent elements of a list are created if create returns true
+// for the tracker and creative type of the list.
+type trackerLists struct {
+	create func(TrackerType, CreativeType) bool
+	visit  func(TrackerType, CreativeType, trackerList)
+}
+
+func eachTrackerList(ads []Ad, create func(TrackerType, CreativeType) bool, visit func(TrackerType, CreativeType, trackerList)) {
+	lists := trackerLists{create: create, visit: visit}
+
+	for i := range ads {
+		ad := &ads[i]
+
+		if ad.InLine != nil {
+			lists.adDefinition(&ad.InLine.AdDefinitionBase)
+
+			for j := range ad.InLine.Creatives.Creative {
+				creative := &ad.InLine.Creatives.Creative[j]
+				if creative.Linear != nil {
+					lists.linear(&creative.Linear.LinearBase, &creative.Linear.VideoClicks)
+				}
+
+				lists.nonLinearAds(creative.NonLinearAds)
+				lists.companionAds(creative.CompanionAds)
+			}
+		}
+
+		if ad.Wrapper != nil {
+			lists.adDefinition(&ad.Wrapper.AdDefinitionBase)
+
+			if ad.Wrapper.Creatives == nil {
+				continue
+			}
+
+			for j := range ad.Wrapper.Creatives.Creative {
+				creative := &ad.Wrapper.Creatives.Creative[j]
+				if creative.Linear != nil {
+					lists.linear(&creative.Linear.LinearBase, &creative.Linear.VideoClicks)
+				}
+
+				lists.nonLinearAds(creative.NonLinearAds)
+				lists.companionAds(creative.CompanionAds)
+			}
+		}
+	}
+}
+
+func (l trackerLists) adDefinition(base *AdDefinitionBase) {
+	l.visit(ImpressionTracker, NoCreative, impressionTrackers{&base.Impression})
+	l.visit(ErrorTracker, NoCreative, cdataTrackers{&base.Error})
+
+	if base.ViewableImpression == nil &&
+		(l.create(ViewableTracker, NoCreative) || l.create(NotViewableTracker, NoCreative) || l.create(ViewUndeterminedTracker, NoCreative)) {
+		base.ViewableImpression = &ViewableImpression{}
+	}
+
+	if base.ViewableImpression != nil {
+		l.visit(ViewableTracker, NoCreative, cdataTrackers{&base.ViewableImpression.Viewable})
+		l.visit(NotViewableTracker, NoCreative, cdataTrackers{&base.ViewableImpression.NotViewable})
+		l.visit(ViewUndeterminedTracker, NoCreative, cdataTrackers{&base.ViewableImpression.ViewUndetermined})
+	}
+}
+
+func (l trackerLists) linear(linear *LinearBase, videoClicks **VideoClicks) {
+	l.visit(TrackingEventTracker, LinearCreative, trackingTrackers{&linear.TrackingEvents})
+
+	if *videoClicks == nil && l.create(ClickTracker, LinearCreative) {
+		*videoClicks = &VideoClicks{}
+	}
+
+	if *videoClicks != nil {
+		l.visit(ClickTracker, LinearCreative, cdataTrackers{&(*videoClicks).ClickTracking})
+	}
+
+	if linear.Icons == nil {
+		return
+	}
+
+	for i := range linear.Icons.Icon {
+		icon := &linear.Icons.Icon[i]
+		l.visit(IconViewTracker, IconCreative, stringTrackers{&icon.IconViewTracking})
+
+		if icon.IconClicks == nil && l.create(ClickTracker, IconCreative) {
+			icon.IconClicks = &IconClicks{}
+		}
+
+		if icon.IconClicks != nil {
+			l.visit(ClickTracker, IconCreative, stringTrackers{&icon.IconClicks.IconClickTracking})
+		}
+	}
+}
+
+func (l trackerLists) nonLinearAds(nonLinearAds *NonLinearAds) {
+	if nonLinearAds == nil {
+		return
+	}
+
+	l.visit(TrackingEventTracker, NonLinearCreative, trackingTrackers{&nonLinearAds.TrackingEvents})
+
+	for i := range nonLinearAds.NonLinear {
+		l.visit(ClickTracker, NonLinearCreative, cdataTrackers{&nonLinearAds.NonLinear[i].NonLinearClickTracking})
+	}
+}
+
+func (l trackerLists) companionAds(companionAds *CompanionAdsCollection) {
+	if companionAds == nil {
+		return
+	}
+
+	for i := range companionAds.Companion {
+		companion := &companionAds.Companion[i]
+		l.visit(TrackingEventTracker, CompanionCreative, trackingTrackers{&companion.TrackingEvents})
+		l.visit(ClickTracker, CompanionCreative, stringTrackers{&companion.CompanionClickTracking})
+	}
+}
@@ -0,0 +1,201 @@
+package vast_test
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func trackerVAST() *vast.VAST {
+	tracking := func() *vast.TrackingEvents {
+		return &vast.TrackingEvents{Tracking: []vast.Tracking{{Event: "creativeView", Value: "https://example.com/creativeView"}}}
+	}
+
+	testVAST := vast.New()
+	testVAST.Ad = []vast.Ad{
+		{
+			InLine: &vast.InLine{
+				AdDefinitionBase: vast.AdDefinitionBase{
+					Error:      []vast.CData{{Value: "https://example.com/error"}},
+					Impression: []vast.Impression{{ID: "1", Value: "https://example.com/impression"}},
+				},
+				Creatives: vast.InLineCreatives{Creative: []vast.InLineCreative{
+					{
+						Linear: &vast.LinearInLine{
+							LinearBase: vast.LinearBase{
+								TrackingEvents: &vast.TrackingEvents{Tracking: []vast.Tracking{
+									{Event: "start", Value: "https://example.com/start"},
+									{Event: "progress", Offset: "00:00:05", Value: "https://example.com/progress"},
+								}},
+								Icons: &vast.Icons{Icon: []vast.Icon{{
+									IconViewTracking: []string{"https://example.com/iconView"},
+								}}},
+							},
+						},
+					},
+					{
+						NonLinearAds: &vast.NonLinearAds{
+							TrackingEvents: tracking(),
+							NonLinear:      []vast.NonLinearAdInLine{{NonLinearClickTracking: []vast.CData{{Value: "https://example.com/nonLinearClick"}}}},
+						},
+					},
+					{
+						CompanionAds: &vast.CompanionAdsCollection{Companion: []vast.CompanionAd{{
+							TrackingEvents:         tracking(),
+							CompanionClickTracking: []string{"https://example.com/companionClick"},
+						}}},
+					},
+				}},
+			},
+		},
+		{
+			Wrapper: &vast.Wrapper{
+				AdDefinitionBase: vast.AdDefinitionBase{
+					Impression: []vast.Impression{{Value: "https://example.com/wrapper/impression"}},
+					ViewableImpression: &vast.ViewableImpression{
+						Viewable: []vast.CData{{Value: "https://example.com/viewable"}},
+					},
+				},
+				Creatives: &vast.Creatives{Creative: []vast.WrapperCreative{{
+					Linear: &vast.LinearWrapper{
+						VideoClicks: &vast.VideoClicks{ClickTracking: []vast.CData{{Value: "https://example.com/click"}}},
+					},
+				}}},
+			},
+		},
+	}
+
+	return testVAST
+}
+
+func collectTrackers(testVAST *vast.VAST) []vast.Tracker {
+	var trackers []vast.Tracker
+
+	testVAST.RewriteTrackers(func(tracker vast.Tracker) (vast.Tracker, bool) {
+		trackers = append(trackers, tracker)
+		return tracker, true
+	})
+
+	return trackers
+}
+
+func TestVAST_RewriteTrackers(t *testing.T) {
+	testVAST := trackerVAST()
+	testVAST.Error = []vast.CData{{Value: "https://example.com/noAd"}}
+
+	expected := []vast.Tracker{
+		{Type: vast.ErrorTracker, URL: "https://example.com/noAd"},
+		{Type: vast.ImpressionTracker, URL: "https://example.com/impression"},
+		{Type: vast.ErrorTracker, URL: "https://example.com/error"},
+		{Type: vast.TrackingEventTracker, CreativeType: vast.LinearCreative, Event: vast.StartEvent, URL: "https://example.com/start"},
+		{Type: vast.TrackingEventTracker, CreativeType: vast.LinearCreative, Event: vast.ProgressEvent, Offset: "00:00:05", URL: "https://example.com/progress"},
+		{Type: vast.IconViewTracker, CreativeType: vast.IconCreative, URL: "https://example.com/iconView"},
+		{Type: vast.TrackingEventTracker, CreativeType: vast.NonLinearCreative, Event: vast.CreativeViewEvent, URL: "https://example.com/creativeView"},
+		{Type: vast.ClickTracker, CreativeType: vast.NonLinearCreative, URL: "https://example.com/nonLinearClick"},
+		{Type: vast.TrackingEventTracker, CreativeType: vast.CompanionCreative, Event: vast.CreativeViewEvent, URL: "https://example.com/creativeView"},
+		{Type: vast.ClickTracker, CreativeType: vast.CompanionCreative, URL: "https://example.com/companionClick"},
+		{Type: vast.ImpressionTracker, URL: "https://example.com/wrapper/impression"},
+		{Type: vast.ViewableTracker, URL: "https://example.com/viewable"},
+		{Type: vast.ClickTracker, CreativeType: vast.LinearCreative, URL: "https://example.com/click"},
+	}
+
+	if diff := cmp.Diff(expected, collectTrackers(testVAST)); diff != "" {
+		t.Errorf("wrong trackers: %s", diff)
+	}
+
+	testVAST.RewriteTrackers(func(tracker vast.Tracker) (vast.Tracker, bool) {
+		if tracker.Type == vast.TrackingEventTracker && tracker.Event != vast.StartEvent {
+			return tracker, false
+		}
+
+		tracker.URL = "https://redirect.example/?url=" + url.QueryEscape(tracker.URL)
+
+		return tracker, true
+	})
+
+	trackers := collectTrackers(testVAST)
+	if len(trackers) != len(expected)-3 {
+		t.Errorf("unexpected trackers: %v", trackers)
+	}
+
+	for _, tracker := range trackers {
+		if !strings.HasPrefix(tracker.URL, "https://redirect.example/?url=https%3A%2F%2Fexample.com%2F") {
+			t.Errorf("unexpected URL: %s", tracker.URL)
+		}
+	}
+
+	if testVAST.Ad[0].InLine.Impression[0].ID != "1" {
+		t.Error("impression ID lost")
+	}
+
+	if testVAST.Ad[0].InLine.Creatives.Creative[1].NonLinearAds.TrackingEvents != nil {
+		t.Error("empty tracking events not removed")
+	}
+}
+
+func TestVAST_RewriteTrackers_fixtures(t *testing.T) {
+	for _, testCase := range iabFixtures {
+		t.Run(testCase, func(t *testing.T) {
+			testVAST := mustReadFixture(t, testCase)
+			testVAST.RewriteTrackers(func(tracker vast.Tracker) (vast.Tracker, bool) {
+				return tracker, true
+			})
+
+			if diff := cmp.Diff(mustReadFixture(t, testCase), testVAST); diff != "" {
+				t.Errorf("wrong VAST: %s", diff)
+			}
+		})
+	}
+}
+
+func TestVAST_InjectTrackers(t *testing.T) {
+	testVAST := trackerVAST()
+	testVAST.InjectTrackers(
+		vast.Tracker{Type: vast.ImpressionTracker, URL: "https://us.example/impression"},
+		vast.Tracker{Type: vast.NotViewableTracker, URL: "https://us.example/notViewable"},
+		vast.Tracker{Type: vast.TrackingEventTracker, CreativeType: vast.LinearCreative, Event: vast.CompleteEvent, URL: "https://us.example/complete"},
+		vast.Tracker{Type: vast.TrackingEventTracker, CreativeType: vast.CompanionCreative, Event: vast.CreativeViewEvent, URL: "https://us.example/companionView"},
+		vast.Tracker{Type: vast.ClickTracker, CreativeType: vast.LinearCreative, URL: "https://us.example/click"},
+		vast.Tracker{Type: vast.ClickTracker, CreativeType: vast.IconCreative, URL: "https://us.example/iconClick"},
+		vast.Tracker{Type: vast.TrackingEventTracker, CreativeType: vast.IconCreative, Event: vast.StartEvent, URL: "https://us.example/ignored"},
+	)
+
+	var injected []vast.Tracker
+
+	for _, tracker := range collectTrackers(testVAST) {
+		if strings.HasPrefix(tracker.URL, "https://us.example/") {
+			injected = append(injected, tracker)
+		}
+	}
+
+	expected := []vast.Tracker{
+		{Type: vast.ImpressionTracker, URL: "https://us.example/impression"},
+		{Type: vast.NotViewableTracker, URL: "https://us.example/notViewable"},
+		{Type: vast.TrackingEventTracker, CreativeType: vast.LinearCreative, Event: vast.CompleteEvent, URL: "https://us.example/complete"},
+		{Type: vast.ClickTracker, CreativeType: vast.LinearCreative, URL: "https://us.example/click"},
+		{Type: vast.ClickTracker, CreativeType: vast.IconCreative, URL: "https://us.example/iconClick"},
+		{Type: vast.TrackingEventTracker, CreativeType: vast.CompanionCreative, Event: vast.CreativeViewEvent, URL: "https://us.example/companionView"},
+		{Type: vast.ImpressionTracker, URL: "https://us.example/impression"},
+		{Type: vast.NotViewableTracker, URL: "https://us.example/notViewable"},
+		{Type: vast.TrackingEventTracker, CreativeType: vast.LinearCreative, Event: vast.CompleteEvent, URL: "https://us.example/complete"},
+		{Type: vast.ClickTracker, CreativeType: vast.LinearCreative, URL: "https://us.example/click"},
+	}
+
+	if diff := cmp.Diff(expected, injected); diff != "" {
+		t.Errorf("wrong trackers: %s", diff)
+	}
+}
+
+func TestVAST_InjectTrackers_noAd(t *testing.T) {
+	testVAST := vast.New()
+	testVAST.InjectTrackers(
+		vast.Tracker{Type: vast.ErrorTracker, URL: "https://example.com/error"},
+		vast.Tracker{Type: vast.ImpressionTracker, URL: "https://example.com/impression"},
+	)
+
+	if diff := cmp.Diff([]vast.CData{{Value: "https://example.com/error"}}, testVAST.Error); diff != "" {
+		t.Errorf("wrong VAST: %s", diff)
+	}
+}
@@ -0,0 +1,178 @@
+package vast
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidUniversalAdID = errors.New("invalid UniversalAdId")
+
+const (
+	// AdIDRegistry is the registry of Ad-ID codes, e.g. `ABCD1234000H`.
+	AdIDRegistry = "ad-id.org"
+	// ClearcastRegistry is the registry of Clearcast clock numbers, e.g. `ABC/DEFG123/030`.
+	ClearcastRegistry = "clearcast.co.uk"
+	// UnknownUniversalAdID is used as registry and value if the creative has no registered ID.
+	UnknownUniversalAdID = "unknown"
+)
+
+var (
+	adIDPattern           = regexp.MustCompile(`^[A-Z]{4}[A-Z0-9]{7}[HD]?$`)
+	clearcastClockPattern = regexp.MustCompile(`^[A-Z0-9]{3}/[A-Z0-9]{7,8}/\d{3}[A-Z]?$`)
+)
+
+// ValidAdID checks the format of an Ad-ID code: a four letter company prefix, seven alphanumeric characters and an
+// optional `H` for HD or `D` for 3D. Lowercase letters are accepted.
+func ValidAdID(code string) bool {
+	return adIDPattern.MatchString(strings.ToUpper(code))
+}
+
+// ValidClearcastClockNumber checks the format of a Clearcast clock number: the agency code, the advertiser and product
+// code and the duration in seconds, separated by slashes. Lowercase letters are accepted.
+func ValidClearcastClockNumber(clockNumber string) bool {
+	return clearcastClockPattern.MatchString(strings.ToUpper(clockNumber))
+}
+
+// registry returns AdIDRegistry or ClearcastRegistry for the known spellings of these registries, and the trimmed,
+// lowercase registry otherwise.
+func (u UniversalAdID) registry() string {
+	registry := normalizeAuthority(u.IDRegistry)
+
+	switch registry {
+	case AdIDRegistry, "ad-id", "adid":
+		return AdIDRegistry
+	case ClearcastRegistry, "clearcast":
+		return ClearcastRegistry
+	}
+
+	return registry
+}
+
+// IsUnknown checks whether the registry or the value is empty or `unknown`.
+func (u UniversalAdID) IsUnknown() bool {
+	registry, value := u.registry(), strings.ToLower(strings.TrimSpace(u.Value))
+
+	return registry == "" || registry == UnknownUniversalAdID || value == "" || value == UnknownUniversalAdID
+}
+
+// Validate checks the format of Ad-ID codes and Clearcast clock numbers. IDs of other registries and unknown IDs are
+// not checked.
+func (u UniversalAdID) Validate() error {
+	if u.IsUnknown() {
+		return nil
+	}
+
+	value := strings.TrimSpace(u.Value)
+
+	switch u.registry() {
+	case AdIDRegistry:
+		if !ValidAdID(value) {
+			return fmt.Errorf("%w: invalid Ad-ID %q", ErrInvalidUniversalAdID, value)
+		}
+	case ClearcastRegistry:
+		if !ValidClearcastClockNumber(value) {
+			return fmt.Errorf("%w: invalid Clearcast clock number %q", ErrInvalidUniversalAdID, value)
+		}
+	}
+
+	return nil
+}
+
+// Key returns the normalized registry and value separated by a colon, e.g. `ad-id.org:ABCD1234000H`, or an empty
+// string if the ID is unknown. Ad-ID codes and Clearcast clock numbers are converted to uppercase.
+func (u UniversalAdID) Key() string {
+	if u.IsUnknown() {
+		return ""
+	}
+
+	registry, value := u.registry(), strings.TrimSpace(u.Value)
+	if registry == AdIDRegistry || registry == ClearcastRegistry {
+		value = strings.ToUpper(value)
+	}
+
+	return registry + ":" + value
+}
+
+// CanonicalID returns an identifier of the creative. It is the Key of the first known UniversalAdId, the adId
+// attribute prefixed with `adId:`, the id attribute prefixed with `id:`, or the SHA-256 hash of the sorted media and
+// resource URLs prefixed with `sha256:`, whichever is available first. It is empty if the creative has none of these.
+func (c *InLineCreative) CanonicalID() string {
+	for _, universalAdID := range c.UniversalAdID {
+		if key := universalAdID.Key(); key != "" {
+			return key
+		}
+	}
+
+	if adID := strings.TrimSpace(c.AdID); adID != "" {
+		return "adId:" + adID
+	}
+
+	if id := strings.TrimSpace(c.ID); id != "" {
+		return "id:" + id
+	}
+
+	return c.mediaHash()
+}
+
+// mediaHash returns the SHA-256 hash of the media and resource URLs of the creative, or an empty string if it has
+// none.
+func (c *InLineCreative) mediaHash() string {
+	var urls []string
+
+	m := &VAST{Ad: []Ad{{InLine: &InLine{Creatives: InLineCreatives{Creative: []InLineCreative{*c}}}}}}
+	for _, u := range m.URLs() {
+		if u.Category == MediaURL || u.Category == ResourceURL {
+			urls = append(urls, u.Value)
+		}
+	}
+
+	if len(urls) == 0 {
+		return ""
+	}
+
+	slices.Sort(urls)
+
+	hash := sha256.Sum256([]byte(strings.Join(slices.Compact(urls), "\n")))
+
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+// SameCreative checks whether two creatives, e.g. from different tags, are the same creative.
+// Creatives with an equal known UniversalAdId are the same, creatives with different IDs of the same registry are not.
+// Otherwise, the adId attributes are compared if both have one, and the media and resource URLs otherwise.
+// The id attributes are not compared, since they are assigned by the ad server.
+func (c *InLineCreative) SameCreative(other *InLineCreative) bool {
+	registries := map[string]bool{}
+
+	for _, a := range c.UniversalAdID {
+		for _, b := range other.UniversalAdID {
+			key, otherKey := a.Key(), b.Key()
+			if key == "" || otherKey == "" || a.registry() != b.registry() {
+				continue
+			}
+
+			if key == otherKey {
+				return true
+			}
+
+			registries[a.registry()] = true
+		}
+	}
+
+	if len(registries) > 0 {
+		return false
+	}
+
+	if adID, otherAdID := strings.TrimSpace(c.AdID), strings.TrimSpace(other.AdID); adID != "" && otherAdID != "" {
+		return adID == otherAdID
+	}
+
+	hash := c.mediaHash()
+
+	return hash != "" && hash == other.mediaHash()
+}
@@ -0,0 +1,155 @@
+package vast_test
+
+import (
+	"errors"
+	"go.eigsys.de/go-vast"
+	"testing"
+)
+
+func TestValidAdID(t *testing.T) {
+	tests := map[string]bool{
+		"ABCD1234000":   true,
+		"ABCD1234000H":  true,
+		"abcd1234000d":  true,
+		"ABCD1234000X":  false,
+		"ABC12345678":   false,
+		"ABCD123400":    false,
+		"ABCD-1234-000": false,
+	}
+
+	for code, expected := range tests {
+		if vast.ValidAdID(code) != expected {
+			t.Errorf("wrong result for %q", code)
+		}
+	}
+}
+
+func TestValidClearcastClockNumber(t *testing.T) {
+	tests := map[string]bool{
+		"ABC/DEFG123/030":  true,
+		"abc/defg1234/010": true,
+		"ABC/DEFG123/030A": true,
+		"ABC/DEFG123/30":   false,
+		"ABCDEFG123030":    false,
+	}
+
+	for clockNumber, expected := range tests {
+		if vast.ValidClearcastClockNumber(clockNumber) != expected {
+			t.Errorf("wrong result for %q", clockNumber)
+		}
+	}
+}
+
+func TestUniversalAdID_Validate(t *testing.T) {
+	valid := []vast.UniversalAdID{
+		{IDRegistry: "ad-id.org", Value: "ABCD1234000H"},
+		{IDRegistry: "https://www.ad-id.org/", Value: " abcd1234000 "},
+		{IDRegistry: "clearcast.co.uk", Value: "ABC/DEFG123/030"},
+		{IDRegistry: "unknown", Value: "unknown"},
+		{IDRegistry: "Ad-ID", Value: "unknown"},
+		{IDRegistry: "FooId", Value: "9999"},
+	}
+
+	for _, universalAdID := range valid {
+		if err := universalAdID.Validate(); err != nil {
+			t.Errorf("unexpected error for %v: %v", universalAdID, err)
+		}
+	}
+
+	invalid := []vast.UniversalAdID{
+		{IDRegistry: "Ad-ID", Value: "8465"},
+		{IDRegistry: "Clearcast", Value: "ABC-DEFG123-030"},
+	}
+
+	for _, universalAdID := range invalid {
+		if err := universalAdID.Validate(); !errors.Is(err, vast.ErrInvalidUniversalAdID) {
+			t.Errorf("expected error for %v", universalAdID)
+		}
+	}
+}
+
+func TestUniversalAdID_Key(t *testing.T) {
+	tests := map[vast.UniversalAdID]string{
+		{IDRegistry: "Ad-ID", Value: " abcd1234000h "}:      "ad-id.org:ABCD1234000H",
+		{IDRegistry: "clearcast", Value: "abc/defg123/030"}: "clearcast.co.uk:ABC/DEFG123/030",
+		{IDRegistry: "FooId", Value: "abc"}:                 "fooid:abc",
+		{IDRegistry: "unknown", Value: "unknown"}:           "",
+		{IDRegistry: "", Value: "abc"}:                      "",
+	}
+
+	for universalAdID, expected := range tests {
+		if key := universalAdID.Key(); key != expected {
+			t.Errorf("wrong key for %v: %q", universalAdID, key)
+		}
+	}
+}
+
+func TestInLineCreative_CanonicalID(t *testing.T) {
+	linear := &vast.LinearInLine{MediaFiles: vast.MediaFiles{MediaFile: []vast.MediaFile{
+		{Value: "https://example.com/video.webm"},
+		{Value: "https://example.com/video.mp4"},
+	}}}
+
+	tests := []struct {
+		creative vast.InLineCreative
+		expected string
+	}{
+		{
+			creative: vast.InLineCreative{
+				UniversalAdID: []vast.UniversalAdID{{IDRegistry: "unknown", Value: "unknown"}, {IDRegistry: "Ad-ID", Value: "ABCD1234000"}},
+				CreativeBase:  vast.CreativeBase{AdID: "ad"},
+				ID:            "1",
+			},
+			expected: "ad-id.org:ABCD1234000",
+		},
+		{
+			creative: vast.InLineCreative{UniversalAdID: []vast.UniversalAdID{{IDRegistry: "unknown", Value: "unknown"}}, CreativeBase: vast.CreativeBase{AdID: "ad"}, ID: "1"},
+			expected: "adId:ad",
+		},
+		{
+			creative: vast.InLineCreative{ID: " 1 "},
+			expected: "id:1",
+		},
+		{
+			creative: vast.InLineCreative{Linear: linear},
+			expected: "sha256:d900cdf7043f460b239b2480d0b004ca8c1d54800fcd7b631a26e3f69c033f46",
+		},
+		{
+			creative: vast.InLineCreative{},
+			expected: "",
+		},
+	}
+
+	for _, testCase := range tests {
+		if id := testCase.creative.CanonicalID(); id != testCase.expected {
+			t.Errorf("wrong ID: %q", id)
+		}
+	}
+}
+
+func TestInLineCreative_SameCreative(t *testing.T) {
+	adID := func(value string) []vast.UniversalAdID {
+		return []vast.UniversalAdID{{IDRegistry: "FooId", Value: "foo"}, {IDRegistry: "ad-id.org", Value: value}}
+	}
+	media := func(uri string) *vast.LinearInLine {
+		return &vast.LinearInLine{MediaFiles: vast.MediaFiles{MediaFile: []vast.MediaFile{{Value: uri}}}}
+	}
+
+	tests := []struct {
+		a, b     vast.InLineCreative
+		expected bool
+	}{
+		{a: vast.InLineCreative{UniversalAdID: adID("ABCD1234000")}, b: vast.InLineCreative{UniversalAdID: adID("abcd1234000")}, expected: true},
+		{a: vast.InLineCreative{UniversalAdID: adID("ABCD1234000"), Linear: media("https://example.com/video.mp4")}, b: vast.InLineCreative{UniversalAdID: []vast.UniversalAdID{{IDRegistry: "Ad-ID", Value: "ABCD1234001"}}, Linear: media("https://example.com/video.mp4")}, expected: false},
+		{a: vast.InLineCreative{CreativeBase: vast.CreativeBase{AdID: "1"}, UniversalAdID: adID("ABCD1234000")}, b: vast.InLineCreative{CreativeBase: vast.CreativeBase{AdID: "1"}}, expected: true},
+		{a: vast.InLineCreative{CreativeBase: vast.CreativeBase{AdID: "1"}, Linear: media("https://example.com/video.mp4")}, b: vast.InLineCreative{CreativeBase: vast.CreativeBase{AdID: "2"}, Linear: media("https://example.com/video.mp4")}, expected: false},
+		{a: vast.InLineCreative{ID: "1", Linear: media("https://example.com/video.mp4")}, b: vast.InLineCreative{ID: "2", Linear: media(" https://example.com/video.mp4 ")}, expected: true},
+		{a: vast.InLineCreative{ID: "1"}, b: vast.InLineCreative{ID: "1"}, expected: false},
+	}
+
+	for i, testCase := range tests {
+		if testCase.a.SameCreative(&testCase.b) != testCase.expected {
+			t.Errorf("wrong result for test case %d", i)
+		}
+	}
+}
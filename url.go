@@ -0,0 +1,262 @@
+package vast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// URLCategory classifies the URLs of a VAST document.
+type URLCategory string
+
+const (
+	// TrackingURL is fired to report an event, e.g. Impression, Error, Tracking or ClickTracking.
+	TrackingURL URLCategory = "tracking"
+	// ClickURL is opened on user interaction, e.g. ClickThrough or CustomClick.
+	ClickURL URLCategory = "click"
+	// MediaURL is loaded by the player, e.g. MediaFile, Mezzanine or ClosedCaptionFile.
+	MediaURL URLCategory = "media"
+	// ResourceURL is rendered as creative, e.g. StaticResource, IFrameResource or Survey.
+	ResourceURL URLCategory = "resource"
+	// VerificationURL is a verification script, i.e. JavaScriptResource or ExecutableResource.
+	VerificationURL URLCategory = "verification"
+	// WrapperURL is the VASTAdTagURI of a wrapper.
+	WrapperURL URLCategory = "wrapper"
+)
+
+// URL is a URL found in a VAST document. Element is the name and Path the path of the element containing the URL.
+type URL struct {
+	Category URLCategory
+	Element  string
+	Path     string
+	Value    string
+}
+
+// URLs returns all non-empty URLs of the VAST, with leading and trailing white space removed.
+func (m *VAST) URLs() []URL {
+	var urls []URL
+
+	visitURLs(m, func(u URL, _ Path, _ *string) bool {
+		urls = append(urls, u)
+		return true
+	})
+
+	return urls
+}
+
+// visitURLs calls fn for every non-empty URL of the VAST with the path of the walked node containing it and a pointer
+// to the value in the tree. If fn returns false, repeated elements are removed and the values of other elements are
+// cleared.
+func visitURLs(m *VAST, fn func(URL, Path, *string) bool) {
+	v := urlVisitor{fn: fn}
+
+	_ = Walk(m, Visitor{
+		VAST: func(m *VAST, path Path) error {
+			v.path = path
+			m.Error = visitURLSlice(v, TrackingURL, path.String(), "Error", m.Error, cdataValue)
+			return nil
+		},
+		InLine: func(inLine *InLine, path Path) error {
+			v.path = path
+			v.adDefinition(path.String(), &inLine.AdDefinitionBase)
+
+			if inLine.Survey != nil && !v.visit(ResourceURL, path.String(), "Survey", 0, &inLine.Survey.Value) {
+				inLine.Survey = nil
+			}
+
+			return nil
+		},
+		Wrapper: func(wrapper *Wrapper, path Path) error {
+			v.path = path
+			v.adDefinition(path.String(), &wrapper.AdDefinitionBase)
+			v.visit(WrapperURL, path.String(), "VASTAdTagURI", 0, &wrapper.VASTAdTagURI.Value)
+
+			return nil
+		},
+		Verification: func(verification *Verification, path Path) error {
+			v.path = path
+			verification.JavaScriptResource = visitURLSlice(v, VerificationURL, path.String(), "JavaScriptResource", verification.JavaScriptResource,
+				func(resource *JavaScriptResource) *string { return &resource.Value })
+			verification.ExecutableResource = visitURLSlice(v, VerificationURL, path.String(), "ExecutableResource", verification.ExecutableResource,
+				func(resource *ExecutableResource) *string { return &resource.Value })
+
+			if verification.TrackingEvents != nil {
+				verification.TrackingEvents.Tracking = v.tracking(path.String(), verification.TrackingEvents.Tracking)
+			}
+
+			return nil
+		},
+		LinearInLine: func(linear *LinearInLine, path Path) error {
+			v.path = path
+			v.linear(path.String(), &linear.LinearBase, linear.VideoClicks)
+
+			mediaFiles := path.String() + "/MediaFiles"
+			linear.MediaFiles.MediaFile = visitURLSlice(v, MediaURL, mediaFiles, "MediaFile", linear.MediaFiles.MediaFile,
+				func(mediaFile *MediaFile) *string { return &mediaFile.Value })
+			linear.MediaFiles.Mezzanine = visitURLSlice(v, MediaURL, mediaFiles, "Mezzanine", linear.MediaFiles.Mezzanine,
+				func(mezzanine *Mezzanine) *string { return &mezzanine.Value })
+			linear.MediaFiles.InteractiveCreativeFile = visitURLSlice(v, MediaURL, mediaFiles, "InteractiveCreativeFile", linear.MediaFiles.InteractiveCreativeFile,
+				func(file *InteractiveCreativeFile) *string { return &file.Value })
+
+			if closedCaptionFiles := linear.MediaFiles.ClosedCaptionFiles; closedCaptionFiles != nil {
+				closedCaptionFiles.ClosedCaptionFile = visitURLSlice(v, MediaURL, mediaFiles+"/ClosedCaptionFiles", "ClosedCaptionFile", closedCaptionFiles.ClosedCaptionFile,
+					func(file *ClosedCaptionFile) *string { return &file.Value })
+			}
+
+			return nil
+		},
+		LinearWrapper: func(linear *LinearWrapper, path Path) error {
+			v.path = path
+			v.linear(path.String(), &linear.LinearBase, linear.VideoClicks)
+			return nil
+		},
+		Icon: func(icon *Icon, path Path) error {
+			v.path = path
+			icon.StaticResource, icon.IFrameResource = v.resources(path.String(), icon.StaticResource, icon.IFrameResource)
+			icon.IconViewTracking = visitURLSlice(v, TrackingURL, path.String(), "IconViewTracking", icon.IconViewTracking, stringValue)
+
+			if icon.IconClicks == nil {
+				return nil
+			}
+
+			iconClicks := path.String() + "/IconClicks"
+			v.visit(ClickURL, iconClicks, "IconClickThrough", 0, &icon.IconClicks.IconClickThrough)
+			icon.IconClicks.IconClickTracking = visitURLSlice(v, TrackingURL, iconClicks, "IconClickTracking", icon.IconClicks.IconClickTracking, stringValue)
+
+			if icon.IconClicks.IconClickFallbackImages == nil {
+				return nil
+			}
+
+			for i := range icon.IconClicks.IconClickFallbackImages.IconClickFallbackImage {
+				image := &icon.IconClicks.IconClickFallbackImages.IconClickFallbackImage[i]
+				if image.StaticResource != nil && !v.visit(ResourceURL, indexed(iconClicks+"/IconClickFallbackImages", "IconClickFallbackImage", i), "StaticResource", 0, &image.StaticResource.Value) {
+					image.StaticResource = nil
+				}
+			}
+
+			return nil
+		},
+		NonLinearAds: func(nonLinearAds *NonLinearAds, path Path) error {
+			v.path = path
+			if nonLinearAds.TrackingEvents != nil {
+				nonLinearAds.TrackingEvents.Tracking = v.tracking(path.String(), nonLinearAds.TrackingEvents.Tracking)
+			}
+
+			return nil
+		},
+		NonLinear: func(nonLinear *NonLinearAdInLine, path Path) error {
+			v.path = path
+			nonLinear.StaticResource, nonLinear.IFrameResource = v.resources(path.String(), nonLinear.StaticResource, nonLinear.IFrameResource)
+
+			if nonLinear.NonLinearClickThrough != nil && !v.visit(ClickURL, path.String(), "NonLinearClickThrough", 0, &nonLinear.NonLinearClickThrough.Value) {
+				nonLinear.NonLinearClickThrough = nil
+			}
+
+			nonLinear.NonLinearClickTracking = visitURLSlice(v, TrackingURL, path.String(), "NonLinearClickTracking", nonLinear.NonLinearClickTracking, cdataValue)
+
+			return nil
+		},
+		Companion: func(companion *CompanionAd, path Path) error {
+			v.path = path
+			companion.StaticResource, companion.IFrameResource = v.resources(path.String(), companion.StaticResource, companion.IFrameResource)
+
+			if companion.CompanionClickThrough != nil && !v.visit(ClickURL, path.String(), "CompanionClickThrough", 0, &companion.CompanionClickThrough.Value) {
+				companion.CompanionClickThrough = nil
+			}
+
+			companion.CompanionClickTracking = visitURLSlice(v, TrackingURL, path.String(), "CompanionClickTracking", companion.CompanionClickTracking, stringValue)
+
+			if companion.TrackingEvents != nil {
+				companion.TrackingEvents.Tracking = v.tracking(path.String(), companion.TrackingEvents.Tracking)
+			}
+
+			return nil
+		},
+	})
+}
+
+type urlVisitor struct {
+	fn   func(URL, Path, *string) bool
+	path Path
+}
+
+// visit reports the URL of the element with the given name below the parent path and clears the value if it is not
+// kept. Index is the 1-based position of repeated elements and 0 otherwise.
+func (v urlVisitor) visit(category URLCategory, parent, element string, index int, value *string) bool {
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return true
+	}
+
+	path := parent + "/" + element
+	if index > 0 {
+		path = fmt.Sprintf("%s[%d]", path, index)
+	}
+
+	if !v.fn(URL{Category: category, Element: element, Path: path, Value: trimmed}, v.path, value) {
+		*value = ""
+		return false
+	}
+
+	return true
+}
+
+// visitURLSlice visits the URLs of repeated elements and returns the kept elements.
+func visitURLSlice[T any](v urlVisitor, category URLCategory, parent, element string, values []T, value func(*T) *string) []T {
+	var kept []T
+
+	for i := range values {
+		if v.visit(category, parent, element, i+1, value(&values[i])) {
+			kept = append(kept, values[i])
+		}
+	}
+
+	if len(kept) == len(values) {
+		return values
+	}
+
+	return kept
+}
+
+func cdataValue(value *CData) *string {
+	return &value.Value
+}
+
+func stringValue(value *string) *string {
+	return value
+}
+
+func (v urlVisitor) adDefinition(path string, base *AdDefinitionBase) {
+	base.Error = visitURLSlice(v, TrackingURL, path, "Error", base.Error, cdataValue)
+	base.Impression = visitURLSlice(v, TrackingURL, path, "Impression", base.Impression, func(impression *Impression) *string { return &impression.Value })
+
+	if viewableImpression := base.ViewableImpression; viewableImpression != nil {
+		viewablePath := path + "/ViewableImpression"
+		viewableImpression.Viewable = visitURLSlice(v, TrackingURL, viewablePath, "Viewable", viewableImpression.Viewable, cdataValue)
+		viewableImpression.NotViewable = visitURLSlice(v, TrackingURL, viewablePath, "NotViewable", viewableImpression.NotViewable, cdataValue)
+		viewableImpression.ViewUndetermined = visitURLSlice(v, TrackingURL, viewablePath, "ViewUndetermined", viewableImpression.ViewUndetermined, cdataValue)
+	}
+}
+
+func (v urlVisitor) tracking(path string, tracking []Tracking) []Tracking {
+	return visitURLSlice(v, TrackingURL, path+"/TrackingEvents", "Tracking", tracking, func(tracking *Tracking) *string { return &tracking.Value })
+}
+
+func (v urlVisitor) linear(path string, linear *LinearBase, videoClicks *VideoClicks) {
+	if linear.TrackingEvents != nil {
+		linear.TrackingEvents.Tracking = v.tracking(path, linear.TrackingEvents.Tracking)
+	}
+
+	if videoClicks == nil {
+		return
+	}
+
+	path += "/VideoClicks"
+	v.visit(ClickURL, path, "ClickThrough", 0, &videoClicks.ClickThrough.Value)
+	videoClicks.ClickTracking = visitURLSlice(v, TrackingURL, path, "ClickTracking", videoClicks.ClickTracking, cdataValue)
+	videoClicks.CustomClick = visitURLSlice(v, ClickURL, path, "CustomClick", videoClicks.CustomClick, stringValue)
+}
+
+func (v urlVisitor) resources(path string, staticResources []StaticResource, iFrameResources []CData) ([]StaticResource, []CData) {
+	return visitURLSlice(v, ResourceURL, path, "StaticResource", staticResources, func(resource *StaticResource) *string { return &resource.Value }),
+		visitURLSlice(v, ResourceURL, path, "IFrameResource", iFrameResources, cdataValue)
+}
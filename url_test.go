@@ -0,0 +1,92 @@
+package vast_test
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"testing"
+)
+
+func TestVAST_URLs(t *testing.T) {
+	expected := []vast.URL{
+		{Category: vast.TrackingURL, Element: "Error", Path: "/VAST/Ad[1]/Wrapper/Error[1]", Value: "https://example.com/error"},
+		{Category: vast.TrackingURL, Element: "Impression", Path: "/VAST/Ad[1]/Wrapper/Impression[1]", Value: "https://example.com/track/impression"},
+		{Category: vast.WrapperURL, Element: "VASTAdTagURI", Path: "/VAST/Ad[1]/Wrapper/VASTAdTagURI", Value: "https://raw.githubusercontent.com/InteractiveAdvertisingBureau/VAST_Samples/master/VAST%204.2%20Samples/Inline_Companion_Tag-test.xml"},
+		{Category: vast.ResourceURL, Element: "StaticResource", Path: "/VAST/Ad[1]/Wrapper/Creatives/Creative[1]/CompanionAds/Companion[1]/StaticResource[1]", Value: "https://www.iab.com/wp-content/uploads/2014/09/iab-tech-lab-6-644x290.png"},
+		{Category: vast.ClickURL, Element: "CompanionClickThrough", Path: "/VAST/Ad[1]/Wrapper/Creatives/Creative[1]/CompanionAds/Companion[1]/CompanionClickThrough", Value: "https://iabtechlab.com"},
+	}
+
+	if diff := cmp.Diff(expected, mustReadFixture(t, "iab/Wrapper_Tag-test.xml").URLs()); diff != "" {
+		t.Errorf("wrong URLs: %s", diff)
+	}
+}
+
+func TestVAST_URLs_elements(t *testing.T) {
+	testVAST := trackerVAST()
+	testVAST.Error = []vast.CData{{Value: " https://example.com/noAd\n"}, {Value: " "}}
+
+	inLine := testVAST.Ad[0].InLine
+	inLine.Survey = &vast.Survey{Value: "https://example.com/survey"}
+	inLine.ViewableImpression = &vast.ViewableImpression{
+		NotViewable:      []vast.CData{{Value: "https://example.com/notViewable"}},
+		ViewUndetermined: []vast.CData{{Value: "https://example.com/viewUndetermined"}},
+	}
+	inLine.AdVerifications = &vast.AdVerifications{Verification: []vast.Verification{{
+		ExecutableResource: []vast.ExecutableResource{{Value: "https://example.com/verification.exe"}},
+	}}}
+
+	linear := inLine.Creatives.Creative[0].Linear
+	linear.VideoClicks = &vast.VideoClicks{CustomClick: []string{"https://example.com/customClick"}}
+	linear.MediaFiles.Mezzanine = []vast.Mezzanine{{Value: "https://example.com/mezzanine.mp4"}}
+	linear.MediaFiles.InteractiveCreativeFile = []vast.InteractiveCreativeFile{{Value: "https://example.com/simid.html"}}
+	linear.Icons.Icon[0].IFrameResource = []vast.CData{{Value: "https://example.com/icon.html"}}
+	linear.Icons.Icon[0].IconClicks = &vast.IconClicks{
+		IconClickThrough:  "https://example.com/iconClickThrough",
+		IconClickTracking: []string{"https://example.com/iconClick"},
+	}
+
+	nonLinear := &inLine.Creatives.Creative[1].NonLinearAds.NonLinear[0]
+	nonLinear.NonLinearClickThrough = &vast.CData{Value: "https://example.com/nonLinearClickThrough"}
+
+	expected := []vast.URL{
+		{Category: vast.TrackingURL, Element: "Error", Path: "/VAST/Error[1]", Value: "https://example.com/noAd"},
+		{Category: vast.TrackingURL, Element: "Error", Path: "/VAST/Ad[1]/InLine/Error[1]", Value: "https://example.com/error"},
+		{Category: vast.TrackingURL, Element: "Impression", Path: "/VAST/Ad[1]/InLine/Impression[1]", Value: "https://example.com/impression"},
+		{Category: vast.TrackingURL, Element: "NotViewable", Path: "/VAST/Ad[1]/InLine/ViewableImpression/NotViewable[1]", Value: "https://example.com/notViewable"},
+		{Category: vast.TrackingURL, Element: "ViewUndetermined", Path: "/VAST/Ad[1]/InLine/ViewableImpression/ViewUndetermined[1]", Value: "https://example.com/viewUndetermined"},
+		{Category: vast.ResourceURL, Element: "Survey", Path: "/VAST/Ad[1]/InLine/Survey", Value: "https://example.com/survey"},
+		{Category: vast.VerificationURL, Element: "ExecutableResource", Path: "/VAST/Ad[1]/InLine/AdVerifications/Verification[1]/ExecutableResource[1]", Value: "https://example.com/verification.exe"},
+		{Category: vast.TrackingURL, Element: "Tracking", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/TrackingEvents/Tracking[1]", Value: "https://example.com/start"},
+		{Category: vast.TrackingURL, Element: "Tracking", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/TrackingEvents/Tracking[2]", Value: "https://example.com/progress"},
+		{Category: vast.ClickURL, Element: "CustomClick", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/VideoClicks/CustomClick[1]", Value: "https://example.com/customClick"},
+		{Category: vast.MediaURL, Element: "Mezzanine", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/MediaFiles/Mezzanine[1]", Value: "https://example.com/mezzanine.mp4"},
+		{Category: vast.MediaURL, Element: "InteractiveCreativeFile", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/MediaFiles/InteractiveCreativeFile[1]", Value: "https://example.com/simid.html"},
+		{Category: vast.ResourceURL, Element: "IFrameResource", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/Icons/Icon[1]/IFrameResource[1]", Value: "https://example.com/icon.html"},
+		{Category: vast.TrackingURL, Element: "IconViewTracking", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/Icons/Icon[1]/IconViewTracking[1]", Value: "https://example.com/iconView"},
+		{Category: vast.ClickURL, Element: "IconClickThrough", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/Icons/Icon[1]/IconClicks/IconClickThrough", Value: "https://example.com/iconClickThrough"},
+		{Category: vast.TrackingURL, Element: "IconClickTracking", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/Icons/Icon[1]/IconClicks/IconClickTracking[1]", Value: "https://example.com/iconClick"},
+		{Category: vast.TrackingURL, Element: "Tracking", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[2]/NonLinearAds/TrackingEvents/Tracking[1]", Value: "https://example.com/creativeView"},
+		{Category: vast.ClickURL, Element: "NonLinearClickThrough", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[2]/NonLinearAds/NonLinear[1]/NonLinearClickThrough", Value: "https://example.com/nonLinearClickThrough"},
+		{Category: vast.TrackingURL, Element: "NonLinearClickTracking", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[2]/NonLinearAds/NonLinear[1]/NonLinearClickTracking[1]", Value: "https://example.com/nonLinearClick"},
+		{Category: vast.TrackingURL, Element: "CompanionClickTracking", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[3]/CompanionAds/Companion[1]/CompanionClickTracking[1]", Value: "https://example.com/companionClick"},
+		{Category: vast.TrackingURL, Element: "Tracking", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[3]/CompanionAds/Companion[1]/TrackingEvents/Tracking[1]", Value: "https://example.com/creativeView"},
+		{Category: vast.TrackingURL, Element: "Impression", Path: "/VAST/Ad[2]/Wrapper/Impression[1]", Value: "https://example.com/wrapper/impression"},
+		{Category: vast.TrackingURL, Element: "Viewable", Path: "/VAST/Ad[2]/Wrapper/ViewableImpression/Viewable[1]", Value: "https://example.com/viewable"},
+		{Category: vast.TrackingURL, Element: "ClickTracking", Path: "/VAST/Ad[2]/Wrapper/Creatives/Creative[1]/Linear/VideoClicks/ClickTracking[1]", Value: "https://example.com/click"},
+	}
+
+	if diff := cmp.Diff(expected, testVAST.URLs()); diff != "" {
+		t.Errorf("wrong URLs: %s", diff)
+	}
+}
+
+func TestVAST_URLs_fixtures(t *testing.T) {
+	for _, testCase := range iabFixtures {
+		t.Run(testCase, func(t *testing.T) {
+			for _, u := range mustReadFixture(t, testCase).URLs() {
+				if u.Value == "" || u.Category == "" || u.Path == "" {
+					t.Errorf("unexpected URL: %v", u)
+				}
+			}
+		})
+	}
+}
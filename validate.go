@@ -0,0 +1,480 @@
+package vast
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type Severity string
+
+const (
+	ErrorSeverity   Severity = "error"
+	WarningSeverity Severity = "warning"
+)
+
+// Rule describes a check performed by Validate.
+type Rule struct {
+	ID          string
+	Description string
+}
+
+var (
+	VersionRule           = Rule{"version", "The VAST version must be declared and should be supported."}
+	AdDefinitionRule      = Rule{"ad-definition", "An Ad must contain exactly one InLine or Wrapper element."}
+	RequiredElementRule   = Rule{"required-element", "Elements required by the specification must be present and not empty."}
+	RequiredAttributeRule = Rule{"required-attribute", "Attributes required by the specification must be present and not empty."}
+	EnumerationRule       = Rule{"enumeration", "Values must be one of the values enumerated by the specification."}
+	FormatRule            = Rule{"format", "Values must match the format defined by the specification."}
+	URLRule               = Rule{"url", "URIs should be absolute HTTP or HTTPS URLs."}
+	UniversalAdIDRule     = Rule{"universal-ad-id", "Creatives of InLine ads must contain a UniversalAdId since VAST 4.0."}
+)
+
+// Rules contains all rules checked by Validate.
+var Rules = []Rule{
+	VersionRule,
+	AdDefinitionRule,
+	RequiredElementRule,
+	RequiredAttributeRule,
+	EnumerationRule,
+	FormatRule,
+	URLRule,
+	UniversalAdIDRule,
+}
+
+// Violation describes a deviation from the VAST specification.
+// Path is an XPath-like expression of the affected node, e.g. `/VAST/Ad[1]/InLine/AdTitle`.
+// Repeated elements are indexed by their position, starting at 1.
+type Violation struct {
+	Rule     string
+	Severity Severity
+	Path     string
+	Message  string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", v.Path, v.Severity, v.Message, v.Rule)
+}
+
+var (
+	durationPattern  = regexp.MustCompile(`^\d{2}:[0-5]\d:[0-5]\d(\.\d{3})?$`)
+	offsetPattern    = regexp.MustCompile(`^(\d{2}:[0-5]\d:[0-5]\d(\.\d\d\d)?|1?\d?\d(\.?\d)*%)$`)
+	currencyPattern  = regexp.MustCompile(`^[a-zA-Z]{3}$`)
+	xPositionPattern = regexp.MustCompile(`^([0-9]*|left|right)$`)
+	yPositionPattern = regexp.MustCompile(`^([0-9]*|top|bottom)$`)
+)
+
+var knownVersions = map[Version]bool{
+	VAST20Version: true,
+	VAST30Version: true,
+	VAST40Version: true,
+	VAST41Version: true,
+	VAST42Version: true,
+	VAST43Version: true,
+}
+
+var knownEvents = map[string]bool{
+	string(MuteEvent): true, string(UnmuteEvent): true, string(PauseEvent): true, string(ResumeEvent): true,
+	string(RewindEvent): true, string(SkipEvent): true, string(PlayerExpandEvent): true, string(PlayerCollapseEvent): true,
+	string(LoadedEvent): true, string(StartEvent): true, string(FirstQuartileEvent): true, string(MidpointEvent): true,
+	string(ThirdQuartileEvent): true, string(CompleteEvent): true, string(ProgressEvent): true, string(CloseLinearEvent): true,
+	string(CreativeViewEvent): true, string(AcceptInvitationEvent): true, string(AdExpandEvent): true,
+	string(AdCollapseEvent): true, string(MinimizeEvent): true, string(CloseEvent): true,
+	string(OverlayViewDurationEvent): true, string(OtherAdInteraction): true, string(InteractiveStart): true,
+	string(VerificationNotExecuted): true,
+}
+
+// Validate checks the VAST against the rules of the specification and returns all violations.
+// It returns nil if the VAST is valid.
+func (m *VAST) Validate() []Violation {
+	v := &validator{version: m.Version}
+	v.validateVAST("/VAST", m)
+
+	return v.violations
+}
+
+type validator struct {
+	version    Version
+	violations []Violation
+}
+
+func (v *validator) report(rule Rule, severity Severity, path, format string, args ...any) {
+	v.violations = append(v.violations, Violation{
+		Rule:     rule.ID,
+		Severity: severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) requireElement(path, name, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.report(RequiredElementRule, ErrorSeverity, path+"/"+name, "missing required element %s", name)
+	}
+}
+
+func (v *validator) requireAttribute(path, name, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.report(RequiredAttributeRule, ErrorSeverity, path+"/@"+name, "missing required attribute %s", name)
+	}
+}
+
+func (v *validator) requireDimension(path, name string, value int) {
+	if value <= 0 {
+		v.report(RequiredAttributeRule, ErrorSeverity, path+"/@"+name, "missing required attribute %s", name)
+	}
+}
+
+func (v *validator) checkEnumeration(path, name, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+
+	for _, candidate := range allowed {
+		if strings.EqualFold(value, candidate) {
+			return
+		}
+	}
+
+	v.report(EnumerationRule, ErrorSeverity, path, "invalid %s %q, expected one of %s", name, value, strings.Join(allowed, ", "))
+}
+
+func (v *validator) checkFormat(path, name, value string, pattern *regexp.Regexp) {
+	if value != "" && !pattern.MatchString(strings.TrimSpace(value)) {
+		v.report(FormatRule, ErrorSeverity, path, "invalid %s %q", name, value)
+	}
+}
+
+func (v *validator) checkURL(path, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		v.report(URLRule, WarningSeverity, path, "invalid URL %q", value)
+	}
+}
+
+func indexed(path, name string, i int) string {
+	return fmt.Sprintf("%s/%s[%d]", path, name, i+1)
+}
+
+func (v *validator) validateVAST(path string, m *VAST) {
+	switch {
+	case m.Version == "":
+		v.report(VersionRule, ErrorSeverity, path+"/@version", "missing required attribute version")
+	case !knownVersions[m.Version]:
+		v.report(VersionRule, WarningSeverity, path+"/@version", "unsupported version %q", m.Version)
+	}
+
+	for i, errorURI := range m.Error {
+		v.checkURL(indexed(path, "Error", i), errorURI.Value)
+	}
+
+	for i := range m.Ad {
+		v.validateAd(indexed(path, "Ad", i), &m.Ad[i])
+	}
+}
+
+func (v *validator) validateAd(path string, ad *Ad) {
+	switch {
+	case ad.InLine != nil && ad.Wrapper != nil:
+		v.report(AdDefinitionRule, ErrorSeverity, path, "ad contains both InLine and Wrapper")
+	case ad.InLine == nil && ad.Wrapper == nil:
+		v.report(AdDefinitionRule, ErrorSeverity, path, "ad contains neither InLine nor Wrapper")
+	}
+
+	v.checkEnumeration(path+"/@adType", "adType", string(ad.AdType), string(VideoAdType), string(AudioAdType), string(HybridAdType))
+
+	if ad.InLine != nil {
+		v.validateInLine(path+"/InLine", ad.InLine)
+	}
+
+	if ad.Wrapper != nil {
+		v.validateWrapper(path+"/Wrapper", ad.Wrapper)
+	}
+}
+
+func (v *validator) validateAdDefinitionBase(path string, base *AdDefinitionBase) {
+	v.requireElement(path, "AdSystem", base.AdSystem.Value)
+
+	if len(base.Impression) == 0 {
+		v.requireElement(path, "Impression", "")
+	}
+
+	for i, impression := range base.Impression {
+		v.checkURL(indexed(path, "Impression", i), impression.Value)
+	}
+
+	for i, errorURI := range base.Error {
+		v.checkURL(indexed(path, "Error", i), errorURI.Value)
+	}
+
+	if base.Pricing != nil {
+		pricingPath := path + "/Pricing"
+		v.requireAttribute(pricingPath, "model", string(base.Pricing.Model))
+		v.requireAttribute(pricingPath, "currency", string(base.Pricing.Currency))
+		v.checkEnumeration(pricingPath+"/@model", "model", string(base.Pricing.Model), string(CPCModel), string(CPMModel), string(CPEModel), string(CPVModel))
+		v.checkFormat(pricingPath+"/@currency", "currency", string(base.Pricing.Currency), currencyPattern)
+
+		if currency := base.Pricing.Currency; currencyPattern.MatchString(strings.TrimSpace(string(currency))) && !currency.Valid() {
+			v.report(EnumerationRule, WarningSeverity, pricingPath+"/@currency", "unknown ISO 4217 currency %q", currency)
+		}
+	}
+
+	if base.ViewableImpression != nil {
+		viewablePath := path + "/ViewableImpression"
+		for i, uri := range base.ViewableImpression.Viewable {
+			v.checkURL(indexed(viewablePath, "Viewable", i), uri.Value)
+		}
+
+		for i, uri := range base.ViewableImpression.NotViewable {
+			v.checkURL(indexed(viewablePath, "NotViewable", i), uri.Value)
+		}
+
+		for i, uri := range base.ViewableImpression.ViewUndetermined {
+			v.checkURL(indexed(viewablePath, "ViewUndetermined", i), uri.Value)
+		}
+	}
+}
+
+func (v *validator) validateInLine(path string, inLine *InLine) {
+	v.validateAdDefinitionBase(path, &inLine.AdDefinitionBase)
+	v.requireElement(path, "AdTitle", inLine.AdTitle)
+
+	if v.version.Compare(VAST40Version) >= 0 {
+		v.requireElement(path, "AdServingId", inLine.AdServingID)
+	}
+
+	for i, category := range inLine.Category {
+		v.requireAttribute(indexed(path, "Category", i), "authority", category.Authority)
+	}
+
+	if inLine.AdVerifications != nil {
+		v.validateAdVerifications(path+"/AdVerifications", inLine.AdVerifications)
+	}
+
+	if len(inLine.Creatives.Creative) == 0 {
+		v.requireElement(path+"/Creatives", "Creative", "")
+	}
+
+	for i := range inLine.Creatives.Creative {
+		v.validateInLineCreative(indexed(path+"/Creatives", "Creative", i), &inLine.Creatives.Creative[i])
+	}
+}
+
+func (v *validator) validateWrapper(path string, wrapper *Wrapper) {
+	v.validateAdDefinitionBase(path, &wrapper.AdDefinitionBase)
+	v.requireElement(path, "VASTAdTagURI", wrapper.VASTAdTagURI.Value)
+	v.checkURL(path+"/VASTAdTagURI", wrapper.VASTAdTagURI.Value)
+
+	if wrapper.AdVerifications != nil {
+		v.validateAdVerifications(path+"/AdVerifications", wrapper.AdVerifications)
+	}
+
+	if wrapper.Creatives == nil {
+		return
+	}
+
+	for i, creative := range wrapper.Creatives.Creative {
+		creativePath := indexed(path+"/Creatives", "Creative", i)
+
+		if creative.Linear != nil {
+			v.validateLinearBase(creativePath+"/Linear", &creative.Linear.LinearBase)
+			v.validateVideoClicks(creativePath+"/Linear/VideoClicks", creative.Linear.VideoClicks)
+		}
+
+		if creative.NonLinearAds != nil {
+			v.validateNonLinearAds(creativePath+"/NonLinearAds", creative.NonLinearAds)
+		}
+
+		if creative.CompanionAds != nil {
+			v.validateCompanionAds(creativePath+"/CompanionAds", creative.CompanionAds)
+		}
+	}
+}
+
+func (v *validator) validateAdVerifications(path string, adVerifications *AdVerifications) {
+	for i, verification := range adVerifications.Verification {
+		verificationPath := indexed(path, "Verification", i)
+
+		for j, resource := range verification.JavaScriptResource {
+			v.checkURL(indexed(verificationPath, "JavaScriptResource", j), resource.Value)
+		}
+
+		if verification.TrackingEvents != nil {
+			v.validateTracking(verificationPath+"/TrackingEvents", verification.TrackingEvents.Tracking)
+		}
+	}
+}
+
+func (v *validator) validateInLineCreative(path string, creative *InLineCreative) {
+	if v.version.Compare(VAST40Version) >= 0 && len(creative.UniversalAdID) == 0 {
+		v.report(UniversalAdIDRule, ErrorSeverity, path+"/UniversalAdId", "missing required element UniversalAdId")
+	}
+
+	for i, universalAdID := range creative.UniversalAdID {
+		universalAdIDPath := indexed(path, "UniversalAdId", i)
+		v.requireAttribute(universalAdIDPath, "idRegistry", universalAdID.IDRegistry)
+
+		if strings.TrimSpace(universalAdID.Value) == "" {
+			v.report(RequiredElementRule, ErrorSeverity, universalAdIDPath, "missing value of UniversalAdId")
+		}
+	}
+
+	if creative.Linear != nil {
+		v.validateLinearInLine(path+"/Linear", creative.Linear)
+	}
+
+	if creative.NonLinearAds != nil {
+		v.validateNonLinearAds(path+"/NonLinearAds", creative.NonLinearAds)
+	}
+
+	if creative.CompanionAds != nil {
+		v.validateCompanionAds(path+"/CompanionAds", creative.CompanionAds)
+	}
+}
+
+func (v *validator) validateLinearBase(path string, linear *LinearBase) {
+	v.checkFormat(path+"/@skipoffset", "skipoffset", string(linear.SkipOffset), offsetPattern)
+
+	if linear.TrackingEvents != nil {
+		v.validateTracking(path+"/TrackingEvents", linear.TrackingEvents.Tracking)
+	}
+
+	if linear.Icons == nil {
+		return
+	}
+
+	for i, icon := range linear.Icons.Icon {
+		iconPath := indexed(path+"/Icons", "Icon", i)
+		v.checkFormat(iconPath+"/@xPosition", "xPosition", string(icon.XPosition), xPositionPattern)
+		v.checkFormat(iconPath+"/@yPosition", "yPosition", string(icon.YPosition), yPositionPattern)
+		v.checkFormat(iconPath+"/@duration", "duration", string(icon.Duration), durationPattern)
+		v.checkFormat(iconPath+"/@offset", "offset", string(icon.Offset), durationPattern)
+		v.validateStaticResources(iconPath, icon.StaticResource)
+	}
+}
+
+func (v *validator) validateLinearInLine(path string, linear *LinearInLine) {
+	v.validateLinearBase(path, &linear.LinearBase)
+	v.requireElement(path, "Duration", string(linear.Duration))
+	v.checkFormat(path+"/Duration", "duration", string(linear.Duration), durationPattern)
+	v.validateVideoClicks(path+"/VideoClicks", linear.VideoClicks)
+
+	mediaFilesPath := path + "/MediaFiles"
+	if len(linear.MediaFiles.MediaFile) == 0 {
+		v.requireElement(mediaFilesPath, "MediaFile", "")
+	}
+
+	for i, mediaFile := range linear.MediaFiles.MediaFile {
+		mediaFilePath := indexed(mediaFilesPath, "MediaFile", i)
+
+		v.requireAttribute(mediaFilePath, "delivery", string(mediaFile.Delivery))
+		v.checkEnumeration(mediaFilePath+"/@delivery", "delivery", string(mediaFile.Delivery), string(StreamingDelivery), string(ProgressiveDelivery))
+		v.requireAttribute(mediaFilePath, "type", mediaFile.Type)
+		v.requireDimension(mediaFilePath, "width", mediaFile.Width)
+		v.requireDimension(mediaFilePath, "height", mediaFile.Height)
+
+		if strings.TrimSpace(mediaFile.Value) == "" {
+			v.report(RequiredElementRule, ErrorSeverity, mediaFilePath, "missing URI of MediaFile")
+		}
+
+		v.checkURL(mediaFilePath, mediaFile.Value)
+	}
+
+	for i, mezzanine := range linear.MediaFiles.Mezzanine {
+		mezzaninePath := indexed(mediaFilesPath, "Mezzanine", i)
+
+		v.requireAttribute(mezzaninePath, "delivery", string(mezzanine.Delivery))
+		v.checkEnumeration(mezzaninePath+"/@delivery", "delivery", string(mezzanine.Delivery), string(StreamingDelivery), string(ProgressiveDelivery))
+		v.requireAttribute(mezzaninePath, "type", mezzanine.Type)
+		v.requireDimension(mezzaninePath, "width", mezzanine.Width)
+		v.requireDimension(mezzaninePath, "height", mezzanine.Height)
+		v.checkURL(mezzaninePath, mezzanine.Value)
+	}
+
+	if linear.MediaFiles.ClosedCaptionFiles != nil {
+		for i, file := range linear.MediaFiles.ClosedCaptionFiles.ClosedCaptionFile {
+			filePath := indexed(mediaFilesPath+"/ClosedCaptionFiles", "ClosedCaptionFile", i)
+
+			if file.Language != "" && !ValidLanguage(file.Language) {
+				v.report(FormatRule, WarningSeverity, filePath+"/@language", "invalid BCP 47 language %q", file.Language)
+			}
+
+			v.checkURL(filePath, file.Value)
+		}
+	}
+}
+
+func (v *validator) validateVideoClicks(path string, videoClicks *VideoClicks) {
+	if videoClicks == nil {
+		return
+	}
+
+	v.checkURL(path+"/ClickThrough", videoClicks.ClickThrough.Value)
+
+	for i, clickTracking := range videoClicks.ClickTracking {
+		v.checkURL(indexed(path, "ClickTracking", i), clickTracking.Value)
+	}
+}
+
+func (v *validator) validateTracking(path string, tracking []Tracking) {
+	for i, event := range tracking {
+		trackingPath := indexed(path, "Tracking", i)
+
+		v.requireAttribute(trackingPath, "event", event.Event)
+
+		if event.Event != "" && !knownEvents[event.Event] {
+			v.report(EnumerationRule, WarningSeverity, trackingPath+"/@event", "unknown event %q", event.Event)
+		}
+
+		v.checkFormat(trackingPath+"/@offset", "offset", string(event.Offset), offsetPattern)
+		v.checkURL(trackingPath, event.Value)
+	}
+}
+
+func (v *validator) validateNonLinearAds(path string, nonLinearAds *NonLinearAds) {
+	if nonLinearAds.TrackingEvents != nil {
+		v.validateTracking(path+"/TrackingEvents", nonLinearAds.TrackingEvents.Tracking)
+	}
+
+	for i, nonLinear := range nonLinearAds.NonLinear {
+		nonLinearPath := indexed(path, "NonLinear", i)
+
+		v.requireDimension(nonLinearPath, "width", nonLinear.Width)
+		v.requireDimension(nonLinearPath, "height", nonLinear.Height)
+		v.checkFormat(nonLinearPath+"/@minSuggestedDuration", "minSuggestedDuration", string(nonLinear.MinSuggestedDuration), durationPattern)
+		v.validateStaticResources(nonLinearPath, nonLinear.StaticResource)
+	}
+}
+
+func (v *validator) validateCompanionAds(path string, companionAds *CompanionAdsCollection) {
+	v.checkEnumeration(path+"/@required", "required", string(companionAds.Required), string(AllRequired), string(AnyRequired), string(NoneRequired))
+
+	for i, companion := range companionAds.Companion {
+		companionPath := indexed(path, "Companion", i)
+
+		v.requireDimension(companionPath, "width", companion.Width)
+		v.requireDimension(companionPath, "height", companion.Height)
+		v.checkEnumeration(companionPath+"/@renderingMode", "renderingMode", string(companion.RenderingMode),
+			string(DefaultRenderingMode), string(EndCardRenderingMode), string(ConcurrentRenderingMode))
+		v.validateStaticResources(companionPath, companion.StaticResource)
+
+		if companion.TrackingEvents != nil {
+			v.validateTracking(companionPath+"/TrackingEvents", companion.TrackingEvents.Tracking)
+		}
+	}
+}
+
+func (v *validator) validateStaticResources(path string, staticResources []StaticResource) {
+	for i, staticResource := range staticResources {
+		staticResourcePath := indexed(path, "StaticResource", i)
+
+		v.requireAttribute(staticResourcePath, "creativeType", staticResource.CreativeType)
+		v.checkURL(staticResourcePath, staticResource.Value)
+	}
+}
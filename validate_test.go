@@ -0,0 +1,108 @@
+package vast_test
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"testing"
+)
+
+func TestVAST_Validate_valid(t *testing.T) {
+	for _, testCase := range iabFixtures {
+		t.Run(testCase, func(t *testing.T) {
+			if violations := mustReadFixture(t, testCase).Validate(); violations != nil {
+				t.Errorf("unexpected violations: %v", violations)
+			}
+		})
+	}
+}
+
+func TestVAST_Validate_invalid(t *testing.T) {
+	got := mustReadFixture(t, "invalid.xml").Validate()
+	want := []vast.Violation{
+		{Rule: "enumeration", Severity: "error", Path: "/VAST/Ad[1]/@adType", Message: `invalid adType "movie", expected one of video, audio, hybrid`},
+		{Rule: "enumeration", Severity: "error", Path: "/VAST/Ad[1]/InLine/Pricing/@model", Message: `invalid model "cpx", expected one of CPC, CPM, CPE, CPV`},
+		{Rule: "format", Severity: "error", Path: "/VAST/Ad[1]/InLine/Pricing/@currency", Message: `invalid currency "EURO"`},
+		{Rule: "required-element", Severity: "error", Path: "/VAST/Ad[1]/InLine/AdTitle", Message: "missing required element AdTitle"},
+		{Rule: "required-attribute", Severity: "error", Path: "/VAST/Ad[1]/InLine/Category[1]/@authority", Message: "missing required attribute authority"},
+		{Rule: "universal-ad-id", Severity: "error", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/UniversalAdId", Message: "missing required element UniversalAdId"},
+		{Rule: "format", Severity: "error", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/@skipoffset", Message: `invalid skipoffset "5s"`},
+		{Rule: "enumeration", Severity: "warning", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/TrackingEvents/Tracking[1]/@event", Message: `unknown event "begin"`},
+		{Rule: "format", Severity: "error", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/TrackingEvents/Tracking[2]/@offset", Message: `invalid offset "later"`},
+		{Rule: "url", Severity: "warning", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/TrackingEvents/Tracking[2]", Message: `invalid URL "example.com/tracking/progress"`},
+		{Rule: "format", Severity: "error", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/Duration", Message: `invalid duration "16"`},
+		{Rule: "enumeration", Severity: "error", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/MediaFiles/MediaFile[1]/@delivery", Message: `invalid delivery "download", expected one of streaming, progressive`},
+		{Rule: "required-attribute", Severity: "error", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/MediaFiles/MediaFile[1]/@height", Message: "missing required attribute height"},
+		{Rule: "format", Severity: "warning", Path: "/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/MediaFiles/ClosedCaptionFiles/ClosedCaptionFile[1]/@language", Message: `invalid BCP 47 language "english"`},
+		{Rule: "ad-definition", Severity: "error", Path: "/VAST/Ad[2]", Message: "ad contains neither InLine nor Wrapper"},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong violations: %s", diff)
+	}
+}
+
+func TestVAST_Validate_wrapper(t *testing.T) {
+	testVAST := &vast.VAST{
+		Version: "5.0",
+		Ad: []vast.Ad{
+			{
+				InLine: &vast.InLine{},
+				Wrapper: &vast.Wrapper{
+					AdDefinitionBase: vast.AdDefinitionBase{
+						AdSystem:   vast.AdSystem{Value: "example"},
+						Impression: []vast.Impression{{Value: "https://example.com/impression"}},
+						Pricing:    &vast.Pricing{Value: 1, Model: vast.CPMModel, Currency: "XYZ"},
+					},
+				},
+			},
+		},
+	}
+
+	got := testVAST.Validate()
+	want := []vast.Violation{
+		{Rule: "version", Severity: "warning", Path: "/VAST/@version", Message: `unsupported version "5.0"`},
+		{Rule: "ad-definition", Severity: "error", Path: "/VAST/Ad[1]", Message: "ad contains both InLine and Wrapper"},
+		{Rule: "required-element", Severity: "error", Path: "/VAST/Ad[1]/InLine/AdSystem", Message: "missing required element AdSystem"},
+		{Rule: "required-element", Severity: "error", Path: "/VAST/Ad[1]/InLine/Impression", Message: "missing required element Impression"},
+		{Rule: "required-element", Severity: "error", Path: "/VAST/Ad[1]/InLine/AdTitle", Message: "missing required element AdTitle"},
+		{Rule: "required-element", Severity: "error", Path: "/VAST/Ad[1]/InLine/AdServingId", Message: "missing required element AdServingId"},
+		{Rule: "required-element", Severity: "error", Path: "/VAST/Ad[1]/InLine/Creatives/Creative", Message: "missing required element Creative"},
+		{Rule: "enumeration", Severity: "warning", Path: "/VAST/Ad[1]/Wrapper/Pricing/@currency", Message: `unknown ISO 4217 currency "XYZ"`},
+		{Rule: "required-element", Severity: "error", Path: "/VAST/Ad[1]/Wrapper/VASTAdTagURI", Message: "missing required element VASTAdTagURI"},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong violations: %s", diff)
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	testCases := []struct {
+		a, b vast.Version
+		want int
+	}{
+		{vast.VAST40Version, vast.VAST40Version, 0},
+		{"4", vast.VAST40Version, 0},
+		{" 4.0 ", vast.VAST40Version, 0},
+		{vast.VAST30Version, "4", -1},
+		{"4.10", vast.VAST42Version, 1},
+		{"10.0", vast.VAST43Version, 1},
+		{"", vast.VAST20Version, -1},
+		{vast.VAST20Version, "4.x", 1},
+		{"x", "", 0},
+	}
+
+	for _, testCase := range testCases {
+		if got := testCase.a.Compare(testCase.b); got != testCase.want {
+			t.Errorf("%q.Compare(%q) = %d, want %d", testCase.a, testCase.b, got, testCase.want)
+		}
+	}
+}
+
+func TestViolation_String(t *testing.T) {
+	violation := vast.Violation{Rule: "version", Severity: vast.ErrorSeverity, Path: "/VAST/@version", Message: "missing required attribute version"}
+
+	if got := violation.String(); got != "/VAST/@version: error: missing required attribute version [version]" {
+		t.Errorf("unexpected result: %s", got)
+	}
+}
@@ -3,9 +3,12 @@
 package vast
 
 import (
+	"cmp"
 	"encoding/xml"
 	"errors"
 	"io"
+	"strconv"
+	"strings"
 )
 
 type NumericBool bool
@@ -22,36 +25,36 @@ type CData struct {
 }
 
 type Ad struct {
-	InLine        *InLine     `xml:"InLine,omitempty"`
-	Wrapper       *Wrapper    `xml:"Wrapper,omitempty"`
-	ID            string      `xml:"id,attr,omitempty"`
-	Sequence      int         `xml:"sequence,attr,omitempty"`
-	ConditionalAd NumericBool `xml:"conditionalAd,attr,omitempty"`
-	AdType        AdType      `xml:"adType,attr,omitempty"`
+	InLine        *InLine     `xml:"InLine,omitempty" json:"inLine,omitempty"`
+	Wrapper       *Wrapper    `xml:"Wrapper,omitempty" json:"wrapper,omitempty"`
+	ID            string      `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Sequence      int         `xml:"sequence,attr,omitempty" json:"sequence,omitempty"`
+	ConditionalAd NumericBool `xml:"conditionalAd,attr,omitempty" json:"conditionalAd,omitempty"`
+	AdType        AdType      `xml:"adType,attr,omitempty" json:"adType,omitempty"`
 }
 
 type Impression struct {
-	Value string `xml:",cdata"`
-	ID    string `xml:"id,attr,omitempty"`
+	Value string `xml:",cdata" json:"value"`
+	ID    string `xml:"id,attr,omitempty" json:"id,omitempty"`
 }
 
 type AdDefinitionBase struct {
-	AdSystem           AdSystem            `xml:"AdSystem"`
-	Error              []CData             `xml:"Error,omitempty"`
-	Extensions         *Extensions         `xml:"Extensions,omitempty"`
-	Impression         []Impression        `xml:"Impression"`
-	Pricing            *Pricing            `xml:"Pricing,omitempty"`
-	ViewableImpression *ViewableImpression `xml:"ViewableImpression,omitempty"`
+	AdSystem           AdSystem            `xml:"AdSystem" json:"adSystem"`
+	Error              []CData             `xml:"Error,omitempty" json:"error,omitempty"`
+	Extensions         *Extensions         `xml:"Extensions,omitempty" json:"extensions,omitempty"`
+	Impression         []Impression        `xml:"Impression" json:"impression"`
+	Pricing            *Pricing            `xml:"Pricing,omitempty" json:"pricing,omitempty"`
+	ViewableImpression *ViewableImpression `xml:"ViewableImpression,omitempty" json:"viewableImpression,omitempty"`
 }
 
 type AdParameters struct {
-	Value      string      `xml:",chardata"`
-	XMLEncoded NumericBool `xml:"xmlEncoded,attr,omitempty"`
+	Value      string      `xml:",chardata" json:"value"`
+	XMLEncoded NumericBool `xml:"xmlEncoded,attr,omitempty" json:"xmlEncoded,omitempty"`
 }
 
 type AdSystem struct {
-	Value   string `xml:",chardata"`
-	Version string `xml:"version,attr,omitempty"`
+	Value   string `xml:",chardata" json:"value"`
+	Version string `xml:"version,attr,omitempty" json:"version,omitempty"`
 }
 
 type AdType string
@@ -63,104 +66,105 @@ const (
 )
 
 type AdVerifications struct {
-	Verification []Verification `xml:"Verification,omitempty"`
+	Verification []Verification `xml:"Verification,omitempty" json:"verification,omitempty"`
 }
 
 type InLineCreatives struct {
-	Creative []InLineCreative `xml:"Creative"`
+	Creative []InLineCreative `xml:"Creative" json:"creative"`
 }
 
 type BlockedAdCategories struct {
-	Value     string `xml:",chardata"`
-	Authority string `xml:"authority,attr,omitempty"`
+	Value     string `xml:",chardata" json:"value"`
+	Authority string `xml:"authority,attr,omitempty" json:"authority,omitempty"`
 }
 
 type Category struct {
-	Value     string `xml:",chardata"`
-	Authority string `xml:"authority,attr"`
+	Value     string `xml:",chardata" json:"value"`
+	Authority string `xml:"authority,attr" json:"authority"`
 }
 
 type ClosedCaptionFile struct {
-	Value    string `xml:",cdata"`
-	Type     string `xml:"type,attr,omitempty"`
-	Language string `xml:"language,attr,omitempty"`
+	Value    string `xml:",cdata" json:"value"`
+	Type     string `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Language string `xml:"language,attr,omitempty" json:"language,omitempty"`
 }
 
 type ClosedCaptionFiles struct {
-	ClosedCaptionFile []ClosedCaptionFile `xml:"ClosedCaptionFile,omitempty"`
+	ClosedCaptionFile []ClosedCaptionFile `xml:"ClosedCaptionFile,omitempty" json:"closedCaptionFile,omitempty"`
 }
 
 type CompanionAdsCollection struct {
-	Companion []CompanionAd `xml:"Companion,omitempty"`
-	Required  Required      `xml:"required,attr,omitempty"`
+	Companion []CompanionAd `xml:"Companion,omitempty" json:"companion,omitempty"`
+	Required  Required      `xml:"required,attr,omitempty" json:"required,omitempty"`
 }
 
 type CompanionAd struct {
-	HTMLResource           []CData             `xml:"HTMLResource,omitempty"`
-	IFrameResource         []CData             `xml:"IFrameResource,omitempty"`
-	StaticResource         []StaticResource    `xml:"StaticResource,omitempty"`
-	AdParameters           *AdParameters       `xml:"AdParameters,omitempty"`
-	AltText                string              `xml:"AltText,omitempty"`
-	CompanionClickThrough  *CData              `xml:"CompanionClickThrough,omitempty"`
-	CompanionClickTracking []string            `xml:"CompanionClickTracking,omitempty"`
-	CreativeExtensions     *CreativeExtensions `xml:"CreativeExtensions,omitempty"`
-	TrackingEvents         *TrackingEvents     `xml:"TrackingEvents,omitempty"`
-	ID                     string              `xml:"id,attr,omitempty"`
-	Width                  int                 `xml:"width,attr"`
-	Height                 int                 `xml:"height,attr"`
-	AssetWidth             int                 `xml:"assetWidth,attr,omitempty"`
-	AssetHeight            int                 `xml:"assetHeight,attr,omitempty"`
-	ExpandedWidth          int                 `xml:"expandedWidth,attr,omitempty"`
-	ExpandedHeight         int                 `xml:"expandedHeight,attr,omitempty"`
-	APIFramework           string              `xml:"apiFramework,attr,omitempty"`
-	AdSlotID               string              `xml:"adSlotId,attr,omitempty"`
-	PXRatio                float64             `xml:"pxratio,attr,omitempty"`
-	RenderingMode          RenderingMode       `xml:"renderingMode,attr,omitempty"`
+	HTMLResource           []CData             `xml:"HTMLResource,omitempty" json:"htmlResource,omitempty"`
+	IFrameResource         []CData             `xml:"IFrameResource,omitempty" json:"iFrameResource,omitempty"`
+	StaticResource         []StaticResource    `xml:"StaticResource,omitempty" json:"staticResource,omitempty"`
+	AdParameters           *AdParameters       `xml:"AdParameters,omitempty" json:"adParameters,omitempty"`
+	AltText                string              `xml:"AltText,omitempty" json:"altText,omitempty"`
+	CompanionClickThrough  *CData              `xml:"CompanionClickThrough,omitempty" json:"companionClickThrough,omitempty"`
+	CompanionClickTracking []string            `xml:"CompanionClickTracking,omitempty" json:"companionClickTracking,omitempty"`
+	CreativeExtensions     *CreativeExtensions `xml:"CreativeExtensions,omitempty" json:"creativeExtensions,omitempty"`
+	TrackingEvents         *TrackingEvents     `xml:"TrackingEvents,omitempty" json:"trackingEvents,omitempty"`
+	ID                     string              `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Width                  int                 `xml:"width,attr" json:"width"`
+	Height                 int                 `xml:"height,attr" json:"height"`
+	AssetWidth             int                 `xml:"assetWidth,attr,omitempty" json:"assetWidth,omitempty"`
+	AssetHeight            int                 `xml:"assetHeight,attr,omitempty" json:"assetHeight,omitempty"`
+	ExpandedWidth          int                 `xml:"expandedWidth,attr,omitempty" json:"expandedWidth,omitempty"`
+	ExpandedHeight         int                 `xml:"expandedHeight,attr,omitempty" json:"expandedHeight,omitempty"`
+	APIFramework           string              `xml:"apiFramework,attr,omitempty" json:"apiFramework,omitempty"`
+	AdSlotID               string              `xml:"adSlotId,attr,omitempty" json:"adSlotId,omitempty"`
+	PXRatio                float64             `xml:"pxratio,attr,omitempty" json:"pxratio,omitempty"`
+	RenderingMode          RenderingMode       `xml:"renderingMode,attr,omitempty" json:"renderingMode,omitempty"`
 }
 
 type CreativeBase struct {
-	Sequence     int    `xml:"sequence,attr,omitempty"`
-	APIFramework string `xml:"apiFramework,attr,omitempty"`
-	AdID         string `xml:"adId,attr,omitempty"`
+	Sequence     int    `xml:"sequence,attr,omitempty" json:"sequence,omitempty"`
+	APIFramework string `xml:"apiFramework,attr,omitempty" json:"apiFramework,omitempty"`
+	AdID         string `xml:"adId,attr,omitempty" json:"adId,omitempty"`
 }
 
 type CreativeExtension struct {
-	Items []string `xml:",any"`
-	Type  string   `xml:"type,attr,omitempty"`
+	Nodes      []Node     `xml:",any" json:"nodes,omitempty"`
+	Type       string     `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Attributes Attributes `xml:",any,attr" json:"attributes,omitempty"`
 }
 
 type CreativeExtensions struct {
-	CreativeExtension []CreativeExtension `xml:"CreativeExtension,omitempty"`
+	CreativeExtension []CreativeExtension `xml:"CreativeExtension,omitempty" json:"creativeExtension,omitempty"`
 }
 
 type InLineCreative struct {
 	CreativeBase
 
-	CompanionAds       *CompanionAdsCollection `xml:"CompanionAds,omitempty"`
-	CreativeExtensions *CreativeExtensions     `xml:"CreativeExtensions,omitempty"`
-	Linear             *LinearInLine           `xml:"Linear,omitempty"`
-	NonLinearAds       *NonLinearAds           `xml:"NonLinearAds,omitempty"`
-	UniversalAdID      []UniversalAdID         `xml:"UniversalAdId"`
-	ID                 string                  `xml:"id,attr,omitempty"`
+	CompanionAds       *CompanionAdsCollection `xml:"CompanionAds,omitempty" json:"companionAds,omitempty"`
+	CreativeExtensions *CreativeExtensions     `xml:"CreativeExtensions,omitempty" json:"creativeExtensions,omitempty"`
+	Linear             *LinearInLine           `xml:"Linear,omitempty" json:"linear,omitempty"`
+	NonLinearAds       *NonLinearAds           `xml:"NonLinearAds,omitempty" json:"nonLinearAds,omitempty"`
+	UniversalAdID      []UniversalAdID         `xml:"UniversalAdId" json:"universalAdId"`
+	ID                 string                  `xml:"id,attr,omitempty" json:"id,omitempty"`
 }
 
 type CreativeResource struct {
-	HTMLResource   []CData          `xml:"HTMLResource,omitempty"`
-	IFrameResource []CData          `xml:"IFrameResource,omitempty"`
-	StaticResource []StaticResource `xml:"StaticResource,omitempty"`
+	HTMLResource   []CData          `xml:"HTMLResource,omitempty" json:"htmlResource,omitempty"`
+	IFrameResource []CData          `xml:"IFrameResource,omitempty" json:"iFrameResource,omitempty"`
+	StaticResource []StaticResource `xml:"StaticResource,omitempty" json:"staticResource,omitempty"`
 }
 
 type WrapperCreative struct {
 	CreativeBase
 
-	CompanionAds *CompanionAdsCollection `xml:"CompanionAds,omitempty"`
-	Linear       *LinearWrapper          `xml:"Linear,omitempty"`
-	NonLinearAds *NonLinearAds           `xml:"NonLinearAds,omitempty"`
-	ID           string                  `xml:"id,attr,omitempty"`
+	CompanionAds *CompanionAdsCollection `xml:"CompanionAds,omitempty" json:"companionAds,omitempty"`
+	Linear       *LinearWrapper          `xml:"Linear,omitempty" json:"linear,omitempty"`
+	NonLinearAds *NonLinearAds           `xml:"NonLinearAds,omitempty" json:"nonLinearAds,omitempty"`
+	ID           string                  `xml:"id,attr,omitempty" json:"id,omitempty"`
 }
 
 type Creatives struct {
-	Creative []WrapperCreative `xml:"Creative"`
+	Creative []WrapperCreative `xml:"Creative" json:"creative"`
 }
 
 // Currency must match the pattern `[a-zA-Z]{3}`.
@@ -201,42 +205,44 @@ const (
 	OverlayViewDurationEvent Event = "overlayViewDuration"
 	OtherAdInteraction       Event = "otherAdInteraction"
 	InteractiveStart         Event = "interactiveStart"
+	VerificationNotExecuted  Event = "verificationNotExecuted"
 )
 
 type ExecutableResource struct {
-	Value        string `xml:",cdata"`
-	APIFramework string `xml:"apiFramework,attr,omitempty"`
-	Type         string `xml:"type,attr,omitempty"`
+	Value        string `xml:",cdata" json:"value"`
+	APIFramework string `xml:"apiFramework,attr,omitempty" json:"apiFramework,omitempty"`
+	Type         string `xml:"type,attr,omitempty" json:"type,omitempty"`
 }
 
 type Extension struct {
-	Value string `xml:",innerxml"`
-	Type  string `xml:"type,attr,omitempty"`
+	Nodes      []Node     `xml:",any" json:"nodes,omitempty"`
+	Type       string     `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Attributes Attributes `xml:",any,attr" json:"attributes,omitempty"`
 }
 
 type Extensions struct {
-	Extension []Extension `xml:"Extension,omitempty"`
+	Extension []Extension `xml:"Extension,omitempty" json:"extension,omitempty"`
 }
 
 type IconClickFallbackImage struct {
-	AltText        string `xml:"AltText,omitempty"`
-	StaticResource *CData `xml:"StaticResource,omitempty"`
-	Height         int    `xml:"height,attr,omitempty"`
-	Width          int    `xml:"width,attr,omitempty"`
+	AltText        string `xml:"AltText,omitempty" json:"altText,omitempty"`
+	StaticResource *CData `xml:"StaticResource,omitempty" json:"staticResource,omitempty"`
+	Height         int    `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width          int    `xml:"width,attr,omitempty" json:"width,omitempty"`
 }
 
 type IconClickFallbackImages struct {
-	IconClickFallbackImage []IconClickFallbackImage `xml:"IconClickFallbackImage"`
+	IconClickFallbackImage []IconClickFallbackImage `xml:"IconClickFallbackImage" json:"iconClickFallbackImage"`
 }
 
 type IconClicks struct {
-	IconClickFallbackImages *IconClickFallbackImages `xml:"IconClickFallbackImages,omitempty"`
-	IconClickThrough        string                   `xml:"IconClickThrough,omitempty"`
-	IconClickTracking       []string                 `xml:"IconClickTracking,omitempty"`
+	IconClickFallbackImages *IconClickFallbackImages `xml:"IconClickFallbackImages,omitempty" json:"iconClickFallbackImages,omitempty"`
+	IconClickThrough        string                   `xml:"IconClickThrough,omitempty" json:"iconClickThrough,omitempty"`
+	IconClickTracking       []string                 `xml:"IconClickTracking,omitempty" json:"iconClickTracking,omitempty"`
 }
 
 type Icons struct {
-	Icon []Icon `xml:"Icon"`
+	Icon []Icon `xml:"Icon" json:"icon"`
 }
 
 // Duration must be expressed in the standard time format `hh:mm:ss`.
@@ -245,104 +251,104 @@ type Duration string
 type Icon struct {
 	CreativeResource
 
-	HTMLResource     []CData          `xml:"HTMLResource,omitempty"`
-	IFrameResource   []CData          `xml:"IFrameResource,omitempty"`
-	StaticResource   []StaticResource `xml:"StaticResource,omitempty"`
-	IconClicks       *IconClicks      `xml:"IconClicks,omitempty"`
-	IconViewTracking []string         `xml:"IconViewTracking,omitempty"`
-	Program          string           `xml:"program,attr,omitempty"`
-	Width            int              `xml:"width,attr,omitempty"`
-	Height           int              `xml:"height,attr,omitempty"`
-	XPosition        XPosition        `xml:"xPosition,attr,omitempty"`
-	YPosition        YPosition        `xml:"yPosition,attr,omitempty"`
-	Duration         Duration         `xml:"duration,attr,omitempty"`
-	Offset           Duration         `xml:"offset,attr,omitempty"`
-	APIFramework     string           `xml:"apiFramework,attr,omitempty"`
-	PXRatio          float64          `xml:"pxratio,attr,omitempty"`
+	HTMLResource     []CData          `xml:"HTMLResource,omitempty" json:"htmlResource,omitempty"`
+	IFrameResource   []CData          `xml:"IFrameResource,omitempty" json:"iFrameResource,omitempty"`
+	StaticResource   []StaticResource `xml:"StaticResource,omitempty" json:"staticResource,omitempty"`
+	IconClicks       *IconClicks      `xml:"IconClicks,omitempty" json:"iconClicks,omitempty"`
+	IconViewTracking []string         `xml:"IconViewTracking,omitempty" json:"iconViewTracking,omitempty"`
+	Program          string           `xml:"program,attr,omitempty" json:"program,omitempty"`
+	Width            int              `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Height           int              `xml:"height,attr,omitempty" json:"height,omitempty"`
+	XPosition        XPosition        `xml:"xPosition,attr,omitempty" json:"xPosition,omitempty"`
+	YPosition        YPosition        `xml:"yPosition,attr,omitempty" json:"yPosition,omitempty"`
+	Duration         Duration         `xml:"duration,attr,omitempty" json:"duration,omitempty"`
+	Offset           Duration         `xml:"offset,attr,omitempty" json:"offset,omitempty"`
+	APIFramework     string           `xml:"apiFramework,attr,omitempty" json:"apiFramework,omitempty"`
+	PXRatio          float64          `xml:"pxratio,attr,omitempty" json:"pxratio,omitempty"`
 }
 
 type InLine struct {
 	AdDefinitionBase
 
-	AdServingID     string           `xml:"AdServingId"`
-	AdTitle         string           `xml:"AdTitle"`
-	AdVerifications *AdVerifications `xml:"AdVerifications,omitempty"`
-	Advertiser      string           `xml:"Advertiser,omitempty"`
-	Category        []Category       `xml:"Category,omitempty"`
-	Creatives       InLineCreatives  `xml:"Creatives"`
-	Description     *CData           `xml:"Description,omitempty"`
-	Expires         int              `xml:"Expires,omitempty"`
-	Survey          *Survey          `xml:"Survey,omitempty"`
+	AdServingID     string           `xml:"AdServingId" json:"adServingId"`
+	AdTitle         string           `xml:"AdTitle" json:"adTitle"`
+	AdVerifications *AdVerifications `xml:"AdVerifications,omitempty" json:"adVerifications,omitempty"`
+	Advertiser      string           `xml:"Advertiser,omitempty" json:"advertiser,omitempty"`
+	Category        []Category       `xml:"Category,omitempty" json:"category,omitempty"`
+	Creatives       InLineCreatives  `xml:"Creatives" json:"creatives"`
+	Description     *CData           `xml:"Description,omitempty" json:"description,omitempty"`
+	Expires         int              `xml:"Expires,omitempty" json:"expires,omitempty"`
+	Survey          *Survey          `xml:"Survey,omitempty" json:"survey,omitempty"`
 }
 
 type InteractiveCreativeFile struct {
-	Value            string      `xml:",cdata"`
-	Type             string      `xml:"type,attr,omitempty"`
-	APIFramework     string      `xml:"apiFramework,attr,omitempty"`
-	VariableDuration NumericBool `xml:"variableDuration,attr,omitempty"`
+	Value            string      `xml:",cdata" json:"value"`
+	Type             string      `xml:"type,attr,omitempty" json:"type,omitempty"`
+	APIFramework     string      `xml:"apiFramework,attr,omitempty" json:"apiFramework,omitempty"`
+	VariableDuration NumericBool `xml:"variableDuration,attr,omitempty" json:"variableDuration,omitempty"`
 }
 
 type JavaScriptResource struct {
-	Value           string      `xml:",cdata"`
-	APIFramework    string      `xml:"apiFramework,attr,omitempty"`
-	BrowserOptional NumericBool `xml:"browserOptional,attr,omitempty"`
+	Value           string      `xml:",cdata" json:"value"`
+	APIFramework    string      `xml:"apiFramework,attr,omitempty" json:"apiFramework,omitempty"`
+	BrowserOptional NumericBool `xml:"browserOptional,attr,omitempty" json:"browserOptional,omitempty"`
 }
 
 type LinearBase struct {
-	Icons          *Icons          `xml:"Icons,omitempty"`
-	TrackingEvents *TrackingEvents `xml:"TrackingEvents,omitempty"`
-	SkipOffset     SkipOffset      `xml:"skipoffset,attr,omitempty"`
+	Icons          *Icons          `xml:"Icons,omitempty" json:"icons,omitempty"`
+	TrackingEvents *TrackingEvents `xml:"TrackingEvents,omitempty" json:"trackingEvents,omitempty"`
+	SkipOffset     SkipOffset      `xml:"skipoffset,attr,omitempty" json:"skipoffset,omitempty"`
 }
 
 type LinearInLine struct {
 	LinearBase
 
-	AdParameters *AdParameters `xml:"AdParameters,omitempty"`
-	Duration     Duration      `xml:"Duration"`
-	MediaFiles   MediaFiles    `xml:"MediaFiles"`
-	VideoClicks  *VideoClicks  `xml:"VideoClicks,omitempty"`
+	AdParameters *AdParameters `xml:"AdParameters,omitempty" json:"adParameters,omitempty"`
+	Duration     Duration      `xml:"Duration" json:"duration"`
+	MediaFiles   MediaFiles    `xml:"MediaFiles" json:"mediaFiles"`
+	VideoClicks  *VideoClicks  `xml:"VideoClicks,omitempty" json:"videoClicks,omitempty"`
 }
 
 type LinearWrapper struct {
 	LinearBase
 
-	VideoClicks *VideoClicks `xml:"VideoClicks,omitempty"`
+	VideoClicks *VideoClicks `xml:"VideoClicks,omitempty" json:"videoClicks,omitempty"`
 }
 
 type MediaFile struct {
-	Value               string      `xml:",cdata"`
-	ID                  string      `xml:"id,attr,omitempty"`
-	Delivery            Delivery    `xml:"delivery,attr"`
-	Type                string      `xml:"type,attr"`
-	Width               int         `xml:"width,attr"`
-	Height              int         `xml:"height,attr"`
-	Codec               string      `xml:"codec,attr,omitempty"`
-	Bitrate             int         `xml:"bitrate,attr,omitempty"`
-	MinBitrate          int         `xml:"minBitrate,attr,omitempty"`
-	MaxBitrate          int         `xml:"maxBitrate,attr,omitempty"`
-	Scalable            NumericBool `xml:"scalable,attr,omitempty"`
-	MaintainAspectRatio NumericBool `xml:"maintainAspectRatio,attr,omitempty"`
-	FileSize            int         `xml:"fileSize,attr,omitempty"`
-	MediaType           string      `xml:"mediaType,attr,omitempty"`
-	APIFramework        string      `xml:"apiFramework,attr,omitempty"`
+	Value               string      `xml:",cdata" json:"value"`
+	ID                  string      `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Delivery            Delivery    `xml:"delivery,attr" json:"delivery"`
+	Type                string      `xml:"type,attr" json:"type"`
+	Width               int         `xml:"width,attr" json:"width"`
+	Height              int         `xml:"height,attr" json:"height"`
+	Codec               string      `xml:"codec,attr,omitempty" json:"codec,omitempty"`
+	Bitrate             int         `xml:"bitrate,attr,omitempty" json:"bitrate,omitempty"`
+	MinBitrate          int         `xml:"minBitrate,attr,omitempty" json:"minBitrate,omitempty"`
+	MaxBitrate          int         `xml:"maxBitrate,attr,omitempty" json:"maxBitrate,omitempty"`
+	Scalable            NumericBool `xml:"scalable,attr,omitempty" json:"scalable,omitempty"`
+	MaintainAspectRatio NumericBool `xml:"maintainAspectRatio,attr,omitempty" json:"maintainAspectRatio,omitempty"`
+	FileSize            int         `xml:"fileSize,attr,omitempty" json:"fileSize,omitempty"`
+	MediaType           string      `xml:"mediaType,attr,omitempty" json:"mediaType,omitempty"`
+	APIFramework        string      `xml:"apiFramework,attr,omitempty" json:"apiFramework,omitempty"`
 }
 
 type MediaFiles struct {
-	ClosedCaptionFiles      *ClosedCaptionFiles       `xml:"ClosedCaptionFiles,omitempty"`
-	MediaFile               []MediaFile               `xml:"MediaFile"`
-	Mezzanine               []Mezzanine               `xml:"Mezzanine,omitempty"`
-	InteractiveCreativeFile []InteractiveCreativeFile `xml:"InteractiveCreativeFile,omitempty"`
+	ClosedCaptionFiles      *ClosedCaptionFiles       `xml:"ClosedCaptionFiles,omitempty" json:"closedCaptionFiles,omitempty"`
+	MediaFile               []MediaFile               `xml:"MediaFile" json:"mediaFile"`
+	Mezzanine               []Mezzanine               `xml:"Mezzanine,omitempty" json:"mezzanine,omitempty"`
+	InteractiveCreativeFile []InteractiveCreativeFile `xml:"InteractiveCreativeFile,omitempty" json:"interactiveCreativeFile,omitempty"`
 }
 
 type Mezzanine struct {
-	Value     string   `xml:",cdata"`
-	Delivery  Delivery `xml:"delivery,attr"`
-	Type      string   `xml:"type,attr"`
-	Width     int      `xml:"width,attr"`
-	Height    int      `xml:"height,attr"`
-	Codec     string   `xml:"codec,attr,omitempty"`
-	FileSize  int      `xml:"fileSize,attr,omitempty"`
-	MediaType string   `xml:"mediaType,attr,omitempty"`
+	Value     string   `xml:",cdata" json:"value"`
+	Delivery  Delivery `xml:"delivery,attr" json:"delivery"`
+	Type      string   `xml:"type,attr" json:"type"`
+	Width     int      `xml:"width,attr" json:"width"`
+	Height    int      `xml:"height,attr" json:"height"`
+	Codec     string   `xml:"codec,attr,omitempty" json:"codec,omitempty"`
+	FileSize  int      `xml:"fileSize,attr,omitempty" json:"fileSize,omitempty"`
+	MediaType string   `xml:"mediaType,attr,omitempty" json:"mediaType,omitempty"`
 }
 
 type Model string
@@ -355,34 +361,34 @@ const (
 )
 
 type NonLinearAdInLine struct {
-	HTMLResource           []CData          `xml:"HTMLResource,omitempty"`
-	IFrameResource         []CData          `xml:"IFrameResource,omitempty"`
-	StaticResource         []StaticResource `xml:"StaticResource,omitempty"`
-	AdParameters           *AdParameters    `xml:"AdParameters,omitempty"`
-	NonLinearClickThrough  *CData           `xml:"NonLinearClickThrough,omitempty"`
-	NonLinearClickTracking []CData          `xml:"NonLinearClickTracking,omitempty"`
-	Width                  int              `xml:"width,attr"`
-	Height                 int              `xml:"height,attr"`
-	ExpandedWidth          int              `xml:"expandedWidth,attr,omitempty"`
-	ExpandedHeight         int              `xml:"expandedHeight,attr,omitempty"`
-	Scalable               NumericBool      `xml:"scalable,attr,omitempty"`
-	MaintainAspectRatio    NumericBool      `xml:"maintainAspectRatio,attr,omitempty"`
-	MinSuggestedDuration   Duration         `xml:"minSuggestedDuration,attr,omitempty"`
-	APIFramework           string           `xml:"apiFramework,attr,omitempty"`
+	HTMLResource           []CData          `xml:"HTMLResource,omitempty" json:"htmlResource,omitempty"`
+	IFrameResource         []CData          `xml:"IFrameResource,omitempty" json:"iFrameResource,omitempty"`
+	StaticResource         []StaticResource `xml:"StaticResource,omitempty" json:"staticResource,omitempty"`
+	AdParameters           *AdParameters    `xml:"AdParameters,omitempty" json:"adParameters,omitempty"`
+	NonLinearClickThrough  *CData           `xml:"NonLinearClickThrough,omitempty" json:"nonLinearClickThrough,omitempty"`
+	NonLinearClickTracking []CData          `xml:"NonLinearClickTracking,omitempty" json:"nonLinearClickTracking,omitempty"`
+	Width                  int              `xml:"width,attr" json:"width"`
+	Height                 int              `xml:"height,attr" json:"height"`
+	ExpandedWidth          int              `xml:"expandedWidth,attr,omitempty" json:"expandedWidth,omitempty"`
+	ExpandedHeight         int              `xml:"expandedHeight,attr,omitempty" json:"expandedHeight,omitempty"`
+	Scalable               NumericBool      `xml:"scalable,attr,omitempty" json:"scalable,omitempty"`
+	MaintainAspectRatio    NumericBool      `xml:"maintainAspectRatio,attr,omitempty" json:"maintainAspectRatio,omitempty"`
+	MinSuggestedDuration   Duration         `xml:"minSuggestedDuration,attr,omitempty" json:"minSuggestedDuration,omitempty"`
+	APIFramework           string           `xml:"apiFramework,attr,omitempty" json:"apiFramework,omitempty"`
 }
 
 type NonLinearAds struct {
-	TrackingEvents *TrackingEvents     `xml:"TrackingEvents,omitempty"`
-	NonLinear      []NonLinearAdInLine `xml:"NonLinear,omitempty"`
+	TrackingEvents *TrackingEvents     `xml:"TrackingEvents,omitempty" json:"trackingEvents,omitempty"`
+	NonLinear      []NonLinearAdInLine `xml:"NonLinear,omitempty" json:"nonLinear,omitempty"`
 }
 
 // Offset must match the pattern `(\d{2}:[0-5]\d:[0-5]\d(\.\d\d\d)?|1?\d?\d(\.?\d)*%)`.
 type Offset string
 
 type Pricing struct {
-	Value    float64  `xml:",cdata"`
-	Model    Model    `xml:"model,attr"`
-	Currency Currency `xml:"currency,attr"`
+	Value    float64  `xml:",cdata" json:"value"`
+	Model    Model    `xml:"model,attr" json:"model"`
+	Currency Currency `xml:"currency,attr" json:"currency"`
 }
 
 type RenderingMode string
@@ -405,32 +411,32 @@ const (
 type SkipOffset string
 
 type StaticResource struct {
-	Value        string `xml:",cdata"`
-	CreativeType string `xml:"creativeType,attr"`
+	Value        string `xml:",cdata" json:"value"`
+	CreativeType string `xml:"creativeType,attr" json:"creativeType"`
 }
 
 type Survey struct {
-	Value string `xml:",chardata"`
-	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata" json:"value"`
+	Type  string `xml:"type,attr,omitempty" json:"type,omitempty"`
 }
 
 type Tracking struct {
-	Value  string `xml:",cdata"`
-	Event  string `xml:"event,attr"`
-	Offset Offset `xml:"offset,attr,omitempty"`
+	Value  string `xml:",cdata" json:"value"`
+	Event  string `xml:"event,attr" json:"event"`
+	Offset Offset `xml:"offset,attr,omitempty" json:"offset,omitempty"`
 }
 
 type TrackingEventsVerification struct {
-	Tracking []Tracking `xml:"Tracking,omitempty"`
+	Tracking []Tracking `xml:"Tracking,omitempty" json:"tracking,omitempty"`
 }
 
 type TrackingEvents struct {
-	Tracking []Tracking `xml:"Tracking,omitempty"`
+	Tracking []Tracking `xml:"Tracking,omitempty" json:"tracking,omitempty"`
 }
 
 type UniversalAdID struct {
-	Value      string `xml:",chardata"`
-	IDRegistry string `xml:"idRegistry,attr"`
+	Value      string `xml:",chardata" json:"value"`
+	IDRegistry string `xml:"idRegistry,attr" json:"idRegistry"`
 }
 
 type Namespace string
@@ -446,14 +452,63 @@ var (
 type Version string
 
 const (
+	VAST20Version Version = "2.0"
+	VAST30Version Version = "3.0"
+	VAST40Version Version = "4.0"
+	VAST41Version Version = "4.1"
 	VAST42Version Version = "4.2"
+	VAST43Version Version = "4.3"
 )
 
+// Compare compares the version with another version numerically by major and minor version, e.g. `4` equals `4.0`
+// and `4.10` is after `4.2`. It returns -1 if the version is before the other version, 0 if they are equal and +1 if
+// it is after. Versions which cannot be parsed are before all other versions.
+func (v Version) Compare(other Version) int {
+	major, minor, ok := v.parse()
+	otherMajor, otherMinor, otherOK := other.parse()
+
+	switch {
+	case !ok && !otherOK:
+		return 0
+	case !ok:
+		return -1
+	case !otherOK:
+		return 1
+	case major != otherMajor:
+		return cmp.Compare(major, otherMajor)
+	default:
+		return cmp.Compare(minor, otherMinor)
+	}
+}
+
+// parse returns the major and minor version. The minor version is 0 if it is missing.
+func (v Version) parse() (int, int, bool) {
+	majorPart, minorPart, hasMinor := strings.Cut(strings.TrimSpace(string(v)), ".")
+
+	major, err := strconv.ParseUint(majorPart, 10, 31)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	if !hasMinor {
+		return int(major), 0, true
+	}
+
+	minor, err := strconv.ParseUint(minorPart, 10, 31)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return int(major), int(minor), true
+}
+
 type VAST struct {
-	Ad      []Ad      `xml:"Ad,omitempty"`
-	Error   []CData   `xml:"Error,omitempty"`
-	Version Version   `xml:"version,attr"`
-	XMLNS   Namespace `xml:"xmlns,attr,omitempty"`
+	Ad      []Ad      `xml:"Ad,omitempty" json:"ad,omitempty"`
+	Error   []CData   `xml:"Error,omitempty" json:"error,omitempty"`
+	Version Version   `xml:"version,attr" json:"version"`
+	XMLNS   Namespace `xml:"xmlns,attr,omitempty" json:"xmlns,omitempty"`
+	// Attributes are further attributes of the root element, e.g. namespace declarations and schema locations.
+	Attributes Attributes `xml:",any,attr" json:"attributes,omitempty"`
 }
 
 // New creates a new instance of VAST, sets the version to VAST42Version and the XML namespace to VASTNamespace.
@@ -474,7 +529,7 @@ func Read(reader io.ReadCloser) (*VAST, error) {
 	_ = reader.Close()
 
 	vast := &VAST{}
-	if err := xml.Unmarshal(body, vast); err != nil {
+	if err := decodeXML(body, vast); err != nil {
 		return nil, errors.Join(ErrUnmarshalVAST, err)
 	}
 
@@ -492,40 +547,41 @@ func (m *VAST) Bytes() ([]byte, error) {
 }
 
 type Verification struct {
-	ExecutableResource     []ExecutableResource        `xml:"ExecutableResource,omitempty"`
-	JavaScriptResource     []JavaScriptResource        `xml:"JavaScriptResource,omitempty"`
-	TrackingEvents         *TrackingEventsVerification `xml:"TrackingEvents,omitempty"`
-	VerificationParameters string                      `xml:"VerificationParameters,omitempty"`
-	Vendor                 string                      `xml:"vendor,attr,omitempty"`
+	ExecutableResource     []ExecutableResource        `xml:"ExecutableResource,omitempty" json:"executableResource,omitempty"`
+	JavaScriptResource     []JavaScriptResource        `xml:"JavaScriptResource,omitempty" json:"javaScriptResource,omitempty"`
+	TrackingEvents         *TrackingEventsVerification `xml:"TrackingEvents,omitempty" json:"trackingEvents,omitempty"`
+	VerificationParameters string                      `xml:"VerificationParameters,omitempty" json:"verificationParameters,omitempty"`
+	Vendor                 string                      `xml:"vendor,attr,omitempty" json:"vendor,omitempty"`
 }
 
 type ClickThrough struct {
-	Value string `xml:",cdata"`
-	ID    string `xml:"id,attr,omitempty"`
+	Value string `xml:",cdata" json:"value"`
+	ID    string `xml:"id,attr,omitempty" json:"id,omitempty"`
 }
 
 type VideoClicks struct {
-	ClickTracking []CData      `xml:"ClickTracking,omitempty"`
-	ClickThrough  ClickThrough `xml:"ClickThrough,omitempty"`
-	CustomClick   []string     `xml:"CustomClick,omitempty"`
+	ClickTracking []CData      `xml:"ClickTracking,omitempty" json:"clickTracking,omitempty"`
+	ClickThrough  ClickThrough `xml:"ClickThrough,omitempty" json:"clickThrough,omitempty"`
+	CustomClick   []string     `xml:"CustomClick,omitempty" json:"customClick,omitempty"`
 }
 
 type ViewableImpression struct {
-	Viewable         []CData `xml:"Viewable,omitempty"`
-	NotViewable      []CData `xml:"NotViewable,omitempty"`
-	ViewUndetermined []CData `xml:"ViewUndetermined,omitempty"`
+	Viewable         []CData `xml:"Viewable,omitempty" json:"viewable,omitempty"`
+	NotViewable      []CData `xml:"NotViewable,omitempty" json:"notViewable,omitempty"`
+	ViewUndetermined []CData `xml:"ViewUndetermined,omitempty" json:"viewUndetermined,omitempty"`
+	ID               string  `xml:"id,attr,omitempty" json:"id,omitempty"`
 }
 
 type Wrapper struct {
 	AdDefinitionBase
 
-	AdVerifications          *AdVerifications      `xml:"AdVerifications,omitempty"`
-	BlockedAdCategories      []BlockedAdCategories `xml:"BlockedAdCategories,omitempty"`
-	Creatives                *Creatives            `xml:"Creatives,omitempty"`
-	VASTAdTagURI             CData                 `xml:"VASTAdTagURI"`
-	FollowAdditionalWrappers NumericBool           `xml:"followAdditionalWrappers,attr,omitempty"`
-	AllowMultipleAds         NumericBool           `xml:"allowMultipleAds,attr,omitempty"`
-	FallbackOnNoAd           NumericBool           `xml:"fallbackOnNoAd,attr,omitempty"`
+	AdVerifications          *AdVerifications      `xml:"AdVerifications,omitempty" json:"adVerifications,omitempty"`
+	BlockedAdCategories      []BlockedAdCategories `xml:"BlockedAdCategories,omitempty" json:"blockedAdCategories,omitempty"`
+	Creatives                *Creatives            `xml:"Creatives,omitempty" json:"creatives,omitempty"`
+	VASTAdTagURI             CData                 `xml:"VASTAdTagURI" json:"vastAdTagURI"`
+	FollowAdditionalWrappers NumericBool           `xml:"followAdditionalWrappers,attr,omitempty" json:"followAdditionalWrappers,omitempty"`
+	AllowMultipleAds         NumericBool           `xml:"allowMultipleAds,attr,omitempty" json:"allowMultipleAds,omitempty"`
+	FallbackOnNoAd           NumericBool           `xml:"fallbackOnNoAd,attr,omitempty" json:"fallbackOnNoAd,omitempty"`
 }
 
 // XPosition must match the pattern `([0-9]*|left|right)`.
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go.eigsys.de/go-vast"
 	"log"
+	"net/http"
 )
 
 func ExampleNew() {
@@ -36,3 +37,28 @@ func ExampleVAST_Bytes() {
 	// Output: <?xml version="1.0" encoding="UTF-8"?>
 	// <VAST version="4.2" xmlns="http://www.iab.com/VAST"></VAST>
 }
+
+func ExampleVAST_JSON() {
+	example := vast.New()
+
+	exampleJSON, err := example.JSON()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	fmt.Printf("%s", exampleJSON)
+	// Output: {
+	//   "version": "4.2",
+	//   "xmlns": "http://www.iab.com/VAST"
+	// }
+}
+
+func ExampleNewHandler() {
+	handler := vast.NewHandler(func(r *http.Request) (*vast.VAST, error) {
+		return vast.New(), nil
+	})
+	handler.ErrorURIs = []string{"https://example.com/error?code=[ERRORCODE]"}
+
+	http.Handle("/vast", handler)
+	// Output:
+}
@@ -77,26 +77,26 @@ func TestRead_ErrReadVAST(t *testing.T) {
 	}
 }
 
-func TestRoundTrip(t *testing.T) {
-	testCases := []string{
-		"iab/Ad_Verification-test.xml",
-		"iab/Category-test.xml",
-		"iab/Closed_Caption_Test.xml",
-		"iab/Event_Tracking-test.xml",
-		"iab/IconClickFallbacks.xml",
-		"iab/Inline_Companion_Tag-test.xml",
-		"iab/Inline_Linear_Tag-test.xml",
-		"iab/Inline_Non-Linear_Tag-test.xml",
-		"iab/Inline_Simple.xml",
-		"iab/No_Wrapper_Tag-test.xml",
-		"iab/Ready_to_serve_Media_Files_check-test.xml",
-		"iab/Universal_Ad_ID-multi-test.xml",
-		"iab/Video_Clicks_and_click_tracking-Inline-test.xml",
-		"iab/Viewable_Impression-test.xml",
-		"iab/Wrapper_Tag-test.xml",
-	}
+var iabFixtures = []string{
+	"iab/Ad_Verification-test.xml",
+	"iab/Category-test.xml",
+	"iab/Closed_Caption_Test.xml",
+	"iab/Event_Tracking-test.xml",
+	"iab/IconClickFallbacks.xml",
+	"iab/Inline_Companion_Tag-test.xml",
+	"iab/Inline_Linear_Tag-test.xml",
+	"iab/Inline_Non-Linear_Tag-test.xml",
+	"iab/Inline_Simple.xml",
+	"iab/No_Wrapper_Tag-test.xml",
+	"iab/Ready_to_serve_Media_Files_check-test.xml",
+	"iab/Universal_Ad_ID-multi-test.xml",
+	"iab/Video_Clicks_and_click_tracking-Inline-test.xml",
+	"iab/Viewable_Impression-test.xml",
+	"iab/Wrapper_Tag-test.xml",
+}
 
-	for _, testCase := range testCases {
+func TestRoundTrip(t *testing.T) {
+	for _, testCase := range iabFixtures {
 		t.Run(testCase, func(t *testing.T) {
 			reader := mustOpenFixture(testCase)
 
@@ -0,0 +1,283 @@
+// Package vasttest provides an in-process ad server for testing video players.
+package vasttest
+
+import (
+	"fmt"
+	"go.eigsys.de/go-vast"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fault is a failure injected into a response.
+type Fault string
+
+const (
+	NoFault Fault = ""
+	// TimeoutFault never responds, until the client gives up or the server is closed.
+	TimeoutFault Fault = "timeout"
+	// NotFoundFault responds with 404 Not Found.
+	NotFoundFault Fault = "not-found"
+	// MalformedFault responds with a document which is not well-formed XML.
+	MalformedFault Fault = "malformed"
+	// EmptyFault responds with an empty VAST without ads, regardless of VAST and Body.
+	EmptyFault Fault = "empty"
+)
+
+// TrackingPath is the path prefix of tracking pixels.
+const TrackingPath = "/track/"
+
+// Response configures the response to an ad request.
+// Latency delays the response. Body is served instead of VAST if set, unless a fault is injected. A response without
+// VAST and Body is served as an empty VAST without ads, like EmptyFault.
+type Response struct {
+	VAST    *vast.VAST
+	Body    []byte
+	Latency time.Duration
+	Fault   Fault
+}
+
+// Hit is a recorded request.
+type Hit struct {
+	Path  string
+	Query url.Values
+	Time  time.Time
+}
+
+// Server is an ad server for tests, which serves configured responses and records ad requests and tracking pixel hits.
+// Requests to paths starting with TrackingPath are answered with a transparent pixel.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses map[string]Response
+	requests  []Hit
+	hits      []Hit
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewServer creates and starts a new instance of Server.
+func NewServer() *Server {
+	s := &Server{
+		responses: map[string]Response{},
+		closed:    make(chan struct{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Close releases pending requests and shuts down the server.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+	s.Server.Close()
+}
+
+// Handle configures the response to ad requests for a path.
+func (s *Server) Handle(path string, response Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[path] = response
+}
+
+// Endpoint returns the absolute URL of a path.
+func (s *Server) Endpoint(path string) string {
+	return s.URL + path
+}
+
+// TrackingURL returns the absolute URL of a tracking pixel.
+func (s *Server) TrackingURL(name string) string {
+	return s.Endpoint(TrackingPath + name)
+}
+
+// Chain serves an InLine VAST at path, preceded by a chain of wrappers which respond with latency each.
+// It returns the URL of the first wrapper, or of the InLine VAST if wrappers is 0.
+// The wrappers are served at path suffixed by `/wrapper-N` and fire the tracking pixel `wrapper-N-impression`.
+func (s *Server) Chain(path string, inLine *vast.VAST, wrappers int, latency time.Duration) string {
+	s.Handle(path, Response{VAST: inLine, Latency: latency})
+	target := s.Endpoint(path)
+
+	for i := wrappers; i > 0; i-- {
+		wrapperPath := fmt.Sprintf("%s/wrapper-%d", path, i)
+		wrapper := vast.New()
+		wrapper.Ad = []vast.Ad{{
+			ID: fmt.Sprintf("wrapper-%d", i),
+			Wrapper: &vast.Wrapper{
+				AdDefinitionBase: vast.AdDefinitionBase{
+					AdSystem:   vast.AdSystem{Value: "vasttest"},
+					Impression: []vast.Impression{{Value: s.TrackingURL(fmt.Sprintf("wrapper-%d-impression", i))}},
+				},
+				VASTAdTagURI: vast.CData{Value: target},
+			},
+		}}
+
+		s.Handle(wrapperPath, Response{VAST: wrapper, Latency: latency})
+		target = s.Endpoint(wrapperPath)
+	}
+
+	return target
+}
+
+// QuartileEvents are the tracking events added to the linear creative of InLine.
+var QuartileEvents = []vast.Event{
+	vast.StartEvent,
+	vast.FirstQuartileEvent,
+	vast.MidpointEvent,
+	vast.ThirdQuartileEvent,
+	vast.CompleteEvent,
+}
+
+// InLine creates an InLine VAST with a linear creative whose impression, error, click and quartile URLs point to
+// tracking pixels of the server, which are named after the event, e.g. `impression` or `firstQuartile`.
+func (s *Server) InLine(id string) *vast.VAST {
+	tracking := make([]vast.Tracking, 0, len(QuartileEvents))
+	for _, event := range QuartileEvents {
+		tracking = append(tracking, vast.Tracking{Event: string(event), Value: s.TrackingURL(string(event))})
+	}
+
+	inLine := vast.New()
+	inLine.Ad = []vast.Ad{{
+		ID: id,
+		InLine: &vast.InLine{
+			AdDefinitionBase: vast.AdDefinitionBase{
+				AdSystem:   vast.AdSystem{Value: "vasttest"},
+				Error:      []vast.CData{{Value: s.TrackingURL("error") + "?code=[ERRORCODE]"}},
+				Impression: []vast.Impression{{Value: s.TrackingURL("impression")}},
+			},
+			AdServingID: id,
+			AdTitle:     "vasttest " + id,
+			Creatives: vast.InLineCreatives{Creative: []vast.InLineCreative{{
+				CreativeBase:  vast.CreativeBase{AdID: id},
+				UniversalAdID: []vast.UniversalAdID{{IDRegistry: "unknown", Value: "unknown"}},
+				Linear: &vast.LinearInLine{
+					LinearBase: vast.LinearBase{TrackingEvents: &vast.TrackingEvents{Tracking: tracking}},
+					Duration:   "00:00:10",
+					MediaFiles: vast.MediaFiles{MediaFile: []vast.MediaFile{{
+						Value:    "https://example.com/vasttest.mp4",
+						Delivery: vast.ProgressiveDelivery,
+						Type:     "video/mp4",
+						Width:    640,
+						Height:   360,
+					}}},
+					VideoClicks: &vast.VideoClicks{
+						ClickThrough:  vast.ClickThrough{Value: "https://example.com/"},
+						ClickTracking: []vast.CData{{Value: s.TrackingURL("click")}},
+					},
+				},
+			}}},
+		},
+	}}
+
+	return inLine
+}
+
+// Requests returns all recorded ad requests.
+func (s *Server) Requests() []Hit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Hit(nil), s.requests...)
+}
+
+// Hits returns all recorded tracking pixel hits.
+func (s *Server) Hits() []Hit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Hit(nil), s.hits...)
+}
+
+// Fired returns how often the tracking pixel with the given name was hit.
+func (s *Server) Fired(name string) int {
+	count := 0
+
+	for _, hit := range s.Hits() {
+		if hit.Path == TrackingPath+name {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Reset deletes all recorded requests and hits.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+	s.hits = nil
+}
+
+// pixel is a transparent 1x1 GIF.
+var pixel = []byte("GIF89a\x01\x00\x01\x00\x80\x00\x00\x00\x00\x00\x00\x00\x00!\xf9\x04\x01\x00\x00\x00\x00,\x00\x00\x00\x00\x01\x00\x01\x00\x00\x02\x02D\x01\x00;")
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	hit := Hit{Path: r.URL.Path, Query: r.URL.Query(), Time: time.Now()}
+
+	s.mu.Lock()
+
+	if strings.HasPrefix(r.URL.Path, TrackingPath) {
+		s.hits = append(s.hits, hit)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "image/gif")
+		_, _ = w.Write(pixel)
+
+		return
+	}
+
+	s.requests = append(s.requests, hit)
+	response, ok := s.responses[r.URL.Path]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	select {
+	case <-time.After(response.Latency):
+	case <-r.Context().Done():
+		return
+	case <-s.closed:
+		return
+	}
+
+	switch response.Fault {
+	case TimeoutFault:
+		select {
+		case <-r.Context().Done():
+		case <-s.closed:
+		}
+
+		return
+	case NotFoundFault:
+		http.NotFound(w, r)
+		return
+	case MalformedFault:
+		response.Body = []byte(`<VAST version="4.2"><Ad><InLine>`)
+	case EmptyFault:
+		response.VAST, response.Body = vast.New(), nil
+	}
+
+	if response.Body != nil {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write(response.Body)
+
+		return
+	}
+
+	if response.VAST == nil {
+		response.VAST = vast.New()
+	}
+
+	vast.NewHandler(func(*http.Request) (*vast.VAST, error) {
+		return response.VAST, nil
+	}).ServeHTTP(w, r)
+}
@@ -0,0 +1,180 @@
+package vasttest_test
+
+import (
+	"context"
+	"errors"
+	"go.eigsys.de/go-vast"
+	"go.eigsys.de/go-vast/vasttest"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func fetch(t *testing.T, server *vasttest.Server, uri string, timeout time.Duration) (*vast.VAST, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return (&vast.HTTPFetcher{Client: server.Client()}).Fetch(ctx, uri)
+}
+
+func TestServer_Chain(t *testing.T) {
+	server := vasttest.NewServer()
+	defer server.Close()
+
+	uri := server.Chain("/ad", server.InLine("1"), 2, 10*time.Millisecond)
+	if uri != server.Endpoint("/ad/wrapper-1") {
+		t.Errorf("unexpected URI: %s", uri)
+	}
+
+	start := time.Now()
+
+	wrapper, err := fetch(t, server, uri, time.Second)
+	if err != nil {
+		t.Fatal("unexpected error")
+	}
+
+	resolver := vast.Resolver{Fetcher: &vast.HTTPFetcher{Client: server.Client()}}
+
+	ad, hops, err := resolver.Resolve(context.Background(), &wrapper.Ad[0])
+	if err != nil {
+		t.Fatal("unexpected error")
+	}
+
+	if len(hops) != 2 || time.Since(start) < 30*time.Millisecond {
+		t.Errorf("unexpected hops: %v", hops)
+	}
+
+	if len(server.Requests()) != 3 || server.Requests()[2].Path != "/ad" {
+		t.Errorf("unexpected requests: %v", server.Requests())
+	}
+
+	for _, impression := range ad.InLine.Impression {
+		response, err := server.Client().Get(impression.Value)
+		if err != nil {
+			t.Fatal("unexpected error")
+		}
+
+		_ = response.Body.Close()
+
+		if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "image/gif" {
+			t.Errorf("unexpected response: %d", response.StatusCode)
+		}
+	}
+
+	for _, name := range []string{"impression", "wrapper-1-impression", "wrapper-2-impression"} {
+		if server.Fired(name) != 1 {
+			t.Errorf("pixel %s not fired", name)
+		}
+	}
+
+	if server.Fired(string(vast.StartEvent)) != 0 {
+		t.Error("unexpected hit")
+	}
+
+	server.Reset()
+
+	if len(server.Hits()) != 0 || len(server.Requests()) != 0 {
+		t.Error("unexpected hits")
+	}
+}
+
+func TestServer_InLine(t *testing.T) {
+	server := vasttest.NewServer()
+	defer server.Close()
+
+	inLine := server.InLine("1")
+
+	if violations := inLine.Validate(); len(violations) != 0 {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+
+	tracking := inLine.Ad[0].InLine.Creatives.Creative[0].Linear.TrackingEvents.Tracking
+	if len(tracking) != len(vasttest.QuartileEvents) || tracking[1].Value != server.TrackingURL("firstQuartile") {
+		t.Errorf("unexpected tracking: %v", tracking)
+	}
+}
+
+func TestServer_faults(t *testing.T) {
+	server := vasttest.NewServer()
+	defer server.Close()
+
+	server.Handle("/timeout", vasttest.Response{Fault: vasttest.TimeoutFault})
+	server.Handle("/not-found", vasttest.Response{Fault: vasttest.NotFoundFault})
+	server.Handle("/malformed", vasttest.Response{Fault: vasttest.MalformedFault})
+	server.Handle("/empty", vasttest.Response{VAST: server.InLine("1"), Fault: vasttest.EmptyFault})
+	server.Handle("/empty-body", vasttest.Response{Body: []byte(`<VAST version="3.0"></VAST>`), Fault: vasttest.EmptyFault})
+	server.Handle("/latency", vasttest.Response{VAST: server.InLine("1"), Latency: time.Second})
+
+	if _, err := fetch(t, server, server.Endpoint("/timeout"), 50*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := fetch(t, server, server.Endpoint("/latency"), 50*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, path := range []string{"/not-found", "/unknown"} {
+		if _, err := fetch(t, server, server.Endpoint(path), time.Second); !errors.Is(err, vast.ErrFetchVAST) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := fetch(t, server, server.Endpoint("/malformed"), time.Second); !errors.Is(err, vast.ErrUnmarshalVAST) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, path := range []string{"/empty", "/empty-body"} {
+		v, err := fetch(t, server, server.Endpoint(path), time.Second)
+		if err != nil || len(v.Ad) != 0 || v.Version != vast.VAST42Version {
+			t.Errorf("unexpected response: %v %v", v, err)
+		}
+	}
+}
+
+func TestServer_noVAST(t *testing.T) {
+	server := vasttest.NewServer()
+	defer server.Close()
+
+	server.Handle("/none", vasttest.Response{})
+
+	v, err := fetch(t, server, server.Endpoint("/none"), time.Second)
+	if err != nil || len(v.Ad) != 0 || v.Version != vast.VAST42Version {
+		t.Errorf("unexpected response: %v %v", v, err)
+	}
+}
+
+func TestServer_Close(t *testing.T) {
+	server := vasttest.NewServer()
+	server.Handle("/timeout", vasttest.Response{Fault: vasttest.TimeoutFault})
+
+	done := make(chan error)
+
+	go func() {
+		_, err := fetch(t, server, server.Endpoint("/timeout"), time.Minute)
+		done <- err
+	}()
+
+	for len(server.Requests()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	server.Close()
+
+	if err := <-done; !errors.Is(err, vast.ErrFetchVAST) && !errors.Is(err, vast.ErrReadVAST) && !errors.Is(err, vast.ErrUnmarshalVAST) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestServer_Body(t *testing.T) {
+	server := vasttest.NewServer()
+	defer server.Close()
+
+	server.Handle("/body", vasttest.Response{Body: []byte(`<VAST version="3.0"></VAST>`)})
+
+	v, err := fetch(t, server, server.Endpoint("/body"), time.Second)
+	if err != nil || v.Version != vast.VAST30Version {
+		t.Errorf("unexpected response: %v %v", v, err)
+	}
+}
@@ -0,0 +1,377 @@
+package vast
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSkipSubtree is returned by a Visitor callback to skip the children of the visited node.
+var ErrSkipSubtree = errors.New("skip subtree")
+
+// PathElement is an XML element of a Path. Index is the 1-based position of repeated elements and 0 otherwise.
+// Node is a pointer to the element, e.g. *Ad or *Creatives.
+type PathElement struct {
+	Name  string
+	Index int
+	Node  any
+}
+
+// Path is the ancestry of a node, starting at the VAST root element and ending with the node itself.
+type Path []PathElement
+
+// String formats the path like `/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear`.
+func (p Path) String() string {
+	var builder strings.Builder
+
+	for _, element := range p {
+		builder.WriteString("/" + element.Name)
+
+		if element.Index > 0 {
+			_, _ = fmt.Fprintf(&builder, "[%d]", element.Index)
+		}
+	}
+
+	return builder.String()
+}
+
+// Visitor has a callback per node kind. Callbacks which are nil are not called.
+// The path is only valid during the callback, it must be cloned to be retained.
+// Callbacks may modify the node, its children are visited after the callback returns.
+type Visitor struct {
+	VAST              func(*VAST, Path) error
+	Ad                func(*Ad, Path) error
+	InLine            func(*InLine, Path) error
+	Wrapper           func(*Wrapper, Path) error
+	InLineCreative    func(*InLineCreative, Path) error
+	WrapperCreative   func(*WrapperCreative, Path) error
+	LinearInLine      func(*LinearInLine, Path) error
+	LinearWrapper     func(*LinearWrapper, Path) error
+	NonLinearAds      func(*NonLinearAds, Path) error
+	NonLinear         func(*NonLinearAdInLine, Path) error
+	CompanionAds      func(*CompanionAdsCollection, Path) error
+	Companion         func(*CompanionAd, Path) error
+	Icon              func(*Icon, Path) error
+	MediaFile         func(*MediaFile, Path) error
+	Tracking          func(*Tracking, Path) error
+	Verification      func(*Verification, Path) error
+	Extension         func(*Extension, Path) error
+	CreativeExtension func(*CreativeExtension, Path) error
+}
+
+// Walk visits the nodes of the VAST depth-first and calls the callbacks of the visitor.
+// If a callback returns ErrSkipSubtree, the children of the node are skipped. Any other error stops the walk and is
+// returned. Nothing is visited if the VAST is nil.
+func Walk(v *VAST, visitor Visitor) error {
+	if v == nil {
+		return nil
+	}
+
+	w := &walker{visitor: visitor}
+
+	return visit(w, visitor.VAST, v, "VAST", 0, func() error {
+		for i := 0; i < len(v.Ad); i++ {
+			if err := w.ad(&v.Ad[i], i+1); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+type walker struct {
+	visitor Visitor
+	path    Path
+}
+
+func visit[T any](w *walker, callback func(*T, Path) error, node *T, name string, index int, children func() error) error {
+	w.path = append(w.path, PathElement{Name: name, Index: index, Node: node})
+	defer func() { w.path = w.path[:len(w.path)-1] }()
+
+	if callback != nil {
+		if err := callback(node, w.path); err != nil {
+			if errors.Is(err, ErrSkipSubtree) {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	if children == nil {
+		return nil
+	}
+
+	return children()
+}
+
+// container adds an element without callback to the path.
+func (w *walker) container(name string, node any, children func() error) error {
+	w.path = append(w.path, PathElement{Name: name, Node: node})
+	defer func() { w.path = w.path[:len(w.path)-1] }()
+
+	return children()
+}
+
+func (w *walker) ad(ad *Ad, index int) error {
+	return visit(w, w.visitor.Ad, ad, "Ad", index, func() error {
+		if ad.InLine != nil {
+			if err := w.inLine(ad.InLine); err != nil {
+				return err
+			}
+		}
+
+		if ad.Wrapper != nil {
+			return w.wrapper(ad.Wrapper)
+		}
+
+		return nil
+	})
+}
+
+func (w *walker) inLine(inLine *InLine) error {
+	return visit(w, w.visitor.InLine, inLine, "InLine", 0, func() error {
+		if err := w.verifications(inLine.AdVerifications); err != nil {
+			return err
+		}
+
+		err := w.container("Creatives", &inLine.Creatives, func() error {
+			for i := 0; i < len(inLine.Creatives.Creative); i++ {
+				if err := w.inLineCreative(&inLine.Creatives.Creative[i], i+1); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return w.extensions(inLine.Extensions)
+	})
+}
+
+func (w *walker) wrapper(wrapper *Wrapper) error {
+	return visit(w, w.visitor.Wrapper, wrapper, "Wrapper", 0, func() error {
+		if err := w.verifications(wrapper.AdVerifications); err != nil {
+			return err
+		}
+
+		if wrapper.Creatives != nil {
+			err := w.container("Creatives", wrapper.Creatives, func() error {
+				for i := 0; i < len(wrapper.Creatives.Creative); i++ {
+					if err := w.wrapperCreative(&wrapper.Creatives.Creative[i], i+1); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return w.extensions(wrapper.Extensions)
+	})
+}
+
+func (w *walker) inLineCreative(creative *InLineCreative, index int) error {
+	return visit(w, w.visitor.InLineCreative, creative, "Creative", index, func() error {
+		if err := w.creativeExtensions(creative.CreativeExtensions); err != nil {
+			return err
+		}
+
+		if creative.Linear != nil {
+			err := visit(w, w.visitor.LinearInLine, creative.Linear, "Linear", 0, func() error {
+				if err := w.linear(&creative.Linear.LinearBase); err != nil {
+					return err
+				}
+
+				return w.container("MediaFiles", &creative.Linear.MediaFiles, func() error {
+					mediaFiles := &creative.Linear.MediaFiles
+					for i := 0; i < len(mediaFiles.MediaFile); i++ {
+						if err := visit(w, w.visitor.MediaFile, &mediaFiles.MediaFile[i], "MediaFile", i+1, nil); err != nil {
+							return err
+						}
+					}
+
+					return nil
+				})
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := w.nonLinearAds(creative.NonLinearAds); err != nil {
+			return err
+		}
+
+		return w.companionAds(creative.CompanionAds)
+	})
+}
+
+func (w *walker) wrapperCreative(creative *WrapperCreative, index int) error {
+	return visit(w, w.visitor.WrapperCreative, creative, "Creative", index, func() error {
+		if creative.Linear != nil {
+			err := visit(w, w.visitor.LinearWrapper, creative.Linear, "Linear", 0, func() error {
+				return w.linear(&creative.Linear.LinearBase)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := w.nonLinearAds(creative.NonLinearAds); err != nil {
+			return err
+		}
+
+		return w.companionAds(creative.CompanionAds)
+	})
+}
+
+func (w *walker) linear(linear *LinearBase) error {
+	if linear.Icons != nil {
+		err := w.container("Icons", linear.Icons, func() error {
+			for i := 0; i < len(linear.Icons.Icon); i++ {
+				if err := visit(w, w.visitor.Icon, &linear.Icons.Icon[i], "Icon", i+1, nil); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if linear.TrackingEvents == nil {
+		return nil
+	}
+
+	return w.trackingEvents(linear.TrackingEvents, &linear.TrackingEvents.Tracking)
+}
+
+func (w *walker) nonLinearAds(nonLinearAds *NonLinearAds) error {
+	if nonLinearAds == nil {
+		return nil
+	}
+
+	return visit(w, w.visitor.NonLinearAds, nonLinearAds, "NonLinearAds", 0, func() error {
+		if nonLinearAds.TrackingEvents != nil {
+			if err := w.trackingEvents(nonLinearAds.TrackingEvents, &nonLinearAds.TrackingEvents.Tracking); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(nonLinearAds.NonLinear); i++ {
+			if err := visit(w, w.visitor.NonLinear, &nonLinearAds.NonLinear[i], "NonLinear", i+1, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (w *walker) companionAds(companionAds *CompanionAdsCollection) error {
+	if companionAds == nil {
+		return nil
+	}
+
+	return visit(w, w.visitor.CompanionAds, companionAds, "CompanionAds", 0, func() error {
+		for i := 0; i < len(companionAds.Companion); i++ {
+			companion := &companionAds.Companion[i]
+
+			err := visit(w, w.visitor.Companion, companion, "Companion", i+1, func() error {
+				if err := w.creativeExtensions(companion.CreativeExtensions); err != nil {
+					return err
+				}
+
+				if companion.TrackingEvents == nil {
+					return nil
+				}
+
+				return w.trackingEvents(companion.TrackingEvents, &companion.TrackingEvents.Tracking)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (w *walker) trackingEvents(node any, tracking *[]Tracking) error {
+	return w.container("TrackingEvents", node, func() error {
+		for i := 0; i < len(*tracking); i++ {
+			if err := visit(w, w.visitor.Tracking, &(*tracking)[i], "Tracking", i+1, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (w *walker) verifications(adVerifications *AdVerifications) error {
+	if adVerifications == nil {
+		return nil
+	}
+
+	return w.container("AdVerifications", adVerifications, func() error {
+		for i := 0; i < len(adVerifications.Verification); i++ {
+			verification := &adVerifications.Verification[i]
+
+			err := visit(w, w.visitor.Verification, verification, "Verification", i+1, func() error {
+				if verification.TrackingEvents == nil {
+					return nil
+				}
+
+				return w.trackingEvents(verification.TrackingEvents, &verification.TrackingEvents.Tracking)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (w *walker) extensions(extensions *Extensions) error {
+	if extensions == nil {
+		return nil
+	}
+
+	return w.container("Extensions", extensions, func() error {
+		for i := 0; i < len(extensions.Extension); i++ {
+			if err := visit(w, w.visitor.Extension, &extensions.Extension[i], "Extension", i+1, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (w *walker) creativeExtensions(creativeExtensions *CreativeExtensions) error {
+	if creativeExtensions == nil {
+		return nil
+	}
+
+	return w.container("CreativeExtensions", creativeExtensions, func() error {
+		for i := 0; i < len(creativeExtensions.CreativeExtension); i++ {
+			if err := visit(w, w.visitor.CreativeExtension, &creativeExtensions.CreativeExtension[i], "CreativeExtension", i+1, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,192 @@
+package vast_test
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"go.eigsys.de/go-vast"
+	"testing"
+)
+
+func recordingVisitor(paths *[]string) vast.Visitor {
+	record := func(path vast.Path) error {
+		*paths = append(*paths, path.String())
+		return nil
+	}
+
+	return vast.Visitor{
+		VAST:              func(_ *vast.VAST, path vast.Path) error { return record(path) },
+		Ad:                func(_ *vast.Ad, path vast.Path) error { return record(path) },
+		InLine:            func(_ *vast.InLine, path vast.Path) error { return record(path) },
+		Wrapper:           func(_ *vast.Wrapper, path vast.Path) error { return record(path) },
+		InLineCreative:    func(_ *vast.InLineCreative, path vast.Path) error { return record(path) },
+		WrapperCreative:   func(_ *vast.WrapperCreative, path vast.Path) error { return record(path) },
+		LinearInLine:      func(_ *vast.LinearInLine, path vast.Path) error { return record(path) },
+		LinearWrapper:     func(_ *vast.LinearWrapper, path vast.Path) error { return record(path) },
+		NonLinearAds:      func(_ *vast.NonLinearAds, path vast.Path) error { return record(path) },
+		NonLinear:         func(_ *vast.NonLinearAdInLine, path vast.Path) error { return record(path) },
+		CompanionAds:      func(_ *vast.CompanionAdsCollection, path vast.Path) error { return record(path) },
+		Companion:         func(_ *vast.CompanionAd, path vast.Path) error { return record(path) },
+		Icon:              func(_ *vast.Icon, path vast.Path) error { return record(path) },
+		MediaFile:         func(_ *vast.MediaFile, path vast.Path) error { return record(path) },
+		Tracking:          func(_ *vast.Tracking, path vast.Path) error { return record(path) },
+		Verification:      func(_ *vast.Verification, path vast.Path) error { return record(path) },
+		Extension:         func(_ *vast.Extension, path vast.Path) error { return record(path) },
+		CreativeExtension: func(_ *vast.CreativeExtension, path vast.Path) error { return record(path) },
+	}
+}
+
+func TestWalk(t *testing.T) {
+	testVAST := trackerVAST()
+	testVAST.Ad[0].InLine.AdVerifications = &vast.AdVerifications{Verification: []vast.Verification{{
+		TrackingEvents: &vast.TrackingEventsVerification{Tracking: []vast.Tracking{{Event: "verificationNotExecuted"}}},
+	}}}
+	testVAST.Ad[0].InLine.Extensions = &vast.Extensions{Extension: []vast.Extension{{}}}
+	testVAST.Ad[0].InLine.Creatives.Creative[0].CreativeExtensions = &vast.CreativeExtensions{CreativeExtension: []vast.CreativeExtension{{}}}
+	testVAST.Ad[0].InLine.Creatives.Creative[0].Linear.MediaFiles.MediaFile = []vast.MediaFile{{}, {}}
+	testVAST.Ad[1].Wrapper.Creatives.Creative[0].Linear.Icons = &vast.Icons{Icon: []vast.Icon{{}}}
+	testVAST.Ad[1].Wrapper.Creatives.Creative = append(testVAST.Ad[1].Wrapper.Creatives.Creative, vast.WrapperCreative{
+		NonLinearAds: &vast.NonLinearAds{},
+		CompanionAds: &vast.CompanionAdsCollection{Companion: []vast.CompanionAd{{}}},
+	})
+	testVAST.Ad[1].Wrapper.Extensions = &vast.Extensions{Extension: []vast.Extension{{}}}
+
+	var paths []string
+
+	if err := vast.Walk(testVAST, recordingVisitor(&paths)); err != nil {
+		t.Error("unexpected error")
+	}
+
+	expected := []string{
+		"/VAST",
+		"/VAST/Ad[1]",
+		"/VAST/Ad[1]/InLine",
+		"/VAST/Ad[1]/InLine/AdVerifications/Verification[1]",
+		"/VAST/Ad[1]/InLine/AdVerifications/Verification[1]/TrackingEvents/Tracking[1]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[1]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[1]/CreativeExtensions/CreativeExtension[1]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/Icons/Icon[1]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/TrackingEvents/Tracking[1]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/TrackingEvents/Tracking[2]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/MediaFiles/MediaFile[1]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[1]/Linear/MediaFiles/MediaFile[2]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[2]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[2]/NonLinearAds",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[2]/NonLinearAds/TrackingEvents/Tracking[1]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[2]/NonLinearAds/NonLinear[1]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[3]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[3]/CompanionAds",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[3]/CompanionAds/Companion[1]",
+		"/VAST/Ad[1]/InLine/Creatives/Creative[3]/CompanionAds/Companion[1]/TrackingEvents/Tracking[1]",
+		"/VAST/Ad[1]/InLine/Extensions/Extension[1]",
+		"/VAST/Ad[2]",
+		"/VAST/Ad[2]/Wrapper",
+		"/VAST/Ad[2]/Wrapper/Creatives/Creative[1]",
+		"/VAST/Ad[2]/Wrapper/Creatives/Creative[1]/Linear",
+		"/VAST/Ad[2]/Wrapper/Creatives/Creative[1]/Linear/Icons/Icon[1]",
+		"/VAST/Ad[2]/Wrapper/Creatives/Creative[2]",
+		"/VAST/Ad[2]/Wrapper/Creatives/Creative[2]/NonLinearAds",
+		"/VAST/Ad[2]/Wrapper/Creatives/Creative[2]/CompanionAds",
+		"/VAST/Ad[2]/Wrapper/Creatives/Creative[2]/CompanionAds/Companion[1]",
+		"/VAST/Ad[2]/Wrapper/Extensions/Extension[1]",
+	}
+
+	if diff := cmp.Diff(expected, paths); diff != "" {
+		t.Errorf("wrong paths: %s", diff)
+	}
+}
+
+func TestWalk_fixtures(t *testing.T) {
+	for _, testCase := range iabFixtures {
+		t.Run(testCase, func(t *testing.T) {
+			var paths []string
+
+			if err := vast.Walk(mustReadFixture(t, testCase), recordingVisitor(&paths)); err != nil || paths[0] != "/VAST" {
+				t.Errorf("unexpected result: %v %v", paths, err)
+			}
+		})
+	}
+}
+
+func TestWalk_skipSubtree(t *testing.T) {
+	var paths []string
+
+	visitor := recordingVisitor(&paths)
+	visitor.InLine = func(*vast.InLine, vast.Path) error {
+		return vast.ErrSkipSubtree
+	}
+
+	if err := vast.Walk(trackerVAST(), visitor); err != nil {
+		t.Error("unexpected error")
+	}
+
+	expected := []string{
+		"/VAST",
+		"/VAST/Ad[1]",
+		"/VAST/Ad[2]",
+		"/VAST/Ad[2]/Wrapper",
+		"/VAST/Ad[2]/Wrapper/Creatives/Creative[1]",
+		"/VAST/Ad[2]/Wrapper/Creatives/Creative[1]/Linear",
+	}
+
+	if diff := cmp.Diff(expected, paths); diff != "" {
+		t.Errorf("wrong paths: %s", diff)
+	}
+}
+
+func TestWalk_error(t *testing.T) {
+	errStop := errors.New("stop")
+	visits := 0
+
+	err := vast.Walk(trackerVAST(), vast.Visitor{
+		Tracking: func(*vast.Tracking, vast.Path) error {
+			visits++
+			return errStop
+		},
+	})
+
+	if !errors.Is(err, errStop) || visits != 1 {
+		t.Errorf("unexpected result: %v %d", err, visits)
+	}
+}
+
+func TestWalk_nil(t *testing.T) {
+	err := vast.Walk(nil, vast.Visitor{
+		VAST: func(*vast.VAST, vast.Path) error {
+			t.Error("unexpected visit")
+			return nil
+		},
+	})
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWalk_mutate(t *testing.T) {
+	testVAST := trackerVAST()
+
+	err := vast.Walk(testVAST, vast.Visitor{
+		LinearInLine: func(linear *vast.LinearInLine, path vast.Path) error {
+			if _, ok := path[len(path)-2].Node.(*vast.InLineCreative); !ok {
+				t.Errorf("unexpected parent: %v", path)
+			}
+
+			linear.MediaFiles.MediaFile = append(linear.MediaFiles.MediaFile, vast.MediaFile{Value: "https://example.com/video.mp4"})
+
+			return nil
+		},
+		MediaFile: func(mediaFile *vast.MediaFile, _ vast.Path) error {
+			mediaFile.Type = "video/mp4"
+			return nil
+		},
+	})
+	if err != nil {
+		t.Error("unexpected error")
+	}
+
+	expected := []vast.MediaFile{{Value: "https://example.com/video.mp4", Type: "video/mp4"}}
+	if diff := cmp.Diff(expected, testVAST.Ad[0].InLine.Creatives.Creative[0].Linear.MediaFiles.MediaFile); diff != "" {
+		t.Errorf("wrong VAST: %s", diff)
+	}
+}